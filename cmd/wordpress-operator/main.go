@@ -31,6 +31,8 @@ import (
 	"github.com/bitpoke/wordpress-operator/pkg/apis"
 	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
 	"github.com/bitpoke/wordpress-operator/pkg/controller"
+	"github.com/bitpoke/wordpress-operator/pkg/gitwebhook"
+	"github.com/bitpoke/wordpress-operator/pkg/webhook"
 )
 
 const genericErrorExitCode = 1
@@ -60,6 +62,8 @@ func main() {
 		LeaderElectionResourceLock: "leases",
 		MetricsBindAddress:         options.MetricsBindAddress,
 		HealthProbeBindAddress:     options.HealthProbeBindAddress,
+		Port:                       options.WebhookPort,
+		CertDir:                    options.WebhookCertDir,
 	}
 
 	if options.WatchNamespace != "" {
@@ -85,6 +89,27 @@ func main() {
 		os.Exit(genericErrorExitCode)
 	}
 
+	ctx := signals.SetupSignalHandler()
+
+	if options.EnableWebhooks {
+		if err := webhook.SetupCertificates(ctx, mgr); err != nil {
+			setupLog.Error(err, "unable to setup webhook certificates")
+			os.Exit(genericErrorExitCode)
+		}
+
+		if err := webhook.AddToManager(mgr); err != nil {
+			setupLog.Error(err, "unable to setup webhooks")
+			os.Exit(genericErrorExitCode)
+		}
+	}
+
+	if options.GitWebhookBindAddress != "" {
+		if err := gitwebhook.AddToManager(mgr); err != nil {
+			setupLog.Error(err, "unable to setup the git webhook receiver")
+			os.Exit(genericErrorExitCode)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -96,7 +121,7 @@ func main() {
 	}
 
 	// Start the Cmd
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "unable to start the manager")
 		os.Exit(genericErrorExitCode)
 	}
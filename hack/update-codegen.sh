@@ -0,0 +1,58 @@
+#!/usr/bin/env bash
+# Generates the typed clientset, listers and informers for the Wordpress API
+# into pkg/client.
+#
+# The code generators are expected to be found in PATH or to be passed in
+# through the CLIENT_GEN, LISTER_GEN and INFORMER_GEN environment variables.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+
+CLIENT_GEN="${CLIENT_GEN:-client-gen}"
+LISTER_GEN="${LISTER_GEN:-lister-gen}"
+INFORMER_GEN="${INFORMER_GEN:-informer-gen}"
+
+MODULE="github.com/bitpoke/wordpress-operator"
+APIS_PKG="${MODULE}/pkg/apis"
+OUTPUT_PKG="${MODULE}/pkg/client"
+GROUP_VERSIONS="wordpress/v1alpha1 wordpress/v1beta1"
+HEADER="${ROOT}/hack/boilerplate.go.txt"
+
+INPUT_DIRS=""
+for gv in ${GROUP_VERSIONS}; do
+    INPUT_DIRS="${INPUT_DIRS:+${INPUT_DIRS},}${APIS_PKG}/${gv}"
+done
+
+OUTPUT_BASE="$(mktemp -d)"
+trap 'rm -rf "${OUTPUT_BASE}"' EXIT
+
+cd "${ROOT}"
+
+"${CLIENT_GEN}" \
+    --clientset-name versioned \
+    --input-base "" \
+    --input "${INPUT_DIRS}" \
+    --output-package "${OUTPUT_PKG}/clientset" \
+    --output-base "${OUTPUT_BASE}" \
+    --go-header-file "${HEADER}"
+
+"${LISTER_GEN}" \
+    --input-dirs "${INPUT_DIRS}" \
+    --output-package "${OUTPUT_PKG}/listers" \
+    --output-base "${OUTPUT_BASE}" \
+    --go-header-file "${HEADER}"
+
+"${INFORMER_GEN}" \
+    --input-dirs "${INPUT_DIRS}" \
+    --versioned-clientset-package "${OUTPUT_PKG}/clientset/versioned" \
+    --listers-package "${OUTPUT_PKG}/listers" \
+    --output-package "${OUTPUT_PKG}/informers" \
+    --output-base "${OUTPUT_BASE}" \
+    --go-header-file "${HEADER}"
+
+rm -rf pkg/client/clientset pkg/client/listers pkg/client/informers
+mkdir -p pkg/client
+cp -R "${OUTPUT_BASE}/${OUTPUT_PKG}/." pkg/client/
@@ -18,8 +18,11 @@ package v1alpha1
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // SecretRef represents a reference to a Secret.
@@ -36,6 +39,16 @@ type RouteSpec struct {
 	// The path for the route. Defaults to /.
 	// +optional
 	Path string `json:"path"`
+	// TLSSecretRef is a secret containing the TLS certificate for the route's
+	// domain. Defaults to the site's tlsSecretRef. The routes of a domain use
+	// the secret of the first of them.
+	// +optional
+	TLSSecretRef SecretRef `json:"tlsSecretRef,omitempty"`
+	// IngressGroup is the name of the spec.ingress.groups entry whose
+	// Ingress exposes the route. By default, the route is exposed by the
+	// site's Ingress.
+	// +optional
+	IngressGroup string `json:"ingressGroup,omitempty"`
 }
 
 // WordpressConditionType defines condition types of a backup resources.
@@ -66,6 +79,96 @@ const (
 
 	// WPCronTriggeringReason is the reason for successfully triggering wp-cron.
 	WPCronTriggeringReason = "WPCronTriggering"
+
+	// DBUpgradedCondition signals the state of the database upgrade for the current image.
+	DBUpgradedCondition WordpressConditionType = "DBUpgraded"
+
+	// DBUpgradeInProgressReason is the reason used while the database upgrade job is running.
+	DBUpgradeInProgressReason = "DBUpgradeInProgress"
+
+	// DBUpgradeFailedReason is the reason used when the database upgrade job failed.
+	DBUpgradeFailedReason = "DBUpgradeFailed"
+
+	// DBUpgradeCompletedReason is the reason used when the database upgrade job succeeded.
+	DBUpgradeCompletedReason = "DBUpgradeCompleted"
+
+	// RouteConflictCondition signals that some of the site's routes are claimed by other sites.
+	RouteConflictCondition WordpressConditionType = "RouteConflict"
+
+	// RouteClaimedReason is the reason used when routes are already claimed by other sites.
+	RouteClaimedReason = "RouteClaimed"
+
+	// NoRouteConflictReason is the reason used when none of the routes are claimed by other sites.
+	NoRouteConflictReason = "NoRouteConflict"
+
+	// TLSReadyCondition signals that the site's cert-manager certificates are issued.
+	TLSReadyCondition WordpressConditionType = "TLSReady"
+
+	// CertificatesReadyReason is the reason used when all the site's certificates are issued.
+	CertificatesReadyReason = "CertificatesReady"
+
+	// CertificatesPendingReason is the reason used while some of the site's certificates are not issued.
+	CertificatesPendingReason = "CertificatesPending"
+
+	// CertManagerNotInstalledReason is the reason used when the site's certificates should be
+	// issued with cert-manager, but cert-manager is not installed.
+	CertManagerNotInstalledReason = "CertManagerNotInstalled"
+
+	// ReadyCondition signals that all the site's components are up to date and available.
+	ReadyCondition WordpressConditionType = "Ready"
+
+	// ProgressingCondition signals that the site's components are being rolled out.
+	ProgressingCondition WordpressConditionType = "Progressing"
+
+	// DegradedCondition signals that some of the site's components failed.
+	DegradedCondition WordpressConditionType = "Degraded"
+
+	// ReadyReason is the reason used when the site is ready.
+	ReadyReason = "Ready"
+
+	// RolloutCompleteReason is the reason used when all the site's components are rolled out.
+	RolloutCompleteReason = "RolloutComplete"
+
+	// AsExpectedReason is the reason used when none of the site's components failed.
+	AsExpectedReason = "AsExpected"
+
+	// DeploymentProgressingReason is the reason used while the web deployment is rolling out.
+	DeploymentProgressingReason = "DeploymentProgressing"
+
+	// ProgressDeadlineExceededReason is the reason used when the web deployment exceeded its progress deadline.
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+	// ReplicaFailureReason is the reason used when the web deployment fails to create pods.
+	ReplicaFailureReason = "ReplicaFailure"
+
+	// PVCPendingReason is the reason used while a volume claim is not bound.
+	PVCPendingReason = "PVCPending"
+
+	// PVCLostReason is the reason used when a volume claim lost its volume.
+	PVCLostReason = "PVCLost"
+
+	// IngressPendingReason is the reason used while the ingress is not admitted by an ingress controller.
+	IngressPendingReason = "IngressPending"
+
+	// HTTPRoutePendingReason is the reason used while the HTTPRoute is not accepted by its Gateway.
+	HTTPRoutePendingReason = "HTTPRoutePending"
+
+	// GatewayAPINotInstalledReason is the reason used when the site is routed through a Gateway,
+	// but the Gateway API is not installed.
+	GatewayAPINotInstalledReason = "GatewayAPINotInstalled"
+
+	// GatewayNotSetReason is the reason used when the site is routed through a Gateway, but
+	// neither the site nor the operator name one.
+	GatewayNotSetReason = "GatewayNotSet"
+
+	// GitCloneFailedReason is the reason used when cloning the code from git fails.
+	GitCloneFailedReason = "GitCloneFailed"
+
+	// InstallFailedReason is the reason used when installing WordPress fails.
+	InstallFailedReason = "InstallFailed"
+
+	// RestoringReason is the reason used when the site is being restored from a backup.
+	RestoringReason = "Restoring"
 )
 
 // WordpressSpec defines the desired state of Wordpress.
@@ -101,8 +204,23 @@ type WordpressSpec struct {
 	// TLSSecretRef a secret containing the TLS certificates for this site.
 	// +optional
 	TLSSecretRef SecretRef `json:"tlsSecretRef,omitempty"`
+	// CertManager enables issuing the TLS certificates of the site with
+	// cert-manager. The routes without a TLS secret use <name>-tls, for which
+	// a Certificate covering their domains is created. The TLS secrets set by
+	// the user are left alone.
+	// +optional
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
 	// DeploymentStrategy allows setting the deployment strategy for the WordPress site
 	DeploymentStrategy *appsv1.DeploymentStrategy `json:"deploymentStrategy,omitempty"`
+	// DisruptionBudget creates a PodDisruptionBudget for the web pods. A
+	// budget which allows no disruptions blocks node drains.
+	// +optional
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+	// Autoscaling creates a HorizontalPodAutoscaler for the web Deployment,
+	// which then owns its number of replicas. Replicas is ignored while
+	// autoscaling is set.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
 	// CodeVolumeSpec specifies how the site's code gets mounted into the
 	// container. If not specified, a code volume won't get mounted at all.
 	// +optional
@@ -160,25 +278,449 @@ type WordpressSpec struct {
 	// If specified, indicates the pod's priority class
 	// +optional
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Monitoring creates a Prometheus Operator monitor which scrapes the
+	// metrics of the web pods. It's created only if the monitor's CRD is
+	// installed when the operator starts.
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// NetworkPolicy enables NetworkPolicies which isolate the web and wp-cli
+	// job pods of the site.
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+	// Service configures the web Service of the site.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
 	// IngressAnnotations for this Wordpress site
 	// +optional
 	IngressAnnotations map[string]string `json:"ingressAnnotations,omitempty"`
+	// Ingress configures the Ingresses of the site in ingress routing mode.
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+	// Routing defines how the routes of the site are exposed. By default,
+	// they are exposed with an Ingress.
+	// +optional
+	Routing *RoutingSpec `json:"routing,omitempty"`
+	// Redirects are the redirects rendered into the site's Ingresses, as
+	// ingress-nginx annotations, or HTTPRoutes.
+	// +optional
+	Redirects []RedirectSpec `json:"redirects,omitempty"`
+	// CanonicalHost is the domain the requests for the other domains of the
+	// site are permanently redirected to, keeping their path. It must be the
+	// domain of the first route, which WordPress uses as home URL.
+	// +optional
+	CanonicalHost string `json:"canonicalHost,omitempty"`
 	// Additional init containers
 	// +optional
 	InitContainers []corev1.Container `json:"initContainers,omitempty"`
 	// Additional sidecar containers (eg. blackfire or tideways agent)
 	// +optional
 	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// Cron defines how wp-cron is run for the site. By default, wp-cron.php
+	// is requested every 30 seconds.
+	// +optional
+	Cron *CronSpec `json:"cron,omitempty"`
+}
+
+// DisruptionBudgetSpec configures the PodDisruptionBudget of the web pods.
+// Only one of minAvailable and maxUnavailable may be set. Defaults to a
+// maxUnavailable of 1.
+type DisruptionBudgetSpec struct {
+	// MinAvailable is the number or the percentage of web pods which must
+	// remain available during voluntary disruptions.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or the percentage of web pods which can be
+	// unavailable during voluntary disruptions.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingSpec configures the HorizontalPodAutoscaler of the web
+// Deployment.
+type AutoscalingSpec struct {
+	// MinReplicas is the lower limit of the number of web pods. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper limit of the number of web pods.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// TargetCPUUtilizationPercentage is the average CPU utilization of the
+	// web pods, relative to their requests, the autoscaler aims for.
+	// Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+}
+
+// MonitorKind is the kind of the Prometheus Operator monitor of a site.
+type MonitorKind string
+
+const (
+	// ServiceMonitorKind scrapes the web pods through the web Service.
+	ServiceMonitorKind MonitorKind = "ServiceMonitor"
+	// PodMonitorKind scrapes the web pods directly.
+	PodMonitorKind MonitorKind = "PodMonitor"
+)
+
+// MonitoringSpec configures the Prometheus Operator monitor of a site.
+type MonitoringSpec struct {
+	// Kind of the monitor, either ServiceMonitor or PodMonitor. Defaults to
+	// ServiceMonitor.
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+	// +optional
+	Kind MonitorKind `json:"kind,omitempty"`
+	// Interval at which the metrics are scraped. Defaults to the Prometheus
+	// scrape interval.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// ScrapeTimeout of the metrics scrapes. Defaults to the Prometheus scrape
+	// timeout.
+	// +optional
+	ScrapeTimeout *metav1.Duration `json:"scrapeTimeout,omitempty"`
+	// Labels are additional labels of the monitor, eg. for Prometheus to
+	// select it.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Relabelings are applied to the scraped targets, before scraping.
+	// +optional
+	Relabelings []RelabelConfig `json:"relabelings,omitempty"`
+}
+
+// RelabelConfig is a Prometheus relabeling rule.
+// More info: https://prometheus.io/docs/prometheus/latest/configuration/configuration/#relabel_config
+type RelabelConfig struct {
+	// SourceLabels select values from existing labels.
+	// +optional
+	SourceLabels []string `json:"sourceLabels,omitempty"`
+	// Separator placed between the concatenated source label values.
+	// +optional
+	Separator string `json:"separator,omitempty"`
+	// TargetLabel is the label to which the resulting value is written.
+	// +optional
+	TargetLabel string `json:"targetLabel,omitempty"`
+	// Regex against which the extracted value is matched.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+	// Modulus to take of the hash of the source label values.
+	// +optional
+	Modulus uint64 `json:"modulus,omitempty"`
+	// Replacement value against which a regex replace is performed.
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+	// Action to perform based on regex matching.
+	// +kubebuilder:validation:Enum=replace;keep;drop;hashmod;labelmap;labeldrop;labelkeep
+	// +optional
+	Action string `json:"action,omitempty"`
+}
+
+// NetworkPolicySpec configures the NetworkPolicies of a site. The web pods
+// accept http traffic from the ingress controllers and the operator, which
+// requests wp-cron.php, and metrics scrapes from Prometheus. The wp-cli job
+// pods accept no traffic.
+type NetworkPolicySpec struct {
+	// IngressFrom are the peers allowed to reach the web pods over http.
+	// Defaults to the pods in the namespace of the Gateway in gateway routing
+	// mode, or in the operator's --ingress-controller-namespace otherwise.
+	// +optional
+	IngressFrom []netv1.NetworkPolicyPeer `json:"ingressFrom,omitempty"`
+	// MetricsFrom are the peers allowed to scrape the metrics of the web pods.
+	// Defaults to the pods in the operator's --prometheus-namespace.
+	// +optional
+	MetricsFrom []netv1.NetworkPolicyPeer `json:"metricsFrom,omitempty"`
+	// Egress are the destinations the web and wp-cli job pods are allowed to
+	// connect to, eg. the database, object storage and git hosts. DNS
+	// lookups are always allowed. If not set, egress is not restricted.
+	// +optional
+	Egress []netv1.NetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// ServiceSpec configures the web Service of a site.
+type ServiceSpec struct {
+	// Type of the Service. Defaults to ClusterIP.
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Annotations of the Service, eg. for cloud load balancers or service
+	// meshes.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// SessionAffinity of the Service. Defaults to None.
+	// +kubebuilder:validation:Enum=None;ClientIP
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// SessionAffinityConfig of the Service, used with ClientIP session
+	// affinity.
+	// +optional
+	SessionAffinityConfig *corev1.SessionAffinityConfig `json:"sessionAffinityConfig,omitempty"`
+	// ExtraPorts are additional ports of the Service, eg. for sidecars. They
+	// must be named and must not use the http and prometheus ports.
+	// +optional
+	ExtraPorts []corev1.ServicePort `json:"extraPorts,omitempty"`
+}
+
+// IngressSpec configures the Ingresses of a site.
+type IngressSpec struct {
+	// IngressClassName is the class of the site's Ingresses. Defaults to the
+	// operator's --ingress-class.
+	// +optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Groups are additional Ingresses of the site, named <name>-<group name>,
+	// which expose the routes that reference them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Groups []IngressGroup `json:"groups,omitempty"`
+}
+
+// IngressGroup defines an additional Ingress of a site.
+type IngressGroup struct {
+	// Name of the group, referenced by the routes' ingressGroup.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// IngressClassName is the class of the group's Ingress. Defaults to the
+	// class of the site's Ingresses.
+	// +optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Annotations of the group's Ingress, merged over the site's
+	// ingressAnnotations.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TLSSecretRef is a secret containing the TLS certificate for the domains
+	// of the group's routes without a tlsSecretRef of their own. Defaults to
+	// the site's tlsSecretRef.
+	// +optional
+	TLSSecretRef SecretRef `json:"tlsSecretRef,omitempty"`
+}
+
+// RedirectSpec defines a redirect of the requests for a domain and path.
+type RedirectSpec struct {
+	// Host is the domain of the requests to redirect. It must be the domain of
+	// one of the site's routes.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+	// Path is the path prefix of the requests to redirect. Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Target is the URL or the absolute path the requests are redirected to.
+	// +kubebuilder:validation:MinLength=1
+	Target string `json:"target"`
+	// StatusCode is the status code of the redirect responses. Only 301 and
+	// 302 are supported in gateway routing mode. Defaults to 301.
+	// +kubebuilder:validation:Enum=301;302;307;308
+	// +optional
+	StatusCode int32 `json:"statusCode,omitempty"`
+}
+
+// CertManagerSpec configures the TLS certificates of a site issued with
+// cert-manager.
+type CertManagerSpec struct {
+	// IssuerRef is the issuer of the certificates.
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+}
+
+// CertManagerIssuerReference identifies a cert-manager issuer.
+type CertManagerIssuerReference struct {
+	// Name is the name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Kind is the kind of the issuer, eg. Issuer or ClusterIssuer. Defaults
+	// to Issuer.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Group is the API group of the issuer. Defaults to cert-manager.io.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// RoutingMode is the way the routes of a site are exposed.
+type RoutingMode string
+
+const (
+	// RoutingModeIngress exposes the routes with an Ingress.
+	RoutingModeIngress RoutingMode = "ingress"
+	// RoutingModeGateway exposes the routes with Gateway API HTTPRoutes.
+	RoutingModeGateway RoutingMode = "gateway"
+)
+
+// RoutingSpec defines how the routes of a site are exposed.
+type RoutingSpec struct {
+	// Mode is the way the routes are exposed. It can be ingress, to create an
+	// Ingress, or gateway, to create a Gateway API HTTPRoute for each domain.
+	// Defaults to the operator's --routing-mode.
+	// +kubebuilder:validation:Enum=ingress;gateway
+	// +optional
+	Mode RoutingMode `json:"mode,omitempty"`
+	// Gateway is the Gateway the HTTPRoutes are attached to, in gateway mode.
+	// Defaults to the operator's --gateway.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// GatewayReference identifies a Gateway API Gateway.
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Namespace is the namespace of the Gateway. Defaults to the namespace of
+	// the site.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the Gateway listener to attach to. By
+	// default, the HTTPRoutes are attached to all listeners.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// CronMode is the way wp-cron is run for a site.
+type CronMode string
+
+const (
+	// CronModeHTTP runs wp-cron by periodically requesting wp-cron.php.
+	CronModeHTTP CronMode = "http"
+	// CronModeJob runs the due wp-cron events with wp-cli, from a CronJob.
+	CronModeJob CronMode = "job"
+	// CronModeDisabled doesn't run wp-cron, eg. when an external scheduler is used.
+	CronModeDisabled CronMode = "disabled"
+)
+
+// CronSpec defines how wp-cron is run for a site.
+type CronSpec struct {
+	// Mode is the way wp-cron is run. It can be http, to request wp-cron.php
+	// at every interval, job, to run `wp cron event run --due-now` from a
+	// CronJob on schedule, or disabled. Defaults to http.
+	// +kubebuilder:validation:Enum=http;job;disabled
+	// +optional
+	Mode CronMode `json:"mode,omitempty"`
+	// Interval is the interval at which wp-cron.php is requested, in http
+	// mode. A random jitter is added to it and it's increased exponentially
+	// while the requests fail. Defaults to the operator's --wp-cron-interval.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Timeout is the timeout of the wp-cron.php requests, in http mode.
+	// Defaults to the operator's --wp-cron-timeout.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// HTTP configures the wp-cron.php requests, in http mode.
+	// +optional
+	HTTP *CronHTTPSpec `json:"http,omitempty"`
+	// Schedule is the schedule of the wp-cron CronJob, in job mode, in cron
+	// format. Defaults to every minute.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// ConcurrencyPolicy specifies how concurrent runs of the wp-cron
+	// CronJob are treated, in job mode. Defaults to Forbid.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy batchv1.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Resources are the resources required by the wp-cron container, in
+	// job mode.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// CronRedirectPolicy is the way redirect responses to wp-cron.php requests
+// are treated.
+type CronRedirectPolicy string
+
+const (
+	// CronRedirectFollow follows redirects, up to 10 of them.
+	CronRedirectFollow CronRedirectPolicy = "Follow"
+	// CronRedirectAccept treats redirects as successful requests, without
+	// following them.
+	CronRedirectAccept CronRedirectPolicy = "Accept"
+	// CronRedirectFail treats redirects as failed requests.
+	CronRedirectFail CronRedirectPolicy = "Fail"
+)
+
+// CronHTTPSpec configures the wp-cron.php requests, in http mode.
+type CronHTTPSpec struct {
+	// URL is the URL wp-cron.php is requested at, eg.
+	// https://example.com/blog/wp-cron.php to request it through a route of
+	// the site. Its host must be one of the site's route domains or its
+	// service, unless the operator runs with --wp-cron-allow-any-url.
+	// Defaults to the site URL on the site's service, over http.
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Host is the Host header sent with the requests. Defaults to the host of
+	// the URL, if set, or to the site's main domain otherwise. It's also the
+	// server name verified over https.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// ForwardedProto is the X-Forwarded-Proto header sent with the requests,
+	// eg. https for sites which redirect to https requests received over http.
+	// +kubebuilder:validation:Enum=http;https
+	// +optional
+	ForwardedProto string `json:"forwardedProto,omitempty"`
+	// CABundle is a PEM encoded CA bundle used to verify the server
+	// certificate over https. Defaults to the system's trusted CAs.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// InsecureSkipVerify disables the verification of the server certificate
+	// over https.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// RedirectPolicy is the way redirect responses are treated. It can be
+	// Follow, Accept, to consider them successful, or Fail. Defaults to Follow.
+	// +kubebuilder:validation:Enum=Follow;Accept;Fail
+	// +optional
+	RedirectPolicy CronRedirectPolicy `json:"redirectPolicy,omitempty"`
+}
+
+// CronStatus is the status of wp-cron.php requests, in http mode.
+type CronStatus struct {
+	// LastAttemptTime is the time wp-cron.php was last requested.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// LastSuccessTime is the time wp-cron.php was last requested successfully.
+	// +optional
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+	// LastDuration is the duration of the last wp-cron.php request.
+	// +optional
+	LastDuration *metav1.Duration `json:"lastDuration,omitempty"`
+	// LastHTTPStatus is the HTTP status code of the last wp-cron.php request,
+	// or zero if it got no response.
+	// +optional
+	LastHTTPStatus int32 `json:"lastHTTPStatus,omitempty"`
+	// ConsecutiveFailures is the number of consecutive failed wp-cron.php
+	// requests.
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+}
+
+// CodeStatus is the status of the code cloned from git.
+type CodeStatus struct {
+	// Reference is the git reference which got resolved.
+	// +optional
+	Reference string `json:"reference,omitempty"`
+	// Revision is the commit the reference was resolved to, which is cloned
+	// by the site's pods.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// LastResolveTime is the time the reference was last resolved.
+	// +optional
+	LastResolveTime *metav1.Time `json:"lastResolveTime,omitempty"`
+	// LastPush is the time of the last git push received by the webhook
+	// receiver, which the reference was resolved after.
+	// +optional
+	LastPush string `json:"lastPush,omitempty"`
 }
 
 // GitVolumeSource is the desired spec for git code source.
 type GitVolumeSource struct {
 	// Repository is the git repository for the code
 	Repository string `json:"repository"`
-	// GitRef to clone (can be a branch name, but it should point to a tag or a
-	// commit hash)
+	// GitRef to clone (a branch name, a tag or a commit hash)
 	// +optional
 	GitRef string `json:"reference,omitempty"`
+	// ResolveInterval is the interval at which the reference is resolved to
+	// a commit, recorded in status.code.revision. A new commit gets rolled
+	// out to all the web pods. If set to 0, the pods clone the reference as
+	// it is when they start. Defaults to the operator's --git-resolve-interval.
+	// +optional
+	ResolveInterval *metav1.Duration `json:"resolveInterval,omitempty"`
 	// Env defines env variables  which get passed to the git clone container
 	// +optional
 	// +patchMergeKey=name
@@ -311,6 +853,12 @@ type WordpressBootstrapSpec struct {
 
 // WordpressStatus defines the observed state of Wordpress.
 type WordpressStatus struct {
+	// ObservedGeneration is the most recent generation observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// URL is the home URL of the site.
+	// +optional
+	URL string `json:"url,omitempty"`
 	// Conditions represents the Wordpress resource conditions list.
 	// +optional
 	Conditions []WordpressCondition `json:"conditions,omitempty"`
@@ -318,6 +866,16 @@ type WordpressStatus struct {
 	// This is copied over from the deployment object
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
+	// ConflictingRoutes are the routes claimed first by other sites. No
+	// ingress rules are created for them.
+	// +optional
+	ConflictingRoutes []RouteSpec `json:"conflictingRoutes,omitempty"`
+	// Cron is the status of wp-cron.php requests, in http mode.
+	// +optional
+	Cron *CronStatus `json:"cron,omitempty"`
+	// Code is the status of the code cloned from git.
+	// +optional
+	Code *CodeStatus `json:"code,omitempty"`
 }
 
 // +genclient
@@ -326,10 +884,15 @@ type WordpressStatus struct {
 // Wordpress is the Schema for the wordpresses API.
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:shortName=wp
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas
 // +kubebuilder:printcolumn:name="image",type="string",JSONPath=".spec.image",description="wordpress image"
 // +kubebuilder:printcolumn:name="wp-cron",type="string",JSONPath=".status.conditions[?(@.type == 'WPCronTriggering')].status",description="wp-cron triggering status"
+// +kubebuilder:printcolumn:name="ready",type="string",JSONPath=".status.conditions[?(@.type == 'Ready')].status",description="site readiness"
+// +kubebuilder:printcolumn:name="progressing",type="string",JSONPath=".status.conditions[?(@.type == 'Progressing')].status",description="site rollout status",priority=1
+// +kubebuilder:printcolumn:name="degraded",type="string",JSONPath=".status.conditions[?(@.type == 'Degraded')].status",description="site failure status",priority=1
+// +kubebuilder:printcolumn:name="url",type="string",JSONPath=".status.url",description="site home URL"
 type Wordpress struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
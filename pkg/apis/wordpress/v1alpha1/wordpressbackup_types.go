@@ -0,0 +1,155 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupDeletionPolicy describes what happens to the backup artifacts when
+// a WordpressBackup is deleted.
+type BackupDeletionPolicy string
+
+const (
+	// BackupDeletionPolicyRetain keeps the backup artifacts.
+	BackupDeletionPolicyRetain BackupDeletionPolicy = "Retain"
+	// BackupDeletionPolicyDelete removes the backup artifacts.
+	BackupDeletionPolicyDelete BackupDeletionPolicy = "Delete"
+)
+
+const (
+	// BackupCompleteCondition signals that the backup artifacts were uploaded.
+	BackupCompleteCondition WordpressConditionType = "Complete"
+
+	// BackupFailedCondition signals that the backup failed.
+	BackupFailedCondition WordpressConditionType = "Failed"
+
+	// BackupRunningReason is the reason used while the backup job is running.
+	BackupRunningReason = "BackupRunning"
+
+	// BackupSucceededReason is the reason used when the backup job succeeded.
+	BackupSucceededReason = "BackupSucceeded"
+
+	// BackupFailedReason is the reason used when the backup job failed.
+	BackupFailedReason = "BackupFailed"
+
+	// WordpressNotFoundReason is the reason used when the referenced site does not exist.
+	WordpressNotFoundReason = "WordpressNotFound"
+)
+
+// PVCBackupDestination is the desired spec for storing backups on a
+// persistent volume claim.
+type PVCBackupDestination struct {
+	// ClaimName is the name of a PersistentVolumeClaim in the same namespace.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+	// PathPrefix is the directory within the volume under which backups are stored.
+	// +optional
+	PathPrefix string `json:"prefix,omitempty"`
+}
+
+// BackupDestination is the location where backup artifacts are stored.
+// Exactly one of its members must be specified.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type BackupDestination struct {
+	// S3 specifies a S3 compatible bucket to store backups in.
+	// +optional
+	S3 *S3VolumeSource `json:"s3,omitempty"`
+	// GCS specifies a google cloud storage bucket to store backups in.
+	// +optional
+	GCS *GCSVolumeSource `json:"gcs,omitempty"`
+	// PersistentVolumeClaim specifies a volume claim to store backups on.
+	// +optional
+	PersistentVolumeClaim *PVCBackupDestination `json:"persistentVolumeClaim,omitempty"`
+}
+
+// WordpressBackupSpec defines the desired state of WordpressBackup.
+type WordpressBackupSpec struct {
+	// WordpressRef is the site to back up, from the same namespace.
+	WordpressRef corev1.LocalObjectReference `json:"wordpressRef"`
+	// Destination is where the backup artifacts are stored. The artifacts
+	// of a backup are stored under <prefix>/<namespace>/<site>/<backup name>.
+	Destination BackupDestination `json:"destination"`
+	// SkipMedia disables backing up the media files. The database is always
+	// backed up.
+	// +optional
+	SkipMedia bool `json:"skipMedia,omitempty"`
+	// DeletionPolicy describes what happens to the backup artifacts when
+	// the WordpressBackup is deleted. Defaults to Retain.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	// +optional
+	DeletionPolicy BackupDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// WordpressBackupStatus defines the observed state of WordpressBackup.
+type WordpressBackupStatus struct {
+	// Conditions represents the WordpressBackup resource conditions list.
+	// +optional
+	Conditions []WordpressCondition `json:"conditions,omitempty"`
+	// Location is the URL of the directory holding the backup artifacts.
+	// +optional
+	Location string `json:"location,omitempty"`
+	// StartTime is the time the backup job started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the backup job completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Duration is how long the backup job took to complete.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Size is the total size of the backup artifacts.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressBackup is the Schema for the wordpressbackups API.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=wpbackup
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="wordpress",type="string",JSONPath=".spec.wordpressRef.name",description="backed up site"
+// +kubebuilder:printcolumn:name="complete",type="string",JSONPath=".status.conditions[?(@.type == 'Complete')].status",description="backup completion status"
+// +kubebuilder:printcolumn:name="size",type="string",JSONPath=".status.size",description="backup size"
+// +kubebuilder:printcolumn:name="location",type="string",JSONPath=".status.location",description="backup artifacts location",priority=1
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+type WordpressBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WordpressBackupSpec   `json:"spec,omitempty"`
+	Status WordpressBackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressBackupList contains a list of WordpressBackup.
+type WordpressBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WordpressBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WordpressBackup{}, &WordpressBackupList{})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WordpressBackupScheduleSpec defines the desired state of WordpressBackupSchedule.
+type WordpressBackupScheduleSpec struct {
+	// Schedule in Cron format, see https://en.wikipedia.org/wiki/Cron.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// Suspend tells the operator to suspend subsequent backups. It does not
+	// apply to already started backups. Defaults to false.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// BackupTemplate is the spec of the backups created by the schedule.
+	BackupTemplate WordpressBackupSpec `json:"backupTemplate"`
+	// SuccessfulBackupsHistoryLimit is the number of completed backups to
+	// retain. Older backups are deleted, along with their artifacts if their
+	// deletion policy is Delete. Defaults to 7.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=7
+	// +optional
+	SuccessfulBackupsHistoryLimit *int32 `json:"successfulBackupsHistoryLimit,omitempty"`
+	// FailedBackupsHistoryLimit is the number of failed backups to retain.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	FailedBackupsHistoryLimit *int32 `json:"failedBackupsHistoryLimit,omitempty"`
+}
+
+// WordpressBackupScheduleStatus defines the observed state of WordpressBackupSchedule.
+type WordpressBackupScheduleStatus struct {
+	// LastScheduleTime is the last time a backup was scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastBackup is the name of the last backup created by the schedule.
+	// +optional
+	LastBackup string `json:"lastBackup,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressBackupSchedule is the Schema for the wordpressbackupschedules API.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=wpbackupschedule
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="wordpress",type="string",JSONPath=".spec.backupTemplate.wordpressRef.name",description="backed up site"
+// +kubebuilder:printcolumn:name="schedule",type="string",JSONPath=".spec.schedule",description="backup schedule"
+// +kubebuilder:printcolumn:name="suspend",type="boolean",JSONPath=".spec.suspend",description="whether the schedule is suspended"
+// +kubebuilder:printcolumn:name="last-schedule",type="date",JSONPath=".status.lastScheduleTime",description="last time a backup was scheduled"
+type WordpressBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WordpressBackupScheduleSpec   `json:"spec,omitempty"`
+	Status WordpressBackupScheduleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressBackupScheduleList contains a list of WordpressBackupSchedule.
+type WordpressBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WordpressBackupSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WordpressBackupSchedule{}, &WordpressBackupScheduleList{})
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CommandCompleteCondition signals that the command exited successfully.
+	CommandCompleteCondition WordpressConditionType = "Complete"
+
+	// CommandFailedCondition signals that the command failed.
+	CommandFailedCondition WordpressConditionType = "Failed"
+
+	// CommandRunningReason is the reason used while the command job is running.
+	CommandRunningReason = "CommandRunning"
+
+	// CommandSucceededReason is the reason used when the command exited successfully.
+	CommandSucceededReason = "CommandSucceeded"
+
+	// CommandFailedReason is the reason used when the command failed.
+	CommandFailedReason = "CommandFailed"
+)
+
+// WordpressCommandSpec defines the desired state of WordpressCommand.
+type WordpressCommandSpec struct {
+	// WordpressRef is the site to run the command for, from the same namespace.
+	WordpressRef corev1.LocalObjectReference `json:"wordpressRef"`
+	// Command is the argv of the command to run, eg. ["wp", "cache", "flush"].
+	// It runs in the site's image, with the site's environment, secrets and
+	// volumes. The command is not run through a shell.
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+	// ActiveDeadlineSeconds is the duration in seconds the command may run
+	// before it is terminated and marked as failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// TTLSecondsAfterFinished is the duration in seconds after which a
+	// finished WordpressCommand is deleted, along with its job. Finished
+	// commands are kept if unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// WordpressCommandStatus defines the observed state of WordpressCommand.
+type WordpressCommandStatus struct {
+	// Conditions represents the WordpressCommand resource conditions list.
+	// +optional
+	Conditions []WordpressCondition `json:"conditions,omitempty"`
+	// StartTime is the time the command job started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the command finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ExitCode is the exit code of the command.
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Output holds the tail of the command output, limited to 4096 bytes.
+	// +optional
+	Output string `json:"output,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressCommand is the Schema for the wordpresscommands API.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=wpcmd
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="wordpress",type="string",JSONPath=".spec.wordpressRef.name",description="site the command runs for"
+// +kubebuilder:printcolumn:name="complete",type="string",JSONPath=".status.conditions[?(@.type == 'Complete')].status",description="command completion status"
+// +kubebuilder:printcolumn:name="exit code",type="integer",JSONPath=".status.exitCode",description="command exit code"
+// +kubebuilder:printcolumn:name="command",type="string",JSONPath=".spec.command",description="command argv",priority=1
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+type WordpressCommand struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WordpressCommandSpec   `json:"spec,omitempty"`
+	Status WordpressCommandStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressCommandList contains a list of WordpressCommand.
+type WordpressCommandList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WordpressCommand `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WordpressCommand{}, &WordpressCommandList{})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WordpressRestorePhase is the phase a WordpressRestore is in.
+type WordpressRestorePhase string
+
+const (
+	// RestorePending means the restore waits for the site or the backup.
+	RestorePending WordpressRestorePhase = "Pending"
+	// RestoreScalingDown means the site's web pods are being stopped.
+	RestoreScalingDown WordpressRestorePhase = "ScalingDown"
+	// RestoreRestoring means the restore job is running.
+	RestoreRestoring WordpressRestorePhase = "Restoring"
+	// RestoreScalingUp means the site's web pods are being started again.
+	RestoreScalingUp WordpressRestorePhase = "ScalingUp"
+	// RestoreCompleted means the site has been restored.
+	RestoreCompleted WordpressRestorePhase = "Completed"
+	// RestoreFailed means the restore has failed.
+	RestoreFailed WordpressRestorePhase = "Failed"
+)
+
+const (
+	// RestoreCompleteCondition signals that the site has been restored.
+	RestoreCompleteCondition WordpressConditionType = "Complete"
+
+	// RestoreFailedCondition signals that the restore failed.
+	RestoreFailedCondition WordpressConditionType = "Failed"
+
+	// RestoreInProgressReason is the reason used while the restore is in progress.
+	RestoreInProgressReason = "RestoreInProgress"
+
+	// RestoreSucceededReason is the reason used when the restore job succeeded.
+	RestoreSucceededReason = "RestoreSucceeded"
+
+	// RestoreFailedReason is the reason used when the restore job failed.
+	RestoreFailedReason = "RestoreFailed"
+
+	// InvalidSourceReason is the reason used when the restore source is not valid.
+	InvalidSourceReason = "InvalidSource"
+
+	// BackupNotCompleteReason is the reason used when the restored backup did not complete.
+	BackupNotCompleteReason = "BackupNotComplete"
+)
+
+// RestoreSource locates the backup artifacts to restore. Either a backup or a
+// destination and a path must be specified.
+type RestoreSource struct {
+	// BackupRef is a WordpressBackup from the same namespace whose artifacts
+	// are restored. The backup must be complete.
+	// +optional
+	BackupRef *corev1.LocalObjectReference `json:"backupRef,omitempty"`
+	// Destination is where the backup artifacts are stored, for restoring
+	// artifacts of backups which no longer exist.
+	// +optional
+	Destination *BackupDestination `json:"destination,omitempty"`
+	// Path is the directory holding the backup artifacts, relative to the
+	// destination prefix, eg. <namespace>/<site>/<backup name>.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// WordpressRestoreSpec defines the desired state of WordpressRestore.
+type WordpressRestoreSpec struct {
+	// WordpressRef is the site to restore, from the same namespace. Its web
+	// pods are stopped while the restore is in progress.
+	WordpressRef corev1.LocalObjectReference `json:"wordpressRef"`
+	// Source locates the backup artifacts to restore.
+	Source RestoreSource `json:"source"`
+	// SkipMedia disables restoring the media files. The database is always
+	// restored.
+	// +optional
+	SkipMedia bool `json:"skipMedia,omitempty"`
+	// SourceDomain is the main domain of the backed up site. When set and
+	// different from the main domain of the restored site, it gets replaced
+	// within the database using `wp search-replace`.
+	// +optional
+	SourceDomain string `json:"sourceDomain,omitempty"`
+}
+
+// WordpressRestoreStatus defines the observed state of WordpressRestore.
+type WordpressRestoreStatus struct {
+	// Phase is the phase the restore is in.
+	// +optional
+	Phase WordpressRestorePhase `json:"phase,omitempty"`
+	// Conditions represents the WordpressRestore resource conditions list.
+	// +optional
+	Conditions []WordpressCondition `json:"conditions,omitempty"`
+	// Location is the URL of the restored backup artifacts.
+	// +optional
+	Location string `json:"location,omitempty"`
+	// StartTime is the time the restore started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the restore completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressRestore is the Schema for the wordpressrestores API.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName=wprestore
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="wordpress",type="string",JSONPath=".spec.wordpressRef.name",description="restored site"
+// +kubebuilder:printcolumn:name="phase",type="string",JSONPath=".status.phase",description="restore phase"
+// +kubebuilder:printcolumn:name="location",type="string",JSONPath=".status.location",description="restored backup artifacts",priority=1
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+type WordpressRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WordpressRestoreSpec   `json:"spec,omitempty"`
+	Status WordpressRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WordpressRestoreList contains a list of WordpressRestore.
+type WordpressRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WordpressRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WordpressRestore{}, &WordpressRestoreList{})
+}
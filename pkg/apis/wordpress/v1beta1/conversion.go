@@ -0,0 +1,760 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+// conversionDataAnnotation holds the v1alpha1 fields which can't be
+// represented in v1beta1, so that they survive a round trip through
+// v1beta1. It is never persisted, as v1alpha1 is the storage version.
+const conversionDataAnnotation = "wordpress.presslabs.org/v1alpha1-conversion-data"
+
+// v1alpha1Data holds the v1alpha1 fields lost when converting to v1beta1.
+type v1alpha1Data struct {
+	// Domains is the deprecated v1alpha1 spec.domains field.
+	Domains []v1alpha1.Domain `json:"domains,omitempty"`
+	// Code holds the code volume sources shadowed by the one in use.
+	Code *v1alpha1.CodeVolumeSpec `json:"code,omitempty"`
+	// Media holds the media volume sources shadowed by the one in use.
+	Media *v1alpha1.MediaVolumeSpec `json:"media,omitempty"`
+	// LastUpdateTimes holds the last update time of the conditions, by type.
+	LastUpdateTimes map[v1alpha1.WordpressConditionType]metav1.Time `json:"lastUpdateTimes,omitempty"`
+}
+
+var _ conversion.Convertible = &Wordpress{}
+
+// ConvertTo converts this Wordpress to the Hub version (v1alpha1).
+func (src *Wordpress) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Wordpress)
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+
+	data, err := popConversionData(&dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec = v1alpha1.WordpressSpec{
+		Replicas:               in.Spec.Replicas,
+		Domains:                data.Domains,
+		Routes:                 convertRoutesTo(in.Spec.Routes),
+		Image:                  in.Spec.Image,
+		ImagePullPolicy:        in.Spec.ImagePullPolicy,
+		ImagePullSecrets:       in.Spec.ImagePullSecrets,
+		ServiceAccountName:     in.Spec.ServiceAccountName,
+		TLSSecretRef:           v1alpha1.SecretRef(in.Spec.TLSSecretRef),
+		CertManager:            convertCertManagerTo(in.Spec.CertManager),
+		DeploymentStrategy:     in.Spec.DeploymentStrategy,
+		CodeVolumeSpec:         convertCodeVolumeSpecTo(in.Spec.CodeVolumeSpec, data.Code),
+		MediaVolumeSpec:        convertMediaVolumeSpecTo(in.Spec.MediaVolumeSpec, data.Media),
+		Volumes:                in.Spec.Volumes,
+		PodMetadata:            in.Spec.PodMetadata,
+		ReadinessProbe:         in.Spec.ReadinessProbe,
+		LivenessProbe:          in.Spec.LivenessProbe,
+		WordpressBootstrapSpec: (*v1alpha1.WordpressBootstrapSpec)(in.Spec.WordpressBootstrapSpec),
+		WordpressPathPrefix:    in.Spec.WordpressPathPrefix,
+		VolumeMounts:           in.Spec.VolumeMounts,
+		Env:                    in.Spec.Env,
+		EnvFrom:                in.Spec.EnvFrom,
+		Resources:              in.Spec.Resources,
+		NodeSelector:           in.Spec.NodeSelector,
+		Tolerations:            in.Spec.Tolerations,
+		Affinity:               in.Spec.Affinity,
+		PriorityClassName:      in.Spec.PriorityClassName,
+		IngressAnnotations:     in.Spec.IngressAnnotations,
+		Monitoring:             convertMonitoringTo(in.Spec.Monitoring),
+		NetworkPolicy:          (*v1alpha1.NetworkPolicySpec)(in.Spec.NetworkPolicy),
+		Service:                (*v1alpha1.ServiceSpec)(in.Spec.Service),
+		DisruptionBudget:       (*v1alpha1.DisruptionBudgetSpec)(in.Spec.DisruptionBudget),
+		Autoscaling:            (*v1alpha1.AutoscalingSpec)(in.Spec.Autoscaling),
+		Ingress:                convertIngressTo(in.Spec.Ingress),
+		Routing:                convertRoutingTo(in.Spec.Routing),
+		Redirects:              convertRedirectsTo(in.Spec.Redirects),
+		CanonicalHost:          in.Spec.CanonicalHost,
+		InitContainers:         in.Spec.InitContainers,
+		Sidecars:               in.Spec.Sidecars,
+		Cron:                   convertCronTo(in.Spec.Cron),
+	}
+
+	dst.Status = v1alpha1.WordpressStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		URL:                in.Status.URL,
+		Replicas:           in.Status.Replicas,
+		ConflictingRoutes:  convertRoutesTo(in.Status.ConflictingRoutes),
+		Cron:               convertCronStatusTo(in.Status.Cron),
+		Code:               (*v1alpha1.CodeStatus)(in.Status.Code),
+	}
+
+	if in.Status.Conditions != nil {
+		dst.Status.Conditions = make([]v1alpha1.WordpressCondition, len(in.Status.Conditions))
+	}
+
+	for i, c := range in.Status.Conditions {
+		dst.Status.Conditions[i] = v1alpha1.WordpressCondition{
+			Type:               v1alpha1.WordpressConditionType(c.Type),
+			Status:             corev1.ConditionStatus(c.Status),
+			LastUpdateTime:     data.LastUpdateTimes[v1alpha1.WordpressConditionType(c.Type)],
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *Wordpress) ConvertFrom(srcRaw conversion.Hub) error {
+	in := srcRaw.(*v1alpha1.Wordpress).DeepCopy()
+	data := &v1alpha1Data{Domains: in.Spec.Domains}
+
+	dst.ObjectMeta = in.ObjectMeta
+
+	code, shadowedCode := convertCodeVolumeSpecFrom(in.Spec.CodeVolumeSpec)
+	media, shadowedMedia := convertMediaVolumeSpecFrom(in.Spec.MediaVolumeSpec)
+	data.Code = shadowedCode
+	data.Media = shadowedMedia
+
+	dst.Spec = WordpressSpec{
+		Replicas:               in.Spec.Replicas,
+		Routes:                 convertRoutesFrom(in.Spec.Routes),
+		Image:                  in.Spec.Image,
+		ImagePullPolicy:        in.Spec.ImagePullPolicy,
+		ImagePullSecrets:       in.Spec.ImagePullSecrets,
+		ServiceAccountName:     in.Spec.ServiceAccountName,
+		TLSSecretRef:           SecretRef(in.Spec.TLSSecretRef),
+		CertManager:            convertCertManagerFrom(in.Spec.CertManager),
+		DeploymentStrategy:     in.Spec.DeploymentStrategy,
+		CodeVolumeSpec:         code,
+		MediaVolumeSpec:        media,
+		Volumes:                in.Spec.Volumes,
+		PodMetadata:            in.Spec.PodMetadata,
+		ReadinessProbe:         in.Spec.ReadinessProbe,
+		LivenessProbe:          in.Spec.LivenessProbe,
+		WordpressBootstrapSpec: (*WordpressBootstrapSpec)(in.Spec.WordpressBootstrapSpec),
+		WordpressPathPrefix:    in.Spec.WordpressPathPrefix,
+		VolumeMounts:           in.Spec.VolumeMounts,
+		Env:                    in.Spec.Env,
+		EnvFrom:                in.Spec.EnvFrom,
+		Resources:              in.Spec.Resources,
+		NodeSelector:           in.Spec.NodeSelector,
+		Tolerations:            in.Spec.Tolerations,
+		Affinity:               in.Spec.Affinity,
+		PriorityClassName:      in.Spec.PriorityClassName,
+		IngressAnnotations:     in.Spec.IngressAnnotations,
+		Monitoring:             convertMonitoringFrom(in.Spec.Monitoring),
+		NetworkPolicy:          (*NetworkPolicySpec)(in.Spec.NetworkPolicy),
+		Service:                (*ServiceSpec)(in.Spec.Service),
+		DisruptionBudget:       (*DisruptionBudgetSpec)(in.Spec.DisruptionBudget),
+		Autoscaling:            (*AutoscalingSpec)(in.Spec.Autoscaling),
+		Ingress:                convertIngressFrom(in.Spec.Ingress),
+		Routing:                convertRoutingFrom(in.Spec.Routing),
+		Redirects:              convertRedirectsFrom(in.Spec.Redirects),
+		CanonicalHost:          in.Spec.CanonicalHost,
+		InitContainers:         in.Spec.InitContainers,
+		Sidecars:               in.Spec.Sidecars,
+		Cron:                   convertCronFrom(in.Spec.Cron),
+	}
+
+	dst.Status = WordpressStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		URL:                in.Status.URL,
+		Replicas:           in.Status.Replicas,
+		ConflictingRoutes:  convertRoutesFrom(in.Status.ConflictingRoutes),
+		Cron:               convertCronStatusFrom(in.Status.Cron),
+		Code:               (*CodeStatus)(in.Status.Code),
+	}
+
+	if in.Status.Conditions != nil {
+		dst.Status.Conditions = make([]metav1.Condition, len(in.Status.Conditions))
+	}
+
+	for i, c := range in.Status.Conditions {
+		// the v1alpha1 conditions are all computed for the observed generation
+		dst.Status.Conditions[i] = metav1.Condition{
+			Type:               string(c.Type),
+			Status:             metav1.ConditionStatus(c.Status),
+			ObservedGeneration: in.Status.ObservedGeneration,
+			LastTransitionTime: c.LastTransitionTime,
+			Reason:             c.Reason,
+			Message:            c.Message,
+		}
+
+		if !c.LastUpdateTime.IsZero() {
+			if data.LastUpdateTimes == nil {
+				data.LastUpdateTimes = map[v1alpha1.WordpressConditionType]metav1.Time{}
+			}
+
+			data.LastUpdateTimes[c.Type] = c.LastUpdateTime
+		}
+	}
+
+	return pushConversionData(&dst.ObjectMeta, data)
+}
+
+// pushConversionData stores data into the conversionDataAnnotation, unless
+// there is nothing to store.
+func pushConversionData(meta *metav1.ObjectMeta, data *v1alpha1Data) error {
+	if data.Domains == nil && data.Code == nil && data.Media == nil && data.LastUpdateTimes == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+
+	meta.Annotations[conversionDataAnnotation] = string(raw)
+
+	return nil
+}
+
+// popConversionData reads and removes the conversionDataAnnotation.
+func popConversionData(meta *metav1.ObjectMeta) (*v1alpha1Data, error) {
+	data := &v1alpha1Data{}
+
+	raw, ok := meta.Annotations[conversionDataAnnotation]
+	if !ok {
+		return data, nil
+	}
+
+	delete(meta.Annotations, conversionDataAnnotation)
+
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func convertRoutesTo(in []RouteSpec) []v1alpha1.RouteSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]v1alpha1.RouteSpec, len(in))
+	for i := range in {
+		out[i] = v1alpha1.RouteSpec{
+			Domain:       in[i].Domain,
+			Path:         in[i].Path,
+			TLSSecretRef: v1alpha1.SecretRef(in[i].TLSSecretRef),
+			IngressGroup: in[i].IngressGroup,
+		}
+	}
+
+	return out
+}
+
+func convertRoutesFrom(in []v1alpha1.RouteSpec) []RouteSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]RouteSpec, len(in))
+	for i := range in {
+		out[i] = RouteSpec{
+			Domain:       in[i].Domain,
+			Path:         in[i].Path,
+			TLSSecretRef: SecretRef(in[i].TLSSecretRef),
+			IngressGroup: in[i].IngressGroup,
+		}
+	}
+
+	return out
+}
+
+func convertMonitoringTo(in *MonitoringSpec) *v1alpha1.MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &v1alpha1.MonitoringSpec{
+		Kind:          v1alpha1.MonitorKind(in.Kind),
+		Interval:      in.Interval,
+		ScrapeTimeout: in.ScrapeTimeout,
+		Labels:        in.Labels,
+	}
+
+	if in.Relabelings != nil {
+		out.Relabelings = make([]v1alpha1.RelabelConfig, len(in.Relabelings))
+		for i := range in.Relabelings {
+			out.Relabelings[i] = v1alpha1.RelabelConfig(in.Relabelings[i])
+		}
+	}
+
+	return out
+}
+
+func convertMonitoringFrom(in *v1alpha1.MonitoringSpec) *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &MonitoringSpec{
+		Kind:          MonitorKind(in.Kind),
+		Interval:      in.Interval,
+		ScrapeTimeout: in.ScrapeTimeout,
+		Labels:        in.Labels,
+	}
+
+	if in.Relabelings != nil {
+		out.Relabelings = make([]RelabelConfig, len(in.Relabelings))
+		for i := range in.Relabelings {
+			out.Relabelings[i] = RelabelConfig(in.Relabelings[i])
+		}
+	}
+
+	return out
+}
+
+func convertIngressTo(in *IngressSpec) *v1alpha1.IngressSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &v1alpha1.IngressSpec{IngressClassName: in.IngressClassName}
+
+	if in.Groups != nil {
+		out.Groups = make([]v1alpha1.IngressGroup, len(in.Groups))
+		for i := range in.Groups {
+			out.Groups[i] = v1alpha1.IngressGroup{
+				Name:             in.Groups[i].Name,
+				IngressClassName: in.Groups[i].IngressClassName,
+				Annotations:      in.Groups[i].Annotations,
+				TLSSecretRef:     v1alpha1.SecretRef(in.Groups[i].TLSSecretRef),
+			}
+		}
+	}
+
+	return out
+}
+
+func convertIngressFrom(in *v1alpha1.IngressSpec) *IngressSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &IngressSpec{IngressClassName: in.IngressClassName}
+
+	if in.Groups != nil {
+		out.Groups = make([]IngressGroup, len(in.Groups))
+		for i := range in.Groups {
+			out.Groups[i] = IngressGroup{
+				Name:             in.Groups[i].Name,
+				IngressClassName: in.Groups[i].IngressClassName,
+				Annotations:      in.Groups[i].Annotations,
+				TLSSecretRef:     SecretRef(in.Groups[i].TLSSecretRef),
+			}
+		}
+	}
+
+	return out
+}
+
+func convertRedirectsTo(in []RedirectSpec) []v1alpha1.RedirectSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]v1alpha1.RedirectSpec, len(in))
+	for i := range in {
+		out[i] = v1alpha1.RedirectSpec(in[i])
+	}
+
+	return out
+}
+
+func convertRedirectsFrom(in []v1alpha1.RedirectSpec) []RedirectSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]RedirectSpec, len(in))
+	for i := range in {
+		out[i] = RedirectSpec(in[i])
+	}
+
+	return out
+}
+
+// v1alpha1 volume sources, in the order of their precedence.
+const (
+	gitSource = iota
+	s3Source
+	gcsSource
+	pvcSource
+	hostPathSource
+	emptyDirSource
+	noSource
+)
+
+func activeCodeSource(in *v1alpha1.CodeVolumeSpec) int {
+	switch {
+	case in.GitDir != nil:
+		return gitSource
+	case in.PersistentVolumeClaim != nil:
+		return pvcSource
+	case in.HostPath != nil:
+		return hostPathSource
+	case in.EmptyDir != nil:
+		return emptyDirSource
+	default:
+		return noSource
+	}
+}
+
+func activeMediaSource(in *v1alpha1.MediaVolumeSpec) int {
+	switch {
+	case in.S3VolumeSource != nil:
+		return s3Source
+	case in.GCSVolumeSource != nil:
+		return gcsSource
+	case in.PersistentVolumeClaim != nil:
+		return pvcSource
+	case in.HostPath != nil:
+		return hostPathSource
+	case in.EmptyDir != nil:
+		return emptyDirSource
+	default:
+		return noSource
+	}
+}
+
+// convertCodeVolumeSpecTo converts the code volume spec and restores the
+// shadowed sources, as long as they are still shadowed by the one in use.
+func convertCodeVolumeSpecTo(in *CodeVolumeSpec, shadowed *v1alpha1.CodeVolumeSpec) *v1alpha1.CodeVolumeSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &v1alpha1.CodeVolumeSpec{
+		ObjectMeta:            in.ObjectMeta,
+		ReadOnly:              in.ReadOnly,
+		MountPath:             in.MountPath,
+		ContentSubPath:        in.ContentSubPath,
+		ConfigSubPath:         in.ConfigSubPath,
+		GitDir:                (*v1alpha1.GitVolumeSource)(in.Source.Git),
+		PersistentVolumeClaim: in.Source.PersistentVolumeClaim,
+		HostPath:              in.Source.HostPath,
+		EmptyDir:              in.Source.EmptyDir,
+	}
+
+	if shadowed == nil {
+		return out
+	}
+
+	active := activeCodeSource(out)
+
+	if active < pvcSource && out.PersistentVolumeClaim == nil {
+		out.PersistentVolumeClaim = shadowed.PersistentVolumeClaim
+	}
+
+	if active < hostPathSource && out.HostPath == nil {
+		out.HostPath = shadowed.HostPath
+	}
+
+	if active < emptyDirSource && out.EmptyDir == nil {
+		out.EmptyDir = shadowed.EmptyDir
+	}
+
+	return out
+}
+
+// convertCodeVolumeSpecFrom converts the code volume spec, keeping only the
+// source in use. The shadowed sources are returned separately.
+func convertCodeVolumeSpecFrom(in *v1alpha1.CodeVolumeSpec) (*CodeVolumeSpec, *v1alpha1.CodeVolumeSpec) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &CodeVolumeSpec{
+		ObjectMeta:     in.ObjectMeta,
+		ReadOnly:       in.ReadOnly,
+		MountPath:      in.MountPath,
+		ContentSubPath: in.ContentSubPath,
+		ConfigSubPath:  in.ConfigSubPath,
+	}
+	shadowed := &v1alpha1.CodeVolumeSpec{
+		PersistentVolumeClaim: in.PersistentVolumeClaim,
+		HostPath:              in.HostPath,
+		EmptyDir:              in.EmptyDir,
+	}
+
+	switch activeCodeSource(in) {
+	case gitSource:
+		out.Source.Git = (*GitVolumeSource)(in.GitDir)
+	case pvcSource:
+		out.Source.PersistentVolumeClaim, shadowed.PersistentVolumeClaim = in.PersistentVolumeClaim, nil
+	case hostPathSource:
+		out.Source.HostPath, shadowed.HostPath = in.HostPath, nil
+	case emptyDirSource:
+		out.Source.EmptyDir, shadowed.EmptyDir = in.EmptyDir, nil
+	}
+
+	if activeCodeSource(shadowed) == noSource {
+		shadowed = nil
+	}
+
+	return out, shadowed
+}
+
+// convertMediaVolumeSpecTo converts the media volume spec and restores the
+// shadowed sources, as long as they are still shadowed by the one in use.
+func convertMediaVolumeSpecTo(in *MediaVolumeSpec, shadowed *v1alpha1.MediaVolumeSpec) *v1alpha1.MediaVolumeSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &v1alpha1.MediaVolumeSpec{
+		ObjectMeta:            in.ObjectMeta,
+		ReadOnly:              in.ReadOnly,
+		MountPath:             in.MountPath,
+		ContentSubPath:        in.ContentSubPath,
+		S3VolumeSource:        (*v1alpha1.S3VolumeSource)(in.Source.S3),
+		GCSVolumeSource:       (*v1alpha1.GCSVolumeSource)(in.Source.GCS),
+		PersistentVolumeClaim: in.Source.PersistentVolumeClaim,
+		HostPath:              in.Source.HostPath,
+		EmptyDir:              in.Source.EmptyDir,
+	}
+
+	if shadowed == nil {
+		return out
+	}
+
+	active := activeMediaSource(out)
+
+	if active < gcsSource && out.GCSVolumeSource == nil {
+		out.GCSVolumeSource = shadowed.GCSVolumeSource
+	}
+
+	if active < pvcSource && out.PersistentVolumeClaim == nil {
+		out.PersistentVolumeClaim = shadowed.PersistentVolumeClaim
+	}
+
+	if active < hostPathSource && out.HostPath == nil {
+		out.HostPath = shadowed.HostPath
+	}
+
+	if active < emptyDirSource && out.EmptyDir == nil {
+		out.EmptyDir = shadowed.EmptyDir
+	}
+
+	return out
+}
+
+// convertMediaVolumeSpecFrom converts the media volume spec, keeping only
+// the source in use. The shadowed sources are returned separately.
+func convertMediaVolumeSpecFrom(in *v1alpha1.MediaVolumeSpec) (*MediaVolumeSpec, *v1alpha1.MediaVolumeSpec) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &MediaVolumeSpec{
+		ObjectMeta:     in.ObjectMeta,
+		ReadOnly:       in.ReadOnly,
+		MountPath:      in.MountPath,
+		ContentSubPath: in.ContentSubPath,
+	}
+	shadowed := &v1alpha1.MediaVolumeSpec{
+		GCSVolumeSource:       in.GCSVolumeSource,
+		PersistentVolumeClaim: in.PersistentVolumeClaim,
+		HostPath:              in.HostPath,
+		EmptyDir:              in.EmptyDir,
+	}
+
+	switch activeMediaSource(in) {
+	case s3Source:
+		out.Source.S3 = (*S3VolumeSource)(in.S3VolumeSource)
+	case gcsSource:
+		out.Source.GCS, shadowed.GCSVolumeSource = (*GCSVolumeSource)(in.GCSVolumeSource), nil
+	case pvcSource:
+		out.Source.PersistentVolumeClaim, shadowed.PersistentVolumeClaim = in.PersistentVolumeClaim, nil
+	case hostPathSource:
+		out.Source.HostPath, shadowed.HostPath = in.HostPath, nil
+	case emptyDirSource:
+		out.Source.EmptyDir, shadowed.EmptyDir = in.EmptyDir, nil
+	}
+
+	if activeMediaSource(shadowed) == noSource {
+		shadowed = nil
+	}
+
+	return out, shadowed
+}
+
+func convertCertManagerTo(in *CertManagerSpec) *v1alpha1.CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+
+	return &v1alpha1.CertManagerSpec{
+		IssuerRef: v1alpha1.CertManagerIssuerReference(in.IssuerRef),
+	}
+}
+
+func convertCertManagerFrom(in *v1alpha1.CertManagerSpec) *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+
+	return &CertManagerSpec{
+		IssuerRef: CertManagerIssuerReference(in.IssuerRef),
+	}
+}
+
+func convertRoutingTo(in *RoutingSpec) *v1alpha1.RoutingSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &v1alpha1.RoutingSpec{Mode: v1alpha1.RoutingMode(in.Mode)}
+
+	if in.Gateway != nil {
+		out.Gateway = &v1alpha1.GatewayReference{
+			Name:        in.Gateway.Name,
+			Namespace:   in.Gateway.Namespace,
+			SectionName: in.Gateway.SectionName,
+		}
+	}
+
+	return out
+}
+
+func convertRoutingFrom(in *v1alpha1.RoutingSpec) *RoutingSpec {
+	if in == nil {
+		return nil
+	}
+
+	out := &RoutingSpec{Mode: RoutingMode(in.Mode)}
+
+	if in.Gateway != nil {
+		out.Gateway = &GatewayReference{
+			Name:        in.Gateway.Name,
+			Namespace:   in.Gateway.Namespace,
+			SectionName: in.Gateway.SectionName,
+		}
+	}
+
+	return out
+}
+
+func convertCronTo(in *CronSpec) *v1alpha1.CronSpec {
+	if in == nil {
+		return nil
+	}
+
+	return &v1alpha1.CronSpec{
+		Mode:              v1alpha1.CronMode(in.Mode),
+		Interval:          in.Interval,
+		Timeout:           in.Timeout,
+		HTTP:              convertCronHTTPTo(in.HTTP),
+		Schedule:          in.Schedule,
+		ConcurrencyPolicy: in.ConcurrencyPolicy,
+		Resources:         in.Resources,
+	}
+}
+
+func convertCronFrom(in *v1alpha1.CronSpec) *CronSpec {
+	if in == nil {
+		return nil
+	}
+
+	return &CronSpec{
+		Mode:              CronMode(in.Mode),
+		Interval:          in.Interval,
+		Timeout:           in.Timeout,
+		HTTP:              convertCronHTTPFrom(in.HTTP),
+		Schedule:          in.Schedule,
+		ConcurrencyPolicy: in.ConcurrencyPolicy,
+		Resources:         in.Resources,
+	}
+}
+
+func convertCronHTTPTo(in *CronHTTPSpec) *v1alpha1.CronHTTPSpec {
+	if in == nil {
+		return nil
+	}
+
+	return &v1alpha1.CronHTTPSpec{
+		URL:                in.URL,
+		Host:               in.Host,
+		ForwardedProto:     in.ForwardedProto,
+		CABundle:           in.CABundle,
+		InsecureSkipVerify: in.InsecureSkipVerify,
+		RedirectPolicy:     v1alpha1.CronRedirectPolicy(in.RedirectPolicy),
+	}
+}
+
+func convertCronHTTPFrom(in *v1alpha1.CronHTTPSpec) *CronHTTPSpec {
+	if in == nil {
+		return nil
+	}
+
+	return &CronHTTPSpec{
+		URL:                in.URL,
+		Host:               in.Host,
+		ForwardedProto:     in.ForwardedProto,
+		CABundle:           in.CABundle,
+		InsecureSkipVerify: in.InsecureSkipVerify,
+		RedirectPolicy:     CronRedirectPolicy(in.RedirectPolicy),
+	}
+}
+
+func convertCronStatusTo(in *CronStatus) *v1alpha1.CronStatus {
+	if in == nil {
+		return nil
+	}
+
+	return &v1alpha1.CronStatus{
+		LastAttemptTime:     in.LastAttemptTime,
+		LastSuccessTime:     in.LastSuccessTime,
+		LastDuration:        in.LastDuration,
+		LastHTTPStatus:      in.LastHTTPStatus,
+		ConsecutiveFailures: in.ConsecutiveFailures,
+	}
+}
+
+func convertCronStatusFrom(in *v1alpha1.CronStatus) *CronStatus {
+	if in == nil {
+		return nil
+	}
+
+	return &CronStatus{
+		LastAttemptTime:     in.LastAttemptTime,
+		LastSuccessTime:     in.LastSuccessTime,
+		LastDuration:        in.LastDuration,
+		LastHTTPStatus:      in.LastHTTPStatus,
+		ConsecutiveFailures: in.ConsecutiveFailures,
+	}
+}
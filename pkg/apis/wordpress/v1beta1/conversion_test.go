@@ -0,0 +1,201 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"math/rand"
+
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+const fuzzIterations = 1000
+
+// fuzzerFuncs restrict the generated objects to the ones the API server
+// would accept.
+func fuzzerFuncs(_ serializer.CodecFactory) []interface{} {
+	return []interface{}{
+		// condition types are unique
+		func(in *v1alpha1.WordpressStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(in)
+
+			seen := map[v1alpha1.WordpressConditionType]bool{}
+			conditions := in.Conditions[:0]
+
+			for _, cond := range in.Conditions {
+				if !seen[cond.Type] {
+					conditions = append(conditions, cond)
+				}
+
+				seen[cond.Type] = true
+			}
+
+			in.Conditions = conditions
+		},
+		// condition types are unique and the v1beta1 conditions are computed
+		// for the observed generation
+		func(in *WordpressStatus, c fuzz.Continue) {
+			c.FuzzNoCustom(in)
+
+			seen := map[string]bool{}
+			conditions := in.Conditions[:0]
+
+			for _, cond := range in.Conditions {
+				if !seen[cond.Type] {
+					cond.ObservedGeneration = in.ObservedGeneration
+					conditions = append(conditions, cond)
+				}
+
+				seen[cond.Type] = true
+			}
+
+			in.Conditions = conditions
+		},
+		// only one of the v1beta1 code volume sources is set
+		func(in *CodeVolumeSource, c fuzz.Continue) {
+			*in = CodeVolumeSource{}
+
+			switch c.Intn(5) {
+			case 0:
+				c.Fuzz(&in.Git)
+			case 1:
+				c.Fuzz(&in.PersistentVolumeClaim)
+			case 2:
+				c.Fuzz(&in.HostPath)
+			case 3:
+				c.Fuzz(&in.EmptyDir)
+			}
+		},
+		// only one of the v1beta1 media volume sources is set
+		func(in *MediaVolumeSource, c fuzz.Continue) {
+			*in = MediaVolumeSource{}
+
+			switch c.Intn(6) {
+			case 0:
+				c.Fuzz(&in.S3)
+			case 1:
+				c.Fuzz(&in.GCS)
+			case 2:
+				c.Fuzz(&in.PersistentVolumeClaim)
+			case 3:
+				c.Fuzz(&in.HostPath)
+			case 4:
+				c.Fuzz(&in.EmptyDir)
+			}
+		},
+	}
+}
+
+func newFuzzer() *fuzz.Fuzzer {
+	scheme := runtime.NewScheme()
+	Expect(AddToScheme(scheme)).To(Succeed())
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	funcs := fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, fuzzerFuncs)
+
+	return fuzzer.FuzzerFor(funcs, rand.NewSource(GinkgoRandomSeed()), serializer.NewCodecFactory(scheme))
+}
+
+var _ = Describe("Wordpress conversion", func() {
+	var f *fuzz.Fuzzer
+
+	BeforeEach(func() {
+		f = newFuzzer()
+	})
+
+	It("should round trip v1alpha1 through v1beta1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			hub := &v1alpha1.Wordpress{}
+			f.Fuzz(hub)
+			hub.TypeMeta = metav1.TypeMeta{}
+
+			spoke := &Wordpress{}
+			Expect(spoke.ConvertFrom(hub.DeepCopy())).To(Succeed())
+
+			restored := &v1alpha1.Wordpress{}
+			Expect(spoke.ConvertTo(restored)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(hub, restored)).To(BeTrue(), diff.ObjectReflectDiff(hub, restored))
+		}
+	})
+
+	It("should round trip v1beta1 through v1alpha1", func() {
+		for i := 0; i < fuzzIterations; i++ {
+			spoke := &Wordpress{}
+			f.Fuzz(spoke)
+			spoke.TypeMeta = metav1.TypeMeta{}
+
+			hub := &v1alpha1.Wordpress{}
+			Expect(spoke.DeepCopy().ConvertTo(hub)).To(Succeed())
+
+			restored := &Wordpress{}
+			Expect(restored.ConvertFrom(hub)).To(Succeed())
+
+			Expect(apiequality.Semantic.DeepEqual(spoke, restored)).To(BeTrue(), diff.ObjectReflectDiff(spoke, restored))
+		}
+	})
+
+	It("should keep only the volume source in use", func() {
+		hub := &v1alpha1.Wordpress{
+			Spec: v1alpha1.WordpressSpec{
+				CodeVolumeSpec: &v1alpha1.CodeVolumeSpec{
+					GitDir:   &v1alpha1.GitVolumeSource{Repository: "https://github.com/bitpoke/stack-example-wordpress.git"},
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+				MediaVolumeSpec: &v1alpha1.MediaVolumeSpec{
+					GCSVolumeSource: &v1alpha1.GCSVolumeSource{Bucket: "test"},
+					HostPath:        &corev1.HostPathVolumeSource{Path: "/media"},
+				},
+			},
+		}
+
+		spoke := &Wordpress{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Spec.CodeVolumeSpec.Source).To(Equal(CodeVolumeSource{
+			Git: &GitVolumeSource{Repository: "https://github.com/bitpoke/stack-example-wordpress.git"},
+		}))
+		Expect(spoke.Spec.MediaVolumeSpec.Source).To(Equal(MediaVolumeSource{
+			GCS: &GCSVolumeSource{Bucket: "test"},
+		}))
+		Expect(spoke.Annotations).To(HaveKey(conversionDataAnnotation))
+
+		By("not restoring the shadowed sources over a changed source")
+		spoke.Spec.MediaVolumeSpec.Source = MediaVolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{},
+		}
+
+		restored := &v1alpha1.Wordpress{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Annotations).ToNot(HaveKey(conversionDataAnnotation))
+		Expect(restored.Spec.CodeVolumeSpec).To(Equal(hub.Spec.CodeVolumeSpec))
+		Expect(restored.Spec.MediaVolumeSpec.GCSVolumeSource).To(BeNil())
+		Expect(restored.Spec.MediaVolumeSpec.PersistentVolumeClaim).ToNot(BeNil())
+		Expect(restored.Spec.MediaVolumeSpec.HostPath).To(Equal(hub.Spec.MediaVolumeSpec.HostPath))
+	})
+})
@@ -19,7 +19,9 @@ package options
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -30,12 +32,35 @@ var (
 	// GitCloneImage is the image used by the init container that clones the code.
 	GitCloneImage = "docker.io/library/buildpack-deps:stretch-scm"
 
+	// GitResolveInterval is the default interval at which the git references
+	// of the sites' code are resolved to commits.
+	GitResolveInterval = 5 * time.Minute
+
 	// WordpressRuntimeImage is the base image used to run your code.
 	WordpressRuntimeImage = "docker.io/bitpoke/wordpress-runtime:5.8.2"
 
+	// RcloneImage is the image used for uploading, downloading and removing backup artifacts.
+	RcloneImage = "docker.io/rclone/rclone:1.57.0"
+
 	// IngressClass is the default ingress class used used for creating WordPress ingresses.
 	IngressClass = ""
 
+	// RoutingMode is the default way the routes of WordPress sites are exposed,
+	// either ingress or gateway.
+	RoutingMode = "ingress"
+
+	// Gateway is the default Gateway, as namespace/name, to which the HTTPRoutes of
+	// WordPress sites are attached in gateway routing mode.
+	Gateway = ""
+
+	// IngressControllerNamespace is the namespace of the ingress controller
+	// pods, allowed to reach the web pods of sites with network policies.
+	IngressControllerNamespace = "ingress-nginx"
+
+	// PrometheusNamespace is the namespace of the Prometheus pods, allowed to
+	// scrape the web pods of sites with network policies.
+	PrometheusNamespace = "monitoring"
+
 	// LeaderElection determines whether or not to use leader election when starting the manager.
 	LeaderElection = false
 
@@ -54,6 +79,53 @@ var (
 
 	// WatchNamespace sets the Namespace field, which restricts the manager's cache to watch objects in the desired namespace.
 	WatchNamespace = os.Getenv("WATCH_NAMESPACE")
+
+	// Namespace is the namespace in which the operator runs.
+	Namespace = namespace()
+
+	// EnableWebhooks determines whether or not to serve the admission webhooks.
+	EnableWebhooks = false
+
+	// WebhookPort is the port on which the webhook server listens.
+	WebhookPort = 9443
+
+	// WebhookCertDir is the directory in which the webhook server certificates are written.
+	WebhookCertDir = filepath.Join(os.TempDir(), "k8s-webhook-server", "serving-certs")
+
+	// WebhookServiceName is the name of the service that exposes the webhook server.
+	WebhookServiceName = "wordpress-operator-webhook"
+
+	// WebhookSecretName is the name of the secret in which the webhook certificates are stored.
+	WebhookSecretName = "wordpress-operator-webhook-certs"
+
+	// WebhookConfigurationName is the name of the mutating and validating webhook configurations.
+	WebhookConfigurationName = "wordpress-operator"
+
+	// GitWebhookBindAddress is the TCP address on which git push webhooks are
+	// received. The receiver is disabled if it's empty.
+	GitWebhookBindAddress = ""
+
+	// GitWebhookSecret is the secret with which the git push webhooks are
+	// signed, read from the environment to keep it out of the command line.
+	GitWebhookSecret = os.Getenv("GIT_WEBHOOK_SECRET")
+
+	// WPCronInterval is the default interval at which wp-cron.php is requested.
+	WPCronInterval = 30 * time.Second
+
+	// WPCronTimeout is the default timeout of wp-cron.php requests.
+	WPCronTimeout = 30 * time.Second
+
+	// WPCronJitter is the maximum fraction of the interval which is randomly
+	// added to it, to spread the wp-cron.php requests of different sites.
+	WPCronJitter = 0.2
+
+	// WPCronMaxBackoff is the maximum interval between wp-cron.php requests
+	// to a site which keeps failing.
+	WPCronMaxBackoff = 10 * time.Minute
+
+	// WPCronAllowAnyURL allows wp-cron.php to be requested at any URL, not
+	// only at the site's route domains and service.
+	WPCronAllowAnyURL = false
 )
 
 func namespace() string {
@@ -77,12 +149,38 @@ func namespace() string {
 // AddToFlagSet set command line arguments.
 func AddToFlagSet(flag *pflag.FlagSet) {
 	flag.StringVar(&GitCloneImage, "git-clone-image", GitCloneImage, "The image used when cloning code from git.")
+	flag.DurationVar(&GitResolveInterval, "git-resolve-interval", GitResolveInterval,
+		"The default interval at which the git references of the sites' code are resolved to commits. Set it to 0 to disable.")
 	flag.StringVar(&WordpressRuntimeImage, "wordpress-runtime-image", WordpressRuntimeImage, "The base image used for Wordpress.")
+	flag.StringVar(&RcloneImage, "rclone-image", RcloneImage, "The image used for transferring backup artifacts.")
 	flag.StringVar(&IngressClass, "ingress-class", IngressClass, "The default ingress class for WordPress sites.")
+	flag.StringVar(&RoutingMode, "routing-mode", RoutingMode, "The default way the routes of WordPress sites are exposed, either ingress or gateway.")
+	flag.StringVar(&Gateway, "gateway", Gateway, "The default Gateway, as namespace/name, to which HTTPRoutes are attached in gateway routing mode.")
+	flag.StringVar(&IngressControllerNamespace, "ingress-controller-namespace", IngressControllerNamespace,
+		"The namespace of the ingress controller pods, allowed to reach the web pods of sites with network policies.")
+	flag.StringVar(&PrometheusNamespace, "prometheus-namespace", PrometheusNamespace,
+		"The namespace of the Prometheus pods, allowed to scrape the web pods of sites with network policies.")
 	flag.BoolVar(&LeaderElection, "leader-election", LeaderElection, "Enables or disables controller leader election.")
 	flag.StringVar(&LeaderElectionNamespace, "leader-election-namespace", LeaderElectionNamespace, "The namespace in which the leader election resource will be created.")
 	flag.StringVar(&LeaderElectionID, "leader-election-id", LeaderElectionID, "The name of the resource that leader election will use for holding the leader lock.")
 	flag.StringVar(&MetricsBindAddress, "metrics-addr", MetricsBindAddress, "The TCP address that the controller should bind to for serving prometheus metrics."+
 		" It can be set to \"0\" to disable the metrics serving.")
 	flag.StringVar(&HealthProbeBindAddress, "healthz-addr", HealthProbeBindAddress, "The TCP address that the controller should bind to for serving health probes.")
+	flag.BoolVar(&EnableWebhooks, "enable-webhooks", EnableWebhooks, "Enables or disables the admission webhooks.")
+	flag.IntVar(&WebhookPort, "webhook-port", WebhookPort, "The port on which the webhook server listens.")
+	flag.StringVar(&WebhookCertDir, "webhook-cert-dir", WebhookCertDir, "The directory in which the webhook server certificates are written.")
+	flag.StringVar(&WebhookServiceName, "webhook-service-name", WebhookServiceName, "The name of the service that exposes the webhook server.")
+	flag.StringVar(&WebhookSecretName, "webhook-secret-name", WebhookSecretName, "The name of the secret in which the webhook certificates are stored.")
+	flag.StringVar(&WebhookConfigurationName, "webhook-configuration-name", WebhookConfigurationName,
+		"The name of the mutating and validating webhook configurations in which the CA bundle gets injected.")
+	flag.StringVar(&GitWebhookBindAddress, "git-webhook-addr", GitWebhookBindAddress,
+		"The TCP address on which git push webhooks are received, signed with the GIT_WEBHOOK_SECRET env variable."+
+			" The receiver is disabled if it's empty.")
+	flag.DurationVar(&WPCronInterval, "wp-cron-interval", WPCronInterval, "The default interval at which wp-cron.php is requested.")
+	flag.DurationVar(&WPCronTimeout, "wp-cron-timeout", WPCronTimeout, "The default timeout of wp-cron.php requests.")
+	flag.Float64Var(&WPCronJitter, "wp-cron-jitter", WPCronJitter,
+		"The maximum fraction of the interval which is randomly added to it, to spread the wp-cron.php requests of different sites.")
+	flag.DurationVar(&WPCronMaxBackoff, "wp-cron-max-backoff", WPCronMaxBackoff, "The maximum interval between wp-cron.php requests to a failing site.")
+	flag.BoolVar(&WPCronAllowAnyURL, "wp-cron-allow-any-url", WPCronAllowAnyURL,
+		"Allow wp-cron.php to be requested at any URL, not only at the site's route domains and service.")
 }
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/presslabs/controller-util/syncer"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// dbUpgradeRetryBackoff is the time after which a failed database upgrade is
+// retried. Until then, the web pods keep running the previous image.
+const dbUpgradeRetryBackoff = 5 * time.Minute
+
+// upgradeDatabase runs the database upgrade job when the runtime image of an
+// already deployed site changes. It returns the Wordpress from which the web
+// pods should be generated: until the upgrade job completes, the web pods keep
+// running the previously deployed image. After a failed upgrade, it also
+// returns the time left until the upgrade is retried.
+func (r *ReconcileWordpress) upgradeDatabase(ctx context.Context, wp *wordpress.Wordpress) (*wordpress.Wordpress, time.Duration, error) {
+	deployedImage, err := r.deployedImage(ctx, wp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// nothing is deployed yet or the image didn't change
+	if deployedImage == "" || deployedImage == wp.Spec.Image {
+		return wp, 0, nil
+	}
+
+	upgradeSyncer := sync.NewDBUpgradeJobSyncer(wp, r.Client)
+	if err = r.sync(ctx, []syncer.Interface{upgradeSyncer}); err != nil {
+		return nil, 0, err
+	}
+
+	job := upgradeSyncer.Object().(*batchv1.Job)
+	if setDBUpgradedCondition(wp, job) {
+		return wp, 0, nil
+	}
+
+	// a failed upgrade is retried by recreating its job, after a backoff. The
+	// deletion of the job triggers the reconcile which recreates it.
+	retryAfter, failed := dbUpgradeRetryAfter(job, time.Now())
+	if failed && retryAfter == 0 {
+		err = r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if ignoreNotFound(err) != nil {
+			return nil, 0, err
+		}
+	}
+
+	web := wordpress.New(wp.Unwrap().DeepCopy())
+	web.Spec.Image = deployedImage
+
+	return web, retryAfter, nil
+}
+
+// deployedImage returns the image used by the wordpress container of the web
+// deployment or an empty string if the deployment does not exist.
+func (r *ReconcileWordpress) deployedImage(ctx context.Context, wp *wordpress.Wordpress) (string, error) {
+	key := types.NamespacedName{
+		Name:      wp.ComponentName(wordpress.WordpressDeployment),
+		Namespace: wp.Namespace,
+	}
+
+	deploy := &appsv1.Deployment{}
+	if err := r.Get(ctx, key, deploy); err != nil {
+		return "", ignoreNotFound(err)
+	}
+
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		if c.Name == "wordpress" {
+			return c.Image, nil
+		}
+	}
+
+	return "", nil
+}
+
+// setDBUpgradedCondition updates the DBUpgraded condition from the status of
+// the upgrade job and returns true if the upgrade has completed.
+func setDBUpgradedCondition(wp *wordpress.Wordpress, job *batchv1.Job) bool {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		if cond.Type == batchv1.JobComplete {
+			wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionTrue,
+				wordpressv1alpha1.DBUpgradeCompletedReason, fmt.Sprintf("database upgraded for %s", wp.Spec.Image))
+
+			return true
+		}
+
+		if cond.Type == batchv1.JobFailed {
+			wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionFalse,
+				wordpressv1alpha1.DBUpgradeFailedReason,
+				fmt.Sprintf("job %s failed: %s, retrying in %s", job.Name, cond.Message, dbUpgradeRetryBackoff))
+
+			return false
+		}
+	}
+
+	wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionFalse,
+		wordpressv1alpha1.DBUpgradeInProgressReason, fmt.Sprintf("upgrading database for %s", wp.Spec.Image))
+
+	return false
+}
+
+// dbUpgradeRetryAfter returns the time left until the failed upgrade job is
+// retried, which is 0 once dbUpgradeRetryBackoff has passed, and false if the
+// job didn't fail.
+func dbUpgradeRetryAfter(job *batchv1.Job, now time.Time) (time.Duration, bool) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			if left := cond.LastTransitionTime.Add(dbUpgradeRetryBackoff).Sub(now); left > 0 {
+				return left, true
+			}
+
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+// cleanupDBUpgradeJobs removes the database upgrade jobs run for images other
+// than the current one.
+func (r *ReconcileWordpress) cleanupDBUpgradeJobs(ctx context.Context, wp *wordpress.Wordpress) error {
+	selector := wp.ComponentLabels(wordpress.WordpressDBUpgrade)
+	delete(selector, wordpress.DBUpgradeForLabel)
+
+	jobs := &batchv1.JobList{}
+	if err := r.List(ctx, jobs, client.InNamespace(wp.Namespace), client.MatchingLabels(selector)); err != nil {
+		return err
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+
+		if job.Labels[wordpress.DBUpgradeForLabel] == wp.ImageVersion() || !isOwnedBy(job.OwnerReferences, wp) {
+			continue
+		}
+
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); ignoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("Database upgrade", func() {
+	var (
+		c      client.Client
+		r      *ReconcileWordpress
+		wp     *wordpress.Wordpress
+		jobKey types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		c = testutil.NewFakeClient()
+		r = &ReconcileWordpress{Client: c, scheme: c.Scheme()}
+
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "test-uid"},
+			Spec:       wordpressv1alpha1.WordpressSpec{Image: "docker.io/bitpoke/wordpress-runtime:new"},
+		})
+		wp.SetDefaults()
+
+		Expect(c.Create(context.TODO(), &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "wordpress", Image: "docker.io/bitpoke/wordpress-runtime:old"}},
+					},
+				},
+			},
+		})).To(Succeed())
+
+		jobKey = types.NamespacedName{Name: wp.ComponentName(wordpress.WordpressDBUpgrade), Namespace: "default"}
+	})
+
+	failJob := func(at time.Time) {
+		job := &batchv1.Job{}
+		Expect(c.Get(context.TODO(), jobKey, job)).To(Succeed())
+
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(at)},
+		}
+		Expect(c.Update(context.TODO(), job)).To(Succeed())
+	}
+
+	It("keeps the previous image while the upgrade is running", func() {
+		web, retryAfter, err := r.upgradeDatabase(context.TODO(), wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retryAfter).To(BeZero())
+		Expect(web.Spec.Image).To(Equal("docker.io/bitpoke/wordpress-runtime:old"))
+		Expect(c.Get(context.TODO(), jobKey, &batchv1.Job{})).To(Succeed())
+	})
+
+	It("retries failed upgrades after a backoff", func() {
+		_, _, err := r.upgradeDatabase(context.TODO(), wp)
+		Expect(err).NotTo(HaveOccurred())
+
+		failJob(time.Now().Add(-time.Minute))
+
+		web, retryAfter, err := r.upgradeDatabase(context.TODO(), wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retryAfter).To(BeNumerically("~", dbUpgradeRetryBackoff-time.Minute, time.Second))
+		Expect(web.Spec.Image).To(Equal("docker.io/bitpoke/wordpress-runtime:old"))
+		Expect(wp.GetCondition(wordpressv1alpha1.DBUpgradedCondition).Reason).To(Equal(wordpressv1alpha1.DBUpgradeFailedReason))
+		Expect(c.Get(context.TODO(), jobKey, &batchv1.Job{})).To(Succeed())
+
+		failJob(time.Now().Add(-dbUpgradeRetryBackoff))
+
+		_, retryAfter, err = r.upgradeDatabase(context.TODO(), wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(retryAfter).To(BeZero())
+		Expect(apierrors.IsNotFound(c.Get(context.TODO(), jobKey, &batchv1.Job{}))).To(BeTrue())
+
+		// the next reconcile recreates the job
+		_, _, err = r.upgradeDatabase(context.TODO(), wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(context.TODO(), jobKey, &batchv1.Job{})).To(Succeed())
+	})
+
+	It("requeues at the earliest of the given intervals", func() {
+		Expect(earliest()).To(BeZero())
+		Expect(earliest(0, 0)).To(BeZero())
+		Expect(earliest(0, time.Minute, unhealthyRequeueInterval)).To(Equal(unhealthyRequeueInterval))
+		Expect(earliest(dbUpgradeRetryBackoff, 0)).To(Equal(dbUpgradeRetryBackoff))
+	})
+})
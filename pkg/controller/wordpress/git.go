@@ -0,0 +1,175 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/presslabs/controller-util/syncer"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var revisionRegexp = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// RevisionResolver resolves the git reference of a site's code to a commit.
+type RevisionResolver interface {
+	// Resolve returns the commit the reference points to, or an empty string
+	// while the resolution is in progress. The site gets reconciled again
+	// once it completes.
+	Resolve(ctx context.Context, wp *wordpress.Wordpress) (string, error)
+}
+
+// jobRevisionResolver resolves git references with git ls-remote, run in a
+// job. The commit is read from the termination message of the job's pod.
+type jobRevisionResolver struct {
+	client    client.Client
+	apiReader client.Reader
+	recorder  record.EventRecorder
+}
+
+var _ RevisionResolver = &jobRevisionResolver{}
+
+func (r *jobRevisionResolver) Resolve(ctx context.Context, wp *wordpress.Wordpress) (string, error) {
+	jobSyncer := sync.NewGitResolveJobSyncer(wp, r.client)
+	if err := syncer.Sync(ctx, &instrumentedSyncer{jobSyncer}, r.recorder); err != nil {
+		return "", err
+	}
+
+	job := jobSyncer.Object().(*batchv1.Job)
+
+	// the reference changed or got pushed to since the job was started
+	if job.Annotations[wordpress.GitRefAnnotation] != wp.Spec.CodeVolumeSpec.GitDir.GitRef ||
+		job.Annotations[wordpress.GitPushAnnotation] != wp.Annotations[wordpress.GitPushAnnotation] {
+		return "", r.deleteJob(ctx, job)
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch cond.Type {
+		case batchv1.JobComplete:
+			revision, err := r.jobRevision(ctx, job)
+			if err != nil {
+				return "", err
+			}
+
+			return revision, r.deleteJob(ctx, job)
+		case batchv1.JobFailed:
+			if err := r.deleteJob(ctx, job); err != nil {
+				return "", err
+			}
+
+			return "", fmt.Errorf("job %s failed: %s", job.Name, cond.Message)
+		}
+	}
+
+	return "", nil
+}
+
+// jobRevision returns the commit reported by the pod of a completed job.
+func (r *jobRevisionResolver) jobRevision(ctx context.Context, job *batchv1.Job) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			if status.Name != "git" || status.State.Terminated == nil {
+				continue
+			}
+
+			revision := strings.TrimSpace(status.State.Terminated.Message)
+			if !revisionRegexp.MatchString(revision) {
+				return "", fmt.Errorf("job %s reported an invalid revision: %q", job.Name, revision)
+			}
+
+			return revision, nil
+		}
+	}
+
+	return "", fmt.Errorf("job %s has no succeeded pod", job.Name)
+}
+
+func (r *jobRevisionResolver) deleteJob(ctx context.Context, job *batchv1.Job) error {
+	return ignoreNotFound(r.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
+
+// resolveCodeRevision resolves the git reference of the site's code when it's
+// due, recording the commit in status.code. It returns the time after which
+// the reference should be resolved again, or 0 if it's not resolved.
+func (r *ReconcileWordpress) resolveCodeRevision(ctx context.Context, wp *wordpress.Wordpress) time.Duration {
+	interval := wp.GitResolveInterval()
+	if interval <= 0 {
+		wp.Status.Code = nil
+
+		return 0
+	}
+
+	ref := wp.Spec.CodeVolumeSpec.GitDir.GitRef
+	push := wp.Annotations[wordpress.GitPushAnnotation]
+
+	// the reference is resolved right away after pushes
+	if status := wp.Status.Code; status != nil && status.Reference == ref && status.LastResolveTime != nil &&
+		status.LastPush == push {
+		if next := status.LastResolveTime.Add(interval); time.Now().Before(next) {
+			return time.Until(next)
+		}
+	}
+
+	revision, err := r.resolver.Resolve(ctx, wp)
+	if err != nil {
+		log.Error(err, "failed to resolve git reference", "key", client.ObjectKeyFromObject(wp), "ref", ref)
+		r.recorder.Eventf(wp.Unwrap(), corev1.EventTypeWarning, "GitResolveFailed", "resolving %q failed: %s", ref, err)
+
+		// the pods keep running the previously resolved commit, if any, and
+		// the resolution is retried after the interval
+		revision = wp.CodeRevision()
+	} else if revision == "" {
+		return 0
+	} else if wp.CodeRevision() != revision {
+		r.recorder.Eventf(wp.Unwrap(), corev1.EventTypeNormal, "GitResolved", "%q resolved to %s", ref, revision)
+	}
+
+	now := metav1.Now()
+	wp.Status.Code = &wordpressv1alpha1.CodeStatus{
+		Reference:       ref,
+		Revision:        revision,
+		LastResolveTime: &now,
+		LastPush:        push,
+	}
+
+	return interval
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// fakeResolver resolves every reference to the same revision.
+type fakeResolver struct {
+	revision string
+	err      error
+	calls    int
+}
+
+func (f *fakeResolver) Resolve(_ context.Context, _ *wordpress.Wordpress) (string, error) {
+	f.calls++
+
+	return f.revision, f.err
+}
+
+var _ = Describe("Git reference resolution", func() {
+	var (
+		c  client.Client
+		wp *wordpress.Wordpress
+	)
+
+	revision := "0123456789abcdef0123456789abcdef01234567"
+
+	BeforeEach(func() {
+		c = testutil.NewFakeClient()
+
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "test-uid"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				CodeVolumeSpec: &wordpressv1alpha1.CodeVolumeSpec{
+					GitDir: &wordpressv1alpha1.GitVolumeSource{
+						Repository:      "https://github.com/bitpoke/stack-example-wordpress.git",
+						GitRef:          "main",
+						ResolveInterval: &metav1.Duration{Duration: 10 * time.Minute},
+					},
+				},
+			},
+		})
+		wp.SetDefaults()
+	})
+
+	Describe("when resolving with a job", func() {
+		var resolver *jobRevisionResolver
+
+		jobKey := types.NamespacedName{Name: "test-git-resolve", Namespace: "default"}
+
+		finishJob := func(condition batchv1.JobConditionType) {
+			job := &batchv1.Job{}
+			Expect(c.Get(context.TODO(), jobKey, job)).To(Succeed())
+
+			job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue, Message: "test"}}
+			Expect(c.Update(context.TODO(), job)).To(Succeed())
+		}
+
+		// markCreated sets the creation timestamp of the job, which the fake
+		// client doesn't set, so that the syncer doesn't treat it as new
+		markCreated := func() {
+			job := &batchv1.Job{}
+			Expect(c.Get(context.TODO(), jobKey, job)).To(Succeed())
+
+			job.CreationTimestamp = metav1.Now()
+			Expect(c.Update(context.TODO(), job)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			resolver = &jobRevisionResolver{client: c, apiReader: c, recorder: record.NewFakeRecorder(10)}
+		})
+
+		It("should read the revision from the completed job's pod", func() {
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+
+			job := &batchv1.Job{}
+			Expect(c.Get(context.TODO(), jobKey, job)).To(Succeed())
+			Expect(job.Annotations).To(HaveKeyWithValue(wordpress.GitRefAnnotation, "main"))
+
+			finishJob(batchv1.JobComplete)
+			Expect(c.Create(context.TODO(), &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-git-resolve-abcde",
+					Namespace: "default",
+					Labels:    map[string]string{"job-name": jobKey.Name},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodSucceeded,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "git",
+							State: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{Message: revision},
+							},
+						},
+					},
+				},
+			})).To(Succeed())
+
+			Expect(resolver.Resolve(context.TODO(), wp)).To(Equal(revision))
+
+			err := c.Get(context.TODO(), jobKey, &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should retry failed jobs", func() {
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+
+			finishJob(batchv1.JobFailed)
+
+			_, err := resolver.Resolve(context.TODO(), wp)
+			Expect(err).To(HaveOccurred())
+
+			err = c.Get(context.TODO(), jobKey, &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should restart the resolution when the reference changes", func() {
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+			markCreated()
+
+			wp.Spec.CodeVolumeSpec.GitDir.GitRef = "release"
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+
+			err := c.Get(context.TODO(), jobKey, &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should restart the resolution when the reference is pushed to", func() {
+			wp.Annotations = map[string]string{wordpress.GitPushAnnotation: "2026-10-18T10:00:00.1Z"}
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+
+			job := &batchv1.Job{}
+			Expect(c.Get(context.TODO(), jobKey, job)).To(Succeed())
+			Expect(job.Annotations).To(HaveKeyWithValue(wordpress.GitPushAnnotation, "2026-10-18T10:00:00.1Z"))
+			markCreated()
+
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+			Expect(c.Get(context.TODO(), jobKey, job)).To(Succeed())
+
+			wp.Annotations[wordpress.GitPushAnnotation] = "2026-10-18T10:00:00.2Z"
+			Expect(resolver.Resolve(context.TODO(), wp)).To(BeEmpty())
+
+			err := c.Get(context.TODO(), jobKey, &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("when reconciling", func() {
+		var (
+			r        *ReconcileWordpress
+			resolver *fakeResolver
+		)
+
+		BeforeEach(func() {
+			resolver = &fakeResolver{revision: revision}
+			r = &ReconcileWordpress{Client: c, recorder: record.NewFakeRecorder(10), resolver: resolver}
+		})
+
+		It("should record the revision and resolve it again after the interval", func() {
+			Expect(r.resolveCodeRevision(context.TODO(), wp)).To(Equal(10 * time.Minute))
+			Expect(wp.Status.Code.Reference).To(Equal("main"))
+			Expect(wp.Status.Code.Revision).To(Equal(revision))
+			Expect(wp.CodeRevision()).To(Equal(revision))
+
+			after := r.resolveCodeRevision(context.TODO(), wp)
+			Expect(after).To(BeNumerically("~", 10*time.Minute, time.Second))
+			Expect(resolver.calls).To(Equal(1))
+
+			past := metav1.NewTime(time.Now().Add(-time.Hour))
+			wp.Status.Code.LastResolveTime = &past
+
+			r.resolveCodeRevision(context.TODO(), wp)
+			Expect(resolver.calls).To(Equal(2))
+		})
+
+		It("should resolve the reference as soon as it's pushed to", func() {
+			past := metav1.NewTime(time.Now().Add(-time.Minute))
+			wp.Status.Code = &wordpressv1alpha1.CodeStatus{Reference: "main", Revision: revision, LastResolveTime: &past}
+
+			r.resolveCodeRevision(context.TODO(), wp)
+			Expect(resolver.calls).To(BeZero())
+
+			wp.Annotations = map[string]string{wordpress.GitPushAnnotation: past.UTC().Format(time.RFC3339Nano)}
+			r.resolveCodeRevision(context.TODO(), wp)
+			Expect(resolver.calls).To(Equal(1))
+			Expect(wp.Status.Code.LastPush).To(Equal(wp.Annotations[wordpress.GitPushAnnotation]))
+
+			r.resolveCodeRevision(context.TODO(), wp)
+			Expect(resolver.calls).To(Equal(1))
+
+			// pushes within the same second are told apart
+			wp.Annotations[wordpress.GitPushAnnotation] = past.Add(time.Millisecond).UTC().Format(time.RFC3339Nano)
+			r.resolveCodeRevision(context.TODO(), wp)
+			Expect(resolver.calls).To(Equal(2))
+		})
+
+		It("should resolve the reference as soon as it changes", func() {
+			r.resolveCodeRevision(context.TODO(), wp)
+
+			wp.Spec.CodeVolumeSpec.GitDir.GitRef = "release"
+			r.resolveCodeRevision(context.TODO(), wp)
+			Expect(resolver.calls).To(Equal(2))
+			Expect(wp.Status.Code.Reference).To(Equal("release"))
+		})
+
+		It("should keep the previous revision when the resolution fails", func() {
+			r.resolveCodeRevision(context.TODO(), wp)
+
+			past := metav1.NewTime(time.Now().Add(-time.Hour))
+			wp.Status.Code.LastResolveTime = &past
+			resolver.err = errors.New("test")
+
+			Expect(r.resolveCodeRevision(context.TODO(), wp)).To(Equal(10 * time.Minute))
+			Expect(wp.Status.Code.Revision).To(Equal(revision))
+			Expect(wp.Status.Code.LastResolveTime.After(past.Time)).To(BeTrue())
+		})
+
+		It("should clear the status when the resolution is disabled", func() {
+			r.resolveCodeRevision(context.TODO(), wp)
+
+			wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval = &metav1.Duration{}
+			Expect(r.resolveCodeRevision(context.TODO(), wp)).To(BeZero())
+			Expect(wp.Status.Code).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// CertificateGVK is the GroupVersionKind of cert-manager Certificates. They
+// are handled as unstructured objects, as cert-manager is not part of
+// Kubernetes.
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+const (
+	defaultIssuerKind  = "Issuer"
+	defaultIssuerGroup = "cert-manager.io"
+)
+
+// NewCertificateSyncer returns a new sync.Interface for reconciling the
+// cert-manager Certificate of a TLS secret.
+func NewCertificateSyncer(wp *wordpress.Wordpress, group wordpress.TLSGroup, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressCertificate)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(CertificateGVK)
+	obj.SetName(group.SecretName)
+	obj.SetNamespace(wp.Namespace)
+
+	return syncer.NewObjectSyncer("Certificate", wp.Unwrap(), obj, c, func() error {
+		obj.SetLabels(labels.Merge(labels.Merge(obj.GetLabels(), objLabels), controllerLabels))
+
+		issuer := wp.Spec.CertManager.IssuerRef
+
+		issuerRef := map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  defaultIssuerKind,
+			"group": defaultIssuerGroup,
+		}
+
+		if issuer.Kind != "" {
+			issuerRef["kind"] = issuer.Kind
+		}
+
+		if issuer.Group != "" {
+			issuerRef["group"] = issuer.Group
+		}
+
+		if err := unstructured.SetNestedField(obj.Object, group.SecretName, "spec", "secretName"); err != nil {
+			return err
+		}
+
+		if err := unstructured.SetNestedStringSlice(obj.Object, group.Domains, "spec", "dnsNames"); err != nil {
+			return err
+		}
+
+		return unstructured.SetNestedMap(obj.Object, issuerRef, "spec", "issuerRef")
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The Certificate syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				CertManager: &wordpressv1alpha1.CertManagerSpec{
+					IssuerRef: wordpressv1alpha1.CertManagerIssuerReference{Name: "letsencrypt"},
+				},
+			},
+		})
+	})
+
+	sync := func(group wordpress.TLSGroup) *unstructured.Unstructured {
+		s := NewCertificateSyncer(wp, group, nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		return s.Obj.(*unstructured.Unstructured)
+	}
+
+	It("should issue the certificate into the TLS secret", func() {
+		obj := sync(wordpress.TLSGroup{SecretName: "test-tls", Domains: []string{"bitpoke.io", "www.bitpoke.io"}})
+
+		Expect(obj.GetName()).To(Equal("test-tls"))
+		Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{
+			"secretName": "test-tls",
+			"dnsNames":   []interface{}{"bitpoke.io", "www.bitpoke.io"},
+			"issuerRef": map[string]interface{}{
+				"name":  "letsencrypt",
+				"kind":  "Issuer",
+				"group": "cert-manager.io",
+			},
+		}))
+	})
+
+	It("should use the issuer kind and group", func() {
+		wp.Spec.CertManager.IssuerRef.Kind = "ClusterIssuer"
+		wp.Spec.CertManager.IssuerRef.Group = "example.com"
+
+		obj := sync(wordpress.TLSGroup{SecretName: "test-tls", Domains: []string{"bitpoke.io"}})
+
+		issuerRef, _, err := unstructured.NestedStringMap(obj.Object, "spec", "issuerRef")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issuerRef).To(HaveKeyWithValue("kind", "ClusterIssuer"))
+		Expect(issuerRef).To(HaveKeyWithValue("group", "example.com"))
+	})
+})
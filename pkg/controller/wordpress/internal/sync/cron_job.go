@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/appscode/mergo"
+
+	"github.com/presslabs/controller-util/mergo/transformers"
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// NewCronJobSyncer returns a new sync.Interface for reconciling the wp-cron CronJob.
+func NewCronJobSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressCron)
+
+	obj := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.ComponentName(wordpress.WordpressCron),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	var backoffLimit int32
+
+	return syncer.NewObjectSyncer("CronJob", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		obj.Spec.Schedule = wp.Spec.Cron.Schedule
+		obj.Spec.ConcurrencyPolicy = wp.Spec.Cron.ConcurrencyPolicy
+
+		// wp-cron is stopped while the site is being restored
+		suspend := wp.IsBeingRestored()
+		obj.Spec.Suspend = &suspend
+
+		// failed runs are not retried, the due events are run again on schedule
+		obj.Spec.JobTemplate.Labels = objLabels
+		obj.Spec.JobTemplate.Spec.BackoffLimit = &backoffLimit
+
+		template := wp.CronJobPodTemplateSpec()
+		obj.Spec.JobTemplate.Spec.Template.ObjectMeta = template.ObjectMeta
+
+		err := mergo.Merge(&obj.Spec.JobTemplate.Spec.Template.Spec, template.Spec, mergo.WithTransformers(transformers.PodSpec))
+		if err != nil {
+			return err
+		}
+
+		obj.Spec.JobTemplate.Spec.Template.Spec.NodeSelector = wp.Spec.NodeSelector
+		obj.Spec.JobTemplate.Spec.Template.Spec.Tolerations = wp.Spec.Tolerations
+
+		return nil
+	})
+}
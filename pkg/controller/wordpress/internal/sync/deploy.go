@@ -19,6 +19,7 @@ package sync
 import (
 	"errors"
 	"reflect"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -76,10 +77,18 @@ func NewDeploymentSyncer(wp *wordpress.Wordpress, secret *corev1.Secret, c clien
 		obj.Spec.Template.Spec.NodeSelector = wp.Spec.NodeSelector
 		obj.Spec.Template.Spec.Tolerations = wp.Spec.Tolerations
 
-		if wp.Spec.Replicas != nil {
+		// the replicas are owned by the autoscaler, if any
+		switch {
+		case wp.Spec.Autoscaling != nil:
+			if obj.ObjectMeta.CreationTimestamp.IsZero() && wp.Spec.Autoscaling.MinReplicas != nil {
+				obj.Spec.Replicas = wp.Spec.Autoscaling.MinReplicas
+			}
+		case wp.Spec.Replicas != nil:
 			obj.Spec.Replicas = wp.Spec.Replicas
 		}
 
+		scaleForRestore(wp, obj)
+
 		if wp.Spec.DeploymentStrategy != nil {
 			obj.Spec.Strategy = *wp.Spec.DeploymentStrategy
 		}
@@ -87,3 +96,43 @@ func NewDeploymentSyncer(wp *wordpress.Wordpress, secret *corev1.Secret, c clien
 		return nil
 	})
 }
+
+// scaleForRestore scales the deployment down while the site is being restored
+// and back to the previous number of replicas once the restore is done.
+func scaleForRestore(wp *wordpress.Wordpress, obj *appsv1.Deployment) {
+	previous, scaledDown := obj.Annotations[wordpress.ReplicasBeforeRestoreAnnotation]
+
+	if wp.IsBeingRestored() {
+		if !scaledDown {
+			replicas := int32(1)
+			if obj.Spec.Replicas != nil {
+				replicas = *obj.Spec.Replicas
+			}
+
+			obj.Annotations = labels.Merge(obj.Annotations, map[string]string{
+				wordpress.ReplicasBeforeRestoreAnnotation: strconv.Itoa(int(replicas)),
+			})
+		}
+
+		var zero int32
+		obj.Spec.Replicas = &zero
+
+		return
+	}
+
+	if !scaledDown {
+		return
+	}
+
+	delete(obj.Annotations, wordpress.ReplicasBeforeRestoreAnnotation)
+
+	// the replicas set in spec take precedence
+	if wp.Spec.Replicas != nil && wp.Spec.Autoscaling == nil {
+		return
+	}
+
+	if replicas, err := strconv.ParseInt(previous, 10, 32); err == nil {
+		r := int32(replicas)
+		obj.Spec.Replicas = &r
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The scaleForRestore function", func() {
+	var (
+		wp     *wordpress.Wordpress
+		deploy *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		})
+		deploy = &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{Replicas: pointer.Int32Ptr(3)},
+		}
+	})
+
+	It("should leave the replicas alone when the site is not being restored", func() {
+		scaleForRestore(wp, deploy)
+		Expect(*deploy.Spec.Replicas).To(BeEquivalentTo(3))
+		Expect(deploy.Annotations).To(BeEmpty())
+	})
+
+	It("should scale down while restoring and back up afterwards", func() {
+		wp.Annotations = map[string]string{wordpress.RestoreAnnotation: "restore"}
+
+		scaleForRestore(wp, deploy)
+		Expect(*deploy.Spec.Replicas).To(BeEquivalentTo(0))
+
+		// the previous replicas are not overwritten by subsequent syncs
+		scaleForRestore(wp, deploy)
+		Expect(deploy.Annotations).To(HaveKeyWithValue(wordpress.ReplicasBeforeRestoreAnnotation, "3"))
+
+		wp.Annotations = nil
+
+		scaleForRestore(wp, deploy)
+		Expect(*deploy.Spec.Replicas).To(BeEquivalentTo(3))
+		Expect(deploy.Annotations).NotTo(HaveKey(wordpress.ReplicasBeforeRestoreAnnotation))
+	})
+
+	It("should prefer the replicas set in spec after restoring", func() {
+		deploy.Annotations = map[string]string{wordpress.ReplicasBeforeRestoreAnnotation: "3"}
+		wp.Spec.Replicas = pointer.Int32Ptr(5)
+		deploy.Spec.Replicas = wp.Spec.Replicas
+
+		scaleForRestore(wp, deploy)
+		Expect(*deploy.Spec.Replicas).To(BeEquivalentTo(5))
+		Expect(deploy.Annotations).NotTo(HaveKey(wordpress.ReplicasBeforeRestoreAnnotation))
+	})
+})
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// NewGitResolveJobSyncer returns a new sync.Interface for reconciling the Job
+// which resolves the git reference of the code to a commit.
+func NewGitResolveJobSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressGitResolve)
+
+	obj := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.ComponentName(wordpress.WordpressGitResolve),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	var (
+		backoffLimit          int32 = 2
+		activeDeadlineSeconds int64 = 300
+	)
+
+	return syncer.NewObjectSyncer("GitResolveJob", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		// the job spec is immutable, the job gets deleted once it finishes
+		if !obj.CreationTimestamp.IsZero() {
+			return nil
+		}
+
+		obj.Annotations = labels.Merge(obj.Annotations, map[string]string{
+			wordpress.GitRefAnnotation: wp.Spec.CodeVolumeSpec.GitDir.GitRef,
+		})
+
+		if push := wp.Annotations[wordpress.GitPushAnnotation]; push != "" {
+			obj.Annotations[wordpress.GitPushAnnotation] = push
+		}
+
+		obj.Spec.BackoffLimit = &backoffLimit
+		obj.Spec.ActiveDeadlineSeconds = &activeDeadlineSeconds
+		obj.Spec.Template = wp.GitResolvePodTemplateSpec()
+
+		return nil
+	})
+}
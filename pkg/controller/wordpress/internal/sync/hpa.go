@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"errors"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const defaultTargetCPUUtilizationPercentage = 80
+
+var errAutoscalingNotDefined = errors.New(".spec.autoscaling is not defined")
+
+// NewHPASyncer returns a new sync.Interface for reconciling the
+// HorizontalPodAutoscaler of the web Deployment.
+func NewHPASyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressHPA)
+
+	obj := &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.ComponentName(wordpress.WordpressHPA),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	return syncer.NewObjectSyncer("HPA", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		spec := wp.Spec.Autoscaling
+		if spec == nil {
+			return errAutoscalingNotDefined
+		}
+
+		obj.Spec.ScaleTargetRef = autoscalingv1.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       wp.ComponentName(wordpress.WordpressDeployment),
+		}
+
+		minReplicas := int32(1)
+		if spec.MinReplicas != nil {
+			minReplicas = *spec.MinReplicas
+		}
+
+		targetCPU := int32(defaultTargetCPUUtilizationPercentage)
+		if spec.TargetCPUUtilizationPercentage != nil {
+			targetCPU = *spec.TargetCPUUtilizationPercentage
+		}
+
+		obj.Spec.MinReplicas = &minReplicas
+		obj.Spec.MaxReplicas = spec.MaxReplicas
+		obj.Spec.TargetCPUUtilizationPercentage = &targetCPU
+
+		return nil
+	})
+}
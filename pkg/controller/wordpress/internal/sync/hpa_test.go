@@ -0,0 +1,75 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The HPA syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Replicas:    pointer.Int32Ptr(2),
+				Autoscaling: &wordpressv1alpha1.AutoscalingSpec{MaxReplicas: 10},
+			},
+		})
+	})
+
+	It("should autoscale the web deployment", func() {
+		s := NewHPASyncer(wp, nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		obj := s.Obj.(*autoscalingv1.HorizontalPodAutoscaler)
+		Expect(obj.Spec.ScaleTargetRef).To(Equal(autoscalingv1.CrossVersionObjectReference{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "test",
+		}))
+		Expect(*obj.Spec.MinReplicas).To(BeEquivalentTo(1))
+		Expect(obj.Spec.MaxReplicas).To(BeEquivalentTo(10))
+		Expect(*obj.Spec.TargetCPUUtilizationPercentage).To(BeEquivalentTo(80))
+	})
+
+	It("should leave the deployment replicas to the autoscaler", func() {
+		s := NewDeploymentSyncer(wp, &corev1.Secret{}, nil).(*syncer.ObjectSyncer)
+		obj := s.Obj.(*appsv1.Deployment)
+		obj.CreationTimestamp = metav1.Now()
+		obj.Spec.Selector = metav1.SetAsLabelSelector(wp.WebPodLabels())
+		obj.Spec.Replicas = pointer.Int32Ptr(7)
+
+		Expect(s.SyncFn()).To(Succeed())
+		Expect(*obj.Spec.Replicas).To(BeEquivalentTo(7))
+
+		wp.Spec.Autoscaling = nil
+
+		Expect(s.SyncFn()).To(Succeed())
+		Expect(*obj.Spec.Replicas).To(BeEquivalentTo(2))
+	})
+})
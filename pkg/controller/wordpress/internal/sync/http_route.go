@@ -0,0 +1,157 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// HTTPRouteGVK is the GroupVersionKind of Gateway API HTTPRoutes. They are
+// handled as unstructured objects, as the Gateway API is not part of
+// Kubernetes.
+var HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+var errNoGateway = errors.New("no gateway is set for the site's HTTPRoutes")
+
+// NewHTTPRouteSyncer returns a new sync.Interface for reconciling the web
+// HTTPRoute of a domain.
+func NewHTTPRouteSyncer(wp *wordpress.Wordpress, domain string, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressHTTPRoute)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(HTTPRouteGVK)
+	obj.SetName(wp.HTTPRouteName(domain))
+	obj.SetNamespace(wp.Namespace)
+
+	backendRef := map[string]interface{}{
+		"name": wp.ComponentName(wordpress.WordpressService),
+		"port": int64(80),
+	}
+
+	return syncer.NewObjectSyncer("HTTPRoute", wp.Unwrap(), obj, c, func() error {
+		obj.SetLabels(labels.Merge(labels.Merge(obj.GetLabels(), objLabels), controllerLabels))
+
+		gw := wp.Gateway()
+		if gw == nil {
+			return errNoGateway
+		}
+
+		parentRef := map[string]interface{}{
+			"name":      gw.Name,
+			"namespace": gw.Namespace,
+		}
+
+		if gw.SectionName != "" {
+			parentRef["sectionName"] = gw.SectionName
+		}
+
+		matches := []interface{}{}
+		seen := map[string]bool{}
+
+		for _, route := range wp.ActiveRoutes() {
+			path := route.Path
+			if path == "" {
+				path = "/"
+			}
+
+			if strings.ToLower(route.Domain) != domain || seen[path] {
+				continue
+			}
+
+			seen[path] = true
+			matches = append(matches, map[string]interface{}{
+				"path": map[string]interface{}{"type": "PathPrefix", "value": path},
+			})
+		}
+
+		rules := []interface{}{}
+
+		for _, r := range wp.Redirects() {
+			if r.Host == domain {
+				rules = append(rules, redirectRule(r))
+			}
+		}
+
+		if !wp.IsRedirected(domain) {
+			rules = append(rules, map[string]interface{}{
+				"matches":     matches,
+				"backendRefs": []interface{}{backendRef},
+			})
+		}
+
+		if err := unstructured.SetNestedSlice(obj.Object, []interface{}{parentRef}, "spec", "parentRefs"); err != nil {
+			return err
+		}
+
+		if err := unstructured.SetNestedStringSlice(obj.Object, []string{domain}, "spec", "hostnames"); err != nil {
+			return err
+		}
+
+		return unstructured.SetNestedSlice(obj.Object, rules, "spec", "rules")
+	})
+}
+
+// redirectRule returns an HTTPRoute rule rendering the redirect as a
+// RequestRedirect filter.
+func redirectRule(r wordpress.Redirect) map[string]interface{} {
+	redirect := map[string]interface{}{
+		"statusCode": int64(r.StatusCode),
+	}
+
+	if r.Target.Scheme != "" {
+		redirect["scheme"] = r.Target.Scheme
+	}
+
+	if hostname := r.Target.Hostname(); hostname != "" {
+		redirect["hostname"] = hostname
+	}
+
+	if port, err := strconv.ParseInt(r.Target.Port(), 10, 64); err == nil {
+		redirect["port"] = port
+	}
+
+	if !r.KeepPath {
+		fullPath := r.Target.Path
+		if fullPath == "" {
+			fullPath = "/"
+		}
+
+		redirect["path"] = map[string]interface{}{"type": "ReplaceFullPath", "replaceFullPath": fullPath}
+	}
+
+	return map[string]interface{}{
+		"matches": []interface{}{
+			map[string]interface{}{
+				"path": map[string]interface{}{"type": "PathPrefix", "value": r.Path},
+			},
+		},
+		"filters": []interface{}{
+			map[string]interface{}{"type": "RequestRedirect", "requestRedirect": redirect},
+		},
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The HTTPRoute syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "bitpoke.io"},
+					{Domain: "docs.bitpoke.io", Path: "/"},
+					{Domain: "Bitpoke.io", Path: "/blog"},
+				},
+				Routing: &wordpressv1alpha1.RoutingSpec{
+					Mode:    wordpressv1alpha1.RoutingModeGateway,
+					Gateway: &wordpressv1alpha1.GatewayReference{Name: "public", SectionName: "https"},
+				},
+			},
+		})
+	})
+
+	sync := func(domain string) (*unstructured.Unstructured, error) {
+		s := NewHTTPRouteSyncer(wp, domain, nil).(*syncer.ObjectSyncer)
+		err := s.SyncFn()
+
+		return s.Obj.(*unstructured.Unstructured), err
+	}
+
+	It("should route the paths of the domain to the web service", func() {
+		obj, err := sync("bitpoke.io")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(obj.GetName()).To(Equal(wp.HTTPRouteName("bitpoke.io")))
+		Expect(obj.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/component", "web"))
+		Expect(obj.Object["spec"]).To(Equal(map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": "public", "namespace": "default", "sectionName": "https"},
+			},
+			"hostnames": []interface{}{"bitpoke.io"},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/"}},
+						map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/blog"}},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "test", "port": int64(80)},
+					},
+				},
+			},
+		}))
+	})
+
+	It("should fail without a gateway", func() {
+		wp.Spec.Routing.Gateway = nil
+
+		_, err := sync("bitpoke.io")
+		Expect(err).To(MatchError(errNoGateway))
+	})
+
+	It("should render the redirects as RequestRedirect filters", func() {
+		wp.Spec.CanonicalHost = "bitpoke.io"
+		wp.Spec.Redirects = []wordpressv1alpha1.RedirectSpec{
+			{Host: "bitpoke.io", Path: "/old", Target: "https://docs.bitpoke.io:8443"},
+		}
+
+		obj, err := sync("bitpoke.io")
+		Expect(err).NotTo(HaveOccurred())
+
+		rules, _, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0]).To(Equal(map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/old"}},
+			},
+			"filters": []interface{}{
+				map[string]interface{}{
+					"type": "RequestRedirect",
+					"requestRedirect": map[string]interface{}{
+						"scheme":     "https",
+						"hostname":   "docs.bitpoke.io",
+						"port":       int64(8443),
+						"path":       map[string]interface{}{"type": "ReplaceFullPath", "replaceFullPath": "/"},
+						"statusCode": int64(301),
+					},
+				},
+			},
+		}))
+
+		obj, err = sync("docs.bitpoke.io")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.Object["spec"].(map[string]interface{})["rules"]).To(Equal([]interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/"}},
+				},
+				"filters": []interface{}{
+					map[string]interface{}{
+						"type": "RequestRedirect",
+						"requestRedirect": map[string]interface{}{
+							"scheme":     "http",
+							"hostname":   "bitpoke.io",
+							"statusCode": int64(301),
+						},
+					},
+				},
+			},
+		}))
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 package sync
 
 import (
+	"strings"
+
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -24,7 +26,6 @@ import (
 
 	"github.com/presslabs/controller-util/syncer"
 
-	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
 	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
 )
 
@@ -72,13 +73,15 @@ func upsertPath(rules []netv1.IngressRule, domain, path string, bk netv1.Ingress
 	return rules
 }
 
-// NewIngressSyncer returns a new sync.Interface for reconciling web Ingress.
-func NewIngressSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+// NewIngressSyncer returns a new sync.Interface for reconciling the web
+// Ingress of an ingress group of the site. The empty group is the site's
+// Ingress.
+func NewIngressSyncer(wp *wordpress.Wordpress, group string, c client.Client) syncer.Interface {
 	objLabels := wp.ComponentLabels(wordpress.WordpressIngress)
 
 	obj := &netv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      wp.ComponentName(wordpress.WordpressIngress),
+			Name:      wp.IngressName(group),
 			Namespace: wp.Namespace,
 		},
 	}
@@ -97,38 +100,48 @@ func NewIngressSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface
 			obj.ObjectMeta.Annotations = make(map[string]string)
 		}
 
-		for k, v := range wp.Spec.IngressAnnotations {
+		for k, v := range wp.IngressAnnotations(group) {
 			obj.ObjectMeta.Annotations[k] = v
 		}
 		delete(obj.ObjectMeta.Annotations, ingressClassAnnotationKey)
 
-		if options.IngressClass != "" {
-			obj.Spec.IngressClassName = &options.IngressClass
-		} else {
-			obj.Spec.IngressClassName = nil
-		}
+		obj.Spec.IngressClassName = wp.IngressClassName(group)
+
+		routes := wp.ActiveRoutes()
+		domains := map[string]bool{}
 
 		rules := []netv1.IngressRule{}
-		for _, route := range wp.Spec.Routes {
+		for _, route := range routes {
+			// the requests for redirected domains are served by the redirect ingresses
+			if wp.IsRedirected(route.Domain) || wp.RouteIngressGroup(route) != group {
+				continue
+			}
+
 			path := route.Path
 			if path == "" {
 				path = "/"
 			}
 			rules = upsertPath(rules, route.Domain, path, bk)
+			domains[strings.ToLower(route.Domain)] = true
 		}
 
 		obj.Spec.Rules = rules
 
-		if len(wp.Spec.TLSSecretRef) > 0 {
-			tls := netv1.IngressTLS{
-				SecretName: string(wp.Spec.TLSSecretRef),
+		obj.Spec.TLS = nil
+		for _, tlsGroup := range wp.TLSGroups() {
+			hosts := []string{}
+			for _, domain := range tlsGroup.Domains {
+				if domains[domain] {
+					hosts = append(hosts, domain)
+				}
 			}
-			for _, route := range wp.Spec.Routes {
-				tls.Hosts = append(tls.Hosts, route.Domain)
+
+			if len(hosts) > 0 {
+				obj.Spec.TLS = append(obj.Spec.TLS, netv1.IngressTLS{
+					SecretName: tlsGroup.SecretName,
+					Hosts:      hosts,
+				})
 			}
-			obj.Spec.TLS = []netv1.IngressTLS{tls}
-		} else {
-			obj.Spec.TLS = nil
 		}
 
 		return nil
@@ -20,9 +20,66 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/presslabs/controller-util/syncer"
 	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
 )
 
+var _ = Describe("The Ingress syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "bitpoke.io"},
+					{Domain: "admin.bitpoke.io", IngressGroup: "internal"},
+				},
+				TLSSecretRef: "bitpoke-tls",
+				IngressAnnotations: map[string]string{
+					ingressClassAnnotationKey: "nginx",
+					"site":                    "bitpoke",
+				},
+				Ingress: &wordpressv1alpha1.IngressSpec{
+					IngressClassName: "public",
+					Groups: []wordpressv1alpha1.IngressGroup{
+						{Name: "internal", IngressClassName: "internal", Annotations: map[string]string{"group": "internal"}},
+					},
+				},
+			},
+		})
+	})
+
+	sync := func(group string) *netv1.Ingress {
+		s := NewIngressSyncer(wp, group, nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		return s.Obj.(*netv1.Ingress)
+	}
+
+	It("should expose the routes of each group with its own Ingress", func() {
+		obj := sync("")
+		Expect(obj.Name).To(Equal("test"))
+		Expect(*obj.Spec.IngressClassName).To(Equal("public"))
+		Expect(obj.Annotations).To(Equal(map[string]string{"site": "bitpoke"}))
+		Expect(obj.Spec.Rules).To(HaveLen(1))
+		Expect(obj.Spec.Rules[0].Host).To(Equal("bitpoke.io"))
+		Expect(obj.Spec.TLS).To(Equal([]netv1.IngressTLS{{SecretName: "bitpoke-tls", Hosts: []string{"bitpoke.io"}}}))
+
+		obj = sync("internal")
+		Expect(obj.Name).To(Equal("test-internal"))
+		Expect(*obj.Spec.IngressClassName).To(Equal("internal"))
+		Expect(obj.Annotations).To(Equal(map[string]string{"site": "bitpoke", "group": "internal"}))
+		Expect(obj.Spec.Rules).To(HaveLen(1))
+		Expect(obj.Spec.Rules[0].Host).To(Equal("admin.bitpoke.io"))
+		Expect(obj.Spec.TLS).To(Equal([]netv1.IngressTLS{{SecretName: "bitpoke-tls", Hosts: []string{"admin.bitpoke.io"}}}))
+	})
+})
+
 var _ = Describe("The upsertPath function", func() {
 	var (
 		rules          []netv1.IngressRule
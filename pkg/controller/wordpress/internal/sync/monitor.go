@@ -0,0 +1,140 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var (
+	// ServiceMonitorGVK is the GroupVersionKind of Prometheus Operator
+	// ServiceMonitors. They are handled as unstructured objects, as the
+	// Prometheus Operator is not part of Kubernetes.
+	ServiceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	// PodMonitorGVK is the GroupVersionKind of Prometheus Operator
+	// PodMonitors.
+	PodMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+)
+
+// MonitorGVK returns the GroupVersionKind of the site's monitor.
+func MonitorGVK(wp *wordpress.Wordpress) schema.GroupVersionKind {
+	if wp.Spec.Monitoring != nil && wp.Spec.Monitoring.Kind == wordpressv1alpha1.PodMonitorKind {
+		return PodMonitorGVK
+	}
+
+	return ServiceMonitorGVK
+}
+
+// NewMonitorSyncer returns a new sync.Interface for reconciling the
+// ServiceMonitor or the PodMonitor which scrapes the web pods.
+func NewMonitorSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressMonitor)
+	gvk := MonitorGVK(wp)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(wp.ComponentName(wordpress.WordpressMonitor))
+	obj.SetNamespace(wp.Namespace)
+
+	return syncer.NewObjectSyncer(gvk.Kind, wp.Unwrap(), obj, c, func() error {
+		spec := wp.Spec.Monitoring
+		if spec == nil {
+			spec = &wordpressv1alpha1.MonitoringSpec{}
+		}
+
+		obj.SetLabels(labels.Merge(labels.Merge(labels.Merge(obj.GetLabels(), spec.Labels), objLabels), controllerLabels))
+
+		endpoint := map[string]interface{}{
+			"port": "prometheus",
+		}
+
+		if spec.Interval != nil {
+			endpoint["interval"] = model.Duration(spec.Interval.Duration).String()
+		}
+
+		if spec.ScrapeTimeout != nil {
+			endpoint["scrapeTimeout"] = model.Duration(spec.ScrapeTimeout.Duration).String()
+		}
+
+		if len(spec.Relabelings) > 0 {
+			endpoint["relabelings"] = relabelings(spec.Relabelings)
+		}
+
+		// the web Service has the labels of the web pods
+		selector := map[string]interface{}{}
+		for k, v := range wp.WebPodLabels() {
+			selector[k] = v
+		}
+
+		if err := unstructured.SetNestedMap(obj.Object, selector, "spec", "selector", "matchLabels"); err != nil {
+			return err
+		}
+
+		endpointsField := "endpoints"
+		if gvk == PodMonitorGVK {
+			endpointsField = "podMetricsEndpoints"
+		}
+
+		return unstructured.SetNestedSlice(obj.Object, []interface{}{endpoint}, "spec", endpointsField)
+	})
+}
+
+func relabelings(in []wordpressv1alpha1.RelabelConfig) []interface{} {
+	out := make([]interface{}, len(in))
+
+	for i, r := range in {
+		config := map[string]interface{}{}
+
+		if len(r.SourceLabels) > 0 {
+			sourceLabels := make([]interface{}, len(r.SourceLabels))
+			for j := range r.SourceLabels {
+				sourceLabels[j] = r.SourceLabels[j]
+			}
+
+			config["sourceLabels"] = sourceLabels
+		}
+
+		for k, v := range map[string]string{
+			"separator":   r.Separator,
+			"targetLabel": r.TargetLabel,
+			"regex":       r.Regex,
+			"replacement": r.Replacement,
+			"action":      r.Action,
+		} {
+			if v != "" {
+				config[k] = v
+			}
+		}
+
+		if r.Modulus != 0 {
+			config["modulus"] = int64(r.Modulus)
+		}
+
+		out[i] = config
+	}
+
+	return out
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The monitor syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Monitoring: &wordpressv1alpha1.MonitoringSpec{
+					Interval: &metav1.Duration{Duration: 90 * time.Second},
+					Labels:   map[string]string{"release": "prometheus"},
+					Relabelings: []wordpressv1alpha1.RelabelConfig{
+						{SourceLabels: []string{"__meta_kubernetes_pod_node_name"}, TargetLabel: "node", Action: "replace"},
+					},
+				},
+			},
+		})
+	})
+
+	sync := func() *unstructured.Unstructured {
+		s := NewMonitorSyncer(wp, nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		return s.Obj.(*unstructured.Unstructured)
+	}
+
+	It("should scrape the prometheus port of the web Service", func() {
+		obj := sync()
+
+		Expect(obj.GroupVersionKind()).To(Equal(ServiceMonitorGVK))
+		Expect(obj.GetName()).To(Equal("test"))
+		Expect(obj.GetLabels()).To(HaveKeyWithValue("release", "prometheus"))
+		Expect(obj.GetLabels()).To(HaveKeyWithValue("app.kubernetes.io/component", "web"))
+
+		selector, _, err := unstructured.NestedStringMap(obj.Object, "spec", "selector", "matchLabels")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector).To(Equal(map[string]string(wp.WebPodLabels())))
+
+		endpoints, _, err := unstructured.NestedSlice(obj.Object, "spec", "endpoints")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoints).To(Equal([]interface{}{
+			map[string]interface{}{
+				"port":     "prometheus",
+				"interval": "1m30s",
+				"relabelings": []interface{}{
+					map[string]interface{}{
+						"sourceLabels": []interface{}{"__meta_kubernetes_pod_node_name"},
+						"targetLabel":  "node",
+						"action":       "replace",
+					},
+				},
+			},
+		}))
+	})
+
+	It("should scrape the web pods directly with a PodMonitor", func() {
+		wp.Spec.Monitoring.Kind = wordpressv1alpha1.PodMonitorKind
+
+		obj := sync()
+
+		Expect(obj.GroupVersionKind()).To(Equal(PodMonitorGVK))
+
+		endpoints, _, err := unstructured.NestedSlice(obj.Object, "spec", "podMetricsEndpoints")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoints).To(HaveLen(1))
+	})
+})
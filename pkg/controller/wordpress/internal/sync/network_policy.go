@@ -0,0 +1,179 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NewWebNetworkPolicySyncer returns a new sync.Interface for reconciling the
+// NetworkPolicy of the web pods.
+func NewWebNetworkPolicySyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressWebNetworkPolicy)
+
+	obj := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.ComponentName(wordpress.WordpressWebNetworkPolicy),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	return syncer.NewObjectSyncer("WebNetworkPolicy", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		spec := networkPolicySpec(wp)
+
+		ingressFrom := spec.IngressFrom
+		if len(ingressFrom) == 0 {
+			ingressFrom = defaultIngressPeers(wp)
+		}
+
+		metricsFrom := spec.MetricsFrom
+		if len(metricsFrom) == 0 && options.PrometheusNamespace != "" {
+			metricsFrom = []netv1.NetworkPolicyPeer{namespacePeer(options.PrometheusNamespace)}
+		}
+
+		// the operator requests wp-cron.php
+		httpFrom := append([]netv1.NetworkPolicyPeer{}, ingressFrom...)
+		httpFrom = append(httpFrom, namespacePeer(options.Namespace))
+
+		rules := []netv1.NetworkPolicyIngressRule{
+			{
+				From:  httpFrom,
+				Ports: []netv1.NetworkPolicyPort{tcpPort(wordpress.InternalHTTPPort)},
+			},
+		}
+
+		if len(metricsFrom) > 0 {
+			rules = append(rules, netv1.NetworkPolicyIngressRule{
+				From:  metricsFrom,
+				Ports: []netv1.NetworkPolicyPort{tcpPort(wordpress.MetricsExporterPort)},
+			})
+		}
+
+		obj.Spec.PodSelector = metav1.LabelSelector{MatchLabels: wp.WebPodLabels()}
+		obj.Spec.Ingress = rules
+
+		setEgress(obj, spec)
+
+		return nil
+	})
+}
+
+// NewJobNetworkPolicySyncer returns a new sync.Interface for reconciling the
+// NetworkPolicy of the wp-cli and wp-cron job pods, which accept no traffic.
+func NewJobNetworkPolicySyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressJobNetworkPolicy)
+
+	obj := &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.ComponentName(wordpress.WordpressJobNetworkPolicy),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	return syncer.NewObjectSyncer("JobNetworkPolicy", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		obj.Spec.PodSelector = wp.JobPodSelector()
+		obj.Spec.Ingress = nil
+
+		setEgress(obj, networkPolicySpec(wp))
+
+		return nil
+	})
+}
+
+func networkPolicySpec(wp *wordpress.Wordpress) *wordpressv1alpha1.NetworkPolicySpec {
+	if wp.Spec.NetworkPolicy == nil {
+		return &wordpressv1alpha1.NetworkPolicySpec{}
+	}
+
+	return wp.Spec.NetworkPolicy
+}
+
+// defaultIngressPeers returns the peers in front of the site, which are the
+// Gateway pods in gateway routing mode and the ingress controller pods
+// otherwise.
+func defaultIngressPeers(wp *wordpress.Wordpress) []netv1.NetworkPolicyPeer {
+	namespace := options.IngressControllerNamespace
+
+	if wp.RoutingMode() == wordpressv1alpha1.RoutingModeGateway {
+		if gw := wp.Gateway(); gw != nil {
+			namespace = gw.Namespace
+		}
+	}
+
+	if namespace == "" {
+		return nil
+	}
+
+	return []netv1.NetworkPolicyPeer{namespacePeer(namespace)}
+}
+
+// setEgress restricts the egress of the pods to the DNS lookups and the
+// destinations set in spec, if any.
+func setEgress(obj *netv1.NetworkPolicy, spec *wordpressv1alpha1.NetworkPolicySpec) {
+	obj.Spec.PolicyTypes = []netv1.PolicyType{netv1.PolicyTypeIngress}
+	obj.Spec.Egress = nil
+
+	if spec.Egress == nil {
+		return
+	}
+
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	dns := intstr.FromInt(53)
+
+	obj.Spec.PolicyTypes = append(obj.Spec.PolicyTypes, netv1.PolicyTypeEgress)
+	obj.Spec.Egress = append([]netv1.NetworkPolicyEgressRule{
+		{
+			Ports: []netv1.NetworkPolicyPort{
+				{Protocol: &udp, Port: &dns},
+				{Protocol: &tcp, Port: &dns},
+			},
+		},
+	}, spec.Egress...)
+}
+
+func namespacePeer(namespace string) netv1.NetworkPolicyPeer {
+	return netv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{namespaceNameLabel: namespace},
+		},
+	}
+}
+
+func tcpPort(port int) netv1.NetworkPolicyPort {
+	tcp := corev1.ProtocolTCP
+	p := intstr.FromInt(port)
+
+	return netv1.NetworkPolicyPort{Protocol: &tcp, Port: &p}
+}
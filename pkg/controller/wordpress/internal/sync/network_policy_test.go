@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The NetworkPolicy syncers", func() {
+	var (
+		wp        *wordpress.Wordpress
+		namespace string
+	)
+
+	BeforeEach(func() {
+		namespace = options.Namespace
+		options.Namespace = "wordpress-operator"
+
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				NetworkPolicy: &wordpressv1alpha1.NetworkPolicySpec{},
+			},
+		})
+	})
+
+	AfterEach(func() {
+		options.Namespace = namespace
+	})
+
+	sync := func(s syncer.Interface) *netv1.NetworkPolicy {
+		Expect(s.(*syncer.ObjectSyncer).SyncFn()).To(Succeed())
+
+		return s.Object().(*netv1.NetworkPolicy)
+	}
+
+	It("should allow http from the ingress controller and the operator and metrics scrapes from prometheus", func() {
+		obj := sync(NewWebNetworkPolicySyncer(wp, nil))
+
+		Expect(obj.Name).To(Equal("test-web"))
+		Expect(obj.Spec.PodSelector.MatchLabels).To(Equal(map[string]string(wp.WebPodLabels())))
+		Expect(obj.Spec.PolicyTypes).To(Equal([]netv1.PolicyType{netv1.PolicyTypeIngress}))
+		Expect(obj.Spec.Egress).To(BeNil())
+		Expect(obj.Spec.Ingress).To(Equal([]netv1.NetworkPolicyIngressRule{
+			{
+				From:  []netv1.NetworkPolicyPeer{namespacePeer("ingress-nginx"), namespacePeer("wordpress-operator")},
+				Ports: []netv1.NetworkPolicyPort{tcpPort(wordpress.InternalHTTPPort)},
+			},
+			{
+				From:  []netv1.NetworkPolicyPeer{namespacePeer("monitoring")},
+				Ports: []netv1.NetworkPolicyPort{tcpPort(wordpress.MetricsExporterPort)},
+			},
+		}))
+	})
+
+	It("should allow http from the gateway in gateway routing mode", func() {
+		wp.Spec.Routing = &wordpressv1alpha1.RoutingSpec{
+			Mode:    wordpressv1alpha1.RoutingModeGateway,
+			Gateway: &wordpressv1alpha1.GatewayReference{Name: "public", Namespace: "gateways"},
+		}
+
+		obj := sync(NewWebNetworkPolicySyncer(wp, nil))
+		Expect(obj.Spec.Ingress[0].From).To(Equal([]netv1.NetworkPolicyPeer{
+			namespacePeer("gateways"), namespacePeer("wordpress-operator"),
+		}))
+	})
+
+	It("should isolate the wp-cli job pods and restrict their egress", func() {
+		db := netv1.NetworkPolicyEgressRule{
+			To:    []netv1.NetworkPolicyPeer{namespacePeer("mysql")},
+			Ports: []netv1.NetworkPolicyPort{tcpPort(3306)},
+		}
+		wp.Spec.NetworkPolicy.Egress = []netv1.NetworkPolicyEgressRule{db}
+
+		obj := sync(NewJobNetworkPolicySyncer(wp, nil))
+
+		Expect(obj.Name).To(Equal("test-wp-cli"))
+		selector, err := metav1.LabelSelectorAsSelector(&obj.Spec.PodSelector)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector.Matches(labels.Set(wp.JobPodTemplateSpec("wp", "help").Labels))).To(BeTrue())
+		Expect(selector.Matches(labels.Set(wp.CronJobPodTemplateSpec().Labels))).To(BeTrue())
+		Expect(selector.Matches(wp.WebPodLabels())).To(BeFalse())
+		Expect(obj.Spec.PolicyTypes).To(Equal([]netv1.PolicyType{netv1.PolicyTypeIngress, netv1.PolicyTypeEgress}))
+		Expect(obj.Spec.Ingress).To(BeEmpty())
+		Expect(obj.Spec.Egress).To(HaveLen(2))
+		Expect(obj.Spec.Egress[0].To).To(BeEmpty())
+		Expect(obj.Spec.Egress[1]).To(Equal(db))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// NewPDBSyncer returns a new sync.Interface for reconciling the
+// PodDisruptionBudget of the web pods.
+func NewPDBSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressPDB)
+
+	obj := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.ComponentName(wordpress.WordpressPDB),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	return syncer.NewObjectSyncer("PDB", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		obj.Spec.Selector = metav1.SetAsLabelSelector(wp.WebPodLabels())
+		obj.Spec.MinAvailable = nil
+		obj.Spec.MaxUnavailable = nil
+
+		spec := wp.Spec.DisruptionBudget
+
+		switch {
+		case spec != nil && spec.MinAvailable != nil:
+			minAvailable := *spec.MinAvailable
+			obj.Spec.MinAvailable = &minAvailable
+		case spec != nil && spec.MaxUnavailable != nil:
+			maxUnavailable := *spec.MaxUnavailable
+			obj.Spec.MaxUnavailable = &maxUnavailable
+		default:
+			maxUnavailable := intstr.FromInt(1)
+			obj.Spec.MaxUnavailable = &maxUnavailable
+		}
+
+		return nil
+	})
+}
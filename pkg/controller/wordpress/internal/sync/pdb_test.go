@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The PDB syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				DisruptionBudget: &wordpressv1alpha1.DisruptionBudgetSpec{},
+			},
+		})
+	})
+
+	It("should allow one unavailable web pod by default", func() {
+		s := NewPDBSyncer(wp, nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		obj := s.Obj.(*policyv1.PodDisruptionBudget)
+		Expect(obj.Spec.Selector).To(Equal(metav1.SetAsLabelSelector(wp.WebPodLabels())))
+		Expect(obj.Spec.MinAvailable).To(BeNil())
+		Expect(*obj.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+
+		minAvailable := intstr.FromString("50%")
+		wp.Spec.DisruptionBudget.MinAvailable = &minAvailable
+
+		Expect(s.SyncFn()).To(Succeed())
+		Expect(*obj.Spec.MinAvailable).To(Equal(minAvailable))
+		Expect(obj.Spec.MaxUnavailable).To(BeNil())
+	})
+})
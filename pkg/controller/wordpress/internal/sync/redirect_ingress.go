@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"strconv"
+
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	redirectAnnotationKey     = "nginx.ingress.kubernetes.io/permanent-redirect"
+	redirectCodeAnnotationKey = "nginx.ingress.kubernetes.io/permanent-redirect-code"
+)
+
+// NewRedirectIngressSyncer returns a new sync.Interface for reconciling the
+// Ingress of a site redirect. The redirect is rendered as ingress-nginx
+// annotations.
+func NewRedirectIngressSyncer(wp *wordpress.Wordpress, r wordpress.Redirect, c client.Client) syncer.Interface {
+	objLabels := wp.ComponentLabels(wordpress.WordpressRedirect)
+
+	obj := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      wp.RedirectName(r),
+			Namespace: wp.Namespace,
+		},
+	}
+
+	bk := netv1.IngressBackend{
+		Service: &netv1.IngressServiceBackend{
+			Name: wp.ComponentName(wordpress.WordpressService),
+			Port: netv1.ServiceBackendPort{Name: "http"},
+		},
+	}
+
+	return syncer.NewObjectSyncer("RedirectIngress", wp.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
+
+		if len(obj.ObjectMeta.Annotations) == 0 {
+			obj.ObjectMeta.Annotations = make(map[string]string)
+		}
+
+		target := r.Target.String()
+		if r.KeepPath {
+			target += "$request_uri"
+		}
+
+		obj.ObjectMeta.Annotations[redirectAnnotationKey] = target
+		obj.ObjectMeta.Annotations[redirectCodeAnnotationKey] = strconv.Itoa(r.StatusCode)
+
+		obj.Spec.IngressClassName = wp.IngressClassName(wp.DomainIngressGroup(r.Host))
+
+		obj.Spec.Rules = upsertPath(nil, r.Host, r.Path, bk)
+
+		obj.Spec.TLS = nil
+		for _, group := range wp.TLSGroups() {
+			for _, domain := range group.Domains {
+				if domain == r.Host {
+					obj.Spec.TLS = append(obj.Spec.TLS, netv1.IngressTLS{
+						SecretName: group.SecretName,
+						Hosts:      []string{r.Host},
+					})
+				}
+			}
+		}
+
+		return nil
+	})
+}
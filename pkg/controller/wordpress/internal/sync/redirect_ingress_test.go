@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The redirect Ingress syncer", func() {
+	var wp *wordpress.Wordpress
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "bitpoke.io"},
+					{Domain: "www.bitpoke.io", TLSSecretRef: "www-tls"},
+				},
+				Redirects: []wordpressv1alpha1.RedirectSpec{
+					{Host: "bitpoke.io", Path: "/old", Target: "/new", StatusCode: 302},
+				},
+				CanonicalHost: "bitpoke.io",
+			},
+		})
+	})
+
+	sync := func(r wordpress.Redirect) *netv1.Ingress {
+		s := NewRedirectIngressSyncer(wp, r, nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		return s.Obj.(*netv1.Ingress)
+	}
+
+	It("should render the redirects as ingress-nginx annotations", func() {
+		redirects := wp.Redirects()
+		Expect(redirects).To(HaveLen(2))
+
+		obj := sync(redirects[0])
+		Expect(obj.Name).To(Equal(wp.RedirectName(redirects[0])))
+		Expect(obj.Labels).To(HaveKeyWithValue("app.kubernetes.io/component", "redirect"))
+		Expect(obj.Annotations).To(Equal(map[string]string{
+			redirectAnnotationKey:     "/new",
+			redirectCodeAnnotationKey: "302",
+		}))
+		Expect(obj.Spec.Rules).To(HaveLen(1))
+		Expect(obj.Spec.Rules[0].Host).To(Equal("bitpoke.io"))
+		Expect(obj.Spec.Rules[0].HTTP.Paths[0].Path).To(Equal("/old"))
+		Expect(obj.Spec.TLS).To(BeEmpty())
+
+		obj = sync(redirects[1])
+		Expect(obj.Annotations).To(Equal(map[string]string{
+			redirectAnnotationKey:     "http://bitpoke.io$request_uri",
+			redirectCodeAnnotationKey: "301",
+		}))
+		Expect(obj.Spec.Rules[0].Host).To(Equal("www.bitpoke.io"))
+		Expect(obj.Spec.Rules[0].HTTP.Paths[0].Path).To(Equal("/"))
+		Expect(obj.Spec.TLS).To(Equal([]netv1.IngressTLS{{SecretName: "www-tls", Hosts: []string{"www.bitpoke.io"}}}))
+	})
+
+	It("should leave the redirected domains out of the site Ingress", func() {
+		s := NewIngressSyncer(wp, "", nil).(*syncer.ObjectSyncer)
+		Expect(s.SyncFn()).To(Succeed())
+
+		obj := s.Obj.(*netv1.Ingress)
+		Expect(obj.Spec.Rules).To(HaveLen(1))
+		Expect(obj.Spec.Rules[0].Host).To(Equal("bitpoke.io"))
+	})
+})
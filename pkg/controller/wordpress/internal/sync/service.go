@@ -18,6 +18,8 @@ package sync
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,11 +29,17 @@ import (
 
 	"github.com/presslabs/controller-util/syncer"
 
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
 	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
 )
 
 var errImmutableServiceSelector = errors.New("service selector is immutable")
 
+// serviceAnnotationsAnnotation holds the keys of the annotations set on the
+// Service from the site's spec, so that the ones removed from the spec can be
+// removed from the Service, without touching the annotations set by others.
+const serviceAnnotationsAnnotation = "wordpress.presslabs.org/service-annotations"
+
 // NewServiceSyncer returns a new sync.Interface for reconciling web Service.
 func NewServiceSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface {
 	objLabels := wp.ComponentLabels(wordpress.WordpressDeployment)
@@ -55,18 +63,120 @@ func NewServiceSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Interface
 			}
 		}
 
-		if len(obj.Spec.Ports) != 2 {
-			obj.Spec.Ports = make([]corev1.ServicePort, 2)
+		spec := wp.Spec.Service
+		if spec == nil {
+			spec = &wordpressv1alpha1.ServiceSpec{}
+		}
+
+		obj.ObjectMeta.Annotations = serviceAnnotations(obj.ObjectMeta.Annotations, spec.Annotations)
+
+		obj.Spec.Type = spec.Type
+		if obj.Spec.Type == "" {
+			obj.Spec.Type = corev1.ServiceTypeClusterIP
+		}
+
+		if obj.Spec.Type == corev1.ServiceTypeClusterIP {
+			obj.Spec.ExternalTrafficPolicy = ""
+			obj.Spec.HealthCheckNodePort = 0
 		}
 
-		obj.Spec.Ports[0].Name = "http"
-		obj.Spec.Ports[0].Port = int32(80)
-		obj.Spec.Ports[0].TargetPort = intstr.FromInt(wordpress.InternalHTTPPort)
+		if obj.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			obj.Spec.AllocateLoadBalancerNodePorts = nil
+		}
 
-		obj.Spec.Ports[1].Name = "prometheus"
-		obj.Spec.Ports[1].Port = int32(wordpress.MetricsExporterPort)
-		obj.Spec.Ports[1].TargetPort = intstr.FromInt(wordpress.MetricsExporterPort)
+		obj.Spec.SessionAffinity = spec.SessionAffinity
+		if obj.Spec.SessionAffinity == "" {
+			obj.Spec.SessionAffinity = corev1.ServiceAffinityNone
+		}
+
+		// keep the session affinity config defaulted by the API server
+		if obj.Spec.SessionAffinity == corev1.ServiceAffinityNone {
+			obj.Spec.SessionAffinityConfig = nil
+		} else if spec.SessionAffinityConfig != nil {
+			obj.Spec.SessionAffinityConfig = spec.SessionAffinityConfig.DeepCopy()
+		}
+
+		ports := []corev1.ServicePort{
+			{
+				Name:       "http",
+				Port:       int32(80),
+				TargetPort: intstr.FromInt(wordpress.InternalHTTPPort),
+			},
+			{
+				Name:       "prometheus",
+				Port:       int32(wordpress.MetricsExporterPort),
+				TargetPort: intstr.FromInt(wordpress.MetricsExporterPort),
+			},
+		}
+		ports = append(ports, spec.ExtraPorts...)
+
+		obj.Spec.Ports = servicePorts(obj.Spec.Type, obj.Spec.Ports, ports)
 
 		return nil
 	})
 }
+
+// serviceAnnotations returns the annotations of a Service, with the ones from
+// the spec set and the ones previously set from the spec but no longer there
+// removed.
+func serviceAnnotations(current, desired map[string]string) map[string]string {
+	annotations := make(map[string]string, len(current)+len(desired))
+	for k, v := range current {
+		annotations[k] = v
+	}
+
+	if applied := annotations[serviceAnnotationsAnnotation]; applied != "" {
+		for _, k := range strings.Split(applied, ",") {
+			if _, ok := desired[k]; !ok {
+				delete(annotations, k)
+			}
+		}
+	}
+
+	delete(annotations, serviceAnnotationsAnnotation)
+
+	keys := make([]string, 0, len(desired))
+	for k, v := range desired {
+		annotations[k] = v
+		keys = append(keys, k)
+	}
+
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		annotations[serviceAnnotationsAnnotation] = strings.Join(keys, ",")
+	}
+
+	return annotations
+}
+
+// servicePorts returns the desired ports of a Service, filled in with the
+// values defaulted by the API server for the existing ports, to avoid
+// needless updates.
+func servicePorts(serviceType corev1.ServiceType, existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	nodePorts := map[string]int32{}
+	for _, port := range existing {
+		nodePorts[port.Name] = port.NodePort
+	}
+
+	ports := make([]corev1.ServicePort, len(desired))
+	for i, port := range desired {
+		ports[i] = *port.DeepCopy()
+
+		if ports[i].Protocol == "" {
+			ports[i].Protocol = corev1.ProtocolTCP
+		}
+
+		if ports[i].TargetPort.IntVal == 0 && ports[i].TargetPort.StrVal == "" {
+			ports[i].TargetPort = intstr.FromInt(int(ports[i].Port))
+		}
+
+		switch {
+		case serviceType == corev1.ServiceTypeClusterIP:
+			ports[i].NodePort = 0
+		case ports[i].NodePort == 0:
+			ports[i].NodePort = nodePorts[ports[i].Name]
+		}
+	}
+
+	return ports
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("The Service syncer", func() {
+	var (
+		wp  *wordpress.Wordpress
+		obj *corev1.Service
+		s   *syncer.ObjectSyncer
+	)
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		})
+		s = NewServiceSyncer(wp, nil).(*syncer.ObjectSyncer)
+		obj = s.Obj.(*corev1.Service)
+	})
+
+	sync := func() {
+		Expect(s.SyncFn()).To(Succeed())
+	}
+
+	It("should create a ClusterIP Service by default", func() {
+		sync()
+
+		Expect(obj.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(obj.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityNone))
+		Expect(obj.Spec.Ports).To(Equal([]corev1.ServicePort{
+			{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(wordpress.InternalHTTPPort)},
+			{Name: "prometheus", Protocol: corev1.ProtocolTCP, Port: 9145, TargetPort: intstr.FromInt(wordpress.MetricsExporterPort)},
+		}))
+	})
+
+	It("should configure the Service and keep the allocated node ports", func() {
+		wp.Spec.Service = &wordpressv1alpha1.ServiceSpec{
+			Type:            corev1.ServiceTypeLoadBalancer,
+			Annotations:     map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			SessionAffinity: corev1.ServiceAffinityClientIP,
+			ExtraPorts: []corev1.ServicePort{
+				{Name: "debug", Port: 2345},
+			},
+		}
+
+		sync()
+
+		Expect(obj.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(obj.Annotations).To(HaveKeyWithValue("service.beta.kubernetes.io/aws-load-balancer-type", "nlb"))
+		Expect(obj.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+		Expect(obj.Spec.Ports).To(HaveLen(3))
+		Expect(obj.Spec.Ports[2]).To(Equal(corev1.ServicePort{
+			Name: "debug", Protocol: corev1.ProtocolTCP, Port: 2345, TargetPort: intstr.FromInt(2345),
+		}))
+
+		// simulate the node ports allocated by the API server
+		for i := range obj.Spec.Ports {
+			obj.Spec.Ports[i].NodePort = int32(30000 + i)
+		}
+
+		sync()
+		Expect(obj.Spec.Ports[0].NodePort).To(Equal(int32(30000)))
+		Expect(obj.Spec.Ports[2].NodePort).To(Equal(int32(30002)))
+
+		wp.Spec.Service.Type = corev1.ServiceTypeClusterIP
+
+		sync()
+		Expect(obj.Spec.Ports[0].NodePort).To(BeZero())
+		Expect(obj.Spec.Ports[2].NodePort).To(BeZero())
+	})
+
+	It("should remove the annotations dropped from the spec", func() {
+		obj.Annotations = map[string]string{"foreign": "value"}
+		wp.Spec.Service = &wordpressv1alpha1.ServiceSpec{
+			Annotations: map[string]string{"a": "1", "b": "2"},
+		}
+
+		sync()
+		Expect(obj.Annotations).To(Equal(map[string]string{
+			"foreign": "value",
+			"a":       "1",
+			"b":       "2",
+			"wordpress.presslabs.org/service-annotations": "a,b",
+		}))
+
+		delete(wp.Spec.Service.Annotations, "a")
+
+		sync()
+		Expect(obj.Annotations).To(Equal(map[string]string{
+			"foreign": "value",
+			"b":       "2",
+			"wordpress.presslabs.org/service-annotations": "b",
+		}))
+
+		wp.Spec.Service = nil
+
+		sync()
+		Expect(obj.Annotations).To(Equal(map[string]string{"foreign": "value"}))
+	})
+})
@@ -43,14 +43,15 @@ func NewDBUpgradeJobSyncer(wp *wordpress.Wordpress, c client.Client) syncer.Inte
 
 	var (
 		backoffLimit          int32
-		activeDeadlineSeconds int64 = 10
+		activeDeadlineSeconds int64 = 1800
 	)
 
 	return syncer.NewObjectSyncer("DBUpgradeJob", wp.Unwrap(), obj, c, func() error {
 		obj.Labels = labels.Merge(labels.Merge(obj.Labels, objLabels), controllerLabels)
 
+		// the job spec is immutable, a failed upgrade gets retried by the
+		// controller, which deletes the job after a backoff
 		if !obj.CreationTimestamp.IsZero() {
-			// TODO (calind): handle the case that the existing job is failed
 			return nil
 		}
 
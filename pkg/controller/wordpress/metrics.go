@@ -0,0 +1,207 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+
+	"github.com/presslabs/controller-util/syncer"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	metricsNamespace = "wordpress_operator"
+
+	// syncFailed is the result reported for syncers which returned an error.
+	syncFailed = "failed"
+
+	// noVolumeSource is the source reported for sites without a code or
+	// media volume.
+	noVolumeSource = "none"
+)
+
+var (
+	syncerResultsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "syncer_results_total",
+		Help:      "Total number of syncs of the sites' resources, by syncer and result (created, updated, unchanged or " + syncFailed + ").",
+	}, []string{"syncer", "result"})
+
+	sitesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "sites"),
+		"Number of sites, by namespace.",
+		[]string{"namespace"}, nil,
+	)
+
+	sitesByImageDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "sites_by_image"),
+		"Number of sites, by runtime image.",
+		[]string{"image"}, nil,
+	)
+
+	sitesByConditionDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "sites_by_condition"),
+		"Number of sites, by condition type and status.",
+		[]string{"type", "status"}, nil,
+	)
+
+	sitesByVolumeSourceDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "sites_by_volume_source"),
+		"Number of sites, by volume (code or media) and volume source type.",
+		[]string{"volume", "source"}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(syncerResultsTotal)
+}
+
+// instrumentedSyncer counts the results of the wrapped syncer.
+type instrumentedSyncer struct {
+	syncer.Interface
+}
+
+func (s *instrumentedSyncer) Sync(ctx context.Context) (syncer.SyncResult, error) {
+	result, err := s.Interface.Sync(ctx)
+
+	name := "unknown"
+	if objSyncer, ok := s.Interface.(*syncer.ObjectSyncer); ok {
+		name = objSyncer.Name
+	}
+
+	outcome := string(result.Operation)
+	if err != nil {
+		outcome = syncFailed
+	} else if result.Operation == "" {
+		outcome = string(controllerutil.OperationResultNone)
+	}
+
+	syncerResultsTotal.WithLabelValues(name, outcome).Inc()
+
+	return result, err
+}
+
+// fleetCollector reports the number of sites grouped by namespace, runtime
+// image, condition and volume sources. The sites are listed on every scrape,
+// from the manager's cache.
+type fleetCollector struct {
+	reader client.Reader
+}
+
+func newFleetCollector(reader client.Reader) *fleetCollector {
+	return &fleetCollector{reader: reader}
+}
+
+// Describe implements prometheus.Collector.
+func (c *fleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sitesDesc
+	ch <- sitesByImageDesc
+	ch <- sitesByConditionDesc
+	ch <- sitesByVolumeSourceDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *fleetCollector) Collect(ch chan<- prometheus.Metric) {
+	sites := &wordpressv1alpha1.WordpressList{}
+	if err := c.reader.List(context.TODO(), sites); err != nil {
+		log.Error(err, "failed to list sites for metrics")
+
+		return
+	}
+
+	byNamespace := map[string]int{}
+	byImage := map[string]int{}
+	byCondition := map[[2]string]int{}
+	byVolumeSource := map[[2]string]int{}
+
+	for i := range sites.Items {
+		wp := wordpress.New(&sites.Items[i])
+		wp.SetDefaults()
+
+		byNamespace[wp.Namespace]++
+		byImage[wp.Spec.Image]++
+
+		for _, cond := range wp.Status.Conditions {
+			byCondition[[2]string{string(cond.Type), string(cond.Status)}]++
+		}
+
+		byVolumeSource[[2]string{"code", codeVolumeSource(wp.Spec.CodeVolumeSpec)}]++
+		byVolumeSource[[2]string{"media", mediaVolumeSource(wp.Spec.MediaVolumeSpec)}]++
+	}
+
+	for ns, n := range byNamespace {
+		ch <- prometheus.MustNewConstMetric(sitesDesc, prometheus.GaugeValue, float64(n), ns)
+	}
+
+	for image, n := range byImage {
+		ch <- prometheus.MustNewConstMetric(sitesByImageDesc, prometheus.GaugeValue, float64(n), image)
+	}
+
+	for labels, n := range byCondition {
+		ch <- prometheus.MustNewConstMetric(sitesByConditionDesc, prometheus.GaugeValue, float64(n), labels[0], labels[1])
+	}
+
+	for labels, n := range byVolumeSource {
+		ch <- prometheus.MustNewConstMetric(sitesByVolumeSourceDesc, prometheus.GaugeValue, float64(n), labels[0], labels[1])
+	}
+}
+
+// codeVolumeSource returns the type of the code volume source, following the
+// same precedence as the web pods.
+func codeVolumeSource(spec *wordpressv1alpha1.CodeVolumeSpec) string {
+	switch {
+	case spec == nil:
+		return noVolumeSource
+	case spec.GitDir != nil:
+		return "git"
+	case spec.PersistentVolumeClaim != nil:
+		return "persistentVolumeClaim"
+	case spec.HostPath != nil:
+		return "hostPath"
+	case spec.EmptyDir != nil:
+		return "emptyDir"
+	}
+
+	return noVolumeSource
+}
+
+// mediaVolumeSource returns the type of the media volume source, following the
+// same precedence as the web pods.
+func mediaVolumeSource(spec *wordpressv1alpha1.MediaVolumeSpec) string {
+	switch {
+	case spec == nil:
+		return noVolumeSource
+	case spec.S3VolumeSource != nil:
+		return "s3"
+	case spec.GCSVolumeSource != nil:
+		return "gcs"
+	case spec.PersistentVolumeClaim != nil:
+		return "persistentVolumeClaim"
+	case spec.HostPath != nil:
+		return "hostPath"
+	case spec.EmptyDir != nil:
+		return "emptyDir"
+	}
+
+	return noVolumeSource
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/presslabs/controller-util/syncer"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+)
+
+var _ = Describe("Fleet metrics", func() {
+	var (
+		c client.Client
+	)
+
+	BeforeEach(func() {
+		c = testutil.NewFakeClient(
+			&wordpressv1alpha1.Wordpress{
+				ObjectMeta: metav1.ObjectMeta{Name: "git", Namespace: "default"},
+				Spec: wordpressv1alpha1.WordpressSpec{
+					Image:           "docker.io/bitpoke/wordpress-runtime:6.0",
+					CodeVolumeSpec:  &wordpressv1alpha1.CodeVolumeSpec{GitDir: &wordpressv1alpha1.GitVolumeSource{}},
+					MediaVolumeSpec: &wordpressv1alpha1.MediaVolumeSpec{S3VolumeSource: &wordpressv1alpha1.S3VolumeSource{}},
+				},
+				Status: wordpressv1alpha1.WordpressStatus{
+					Conditions: []wordpressv1alpha1.WordpressCondition{
+						{Type: wordpressv1alpha1.ReadyCondition, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			&wordpressv1alpha1.Wordpress{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: "default"},
+				Spec: wordpressv1alpha1.WordpressSpec{
+					Image: "docker.io/bitpoke/wordpress-runtime:6.0",
+					CodeVolumeSpec: &wordpressv1alpha1.CodeVolumeSpec{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{},
+					},
+				},
+				Status: wordpressv1alpha1.WordpressStatus{
+					Conditions: []wordpressv1alpha1.WordpressCondition{
+						{Type: wordpressv1alpha1.ReadyCondition, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+			&wordpressv1alpha1.Wordpress{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"},
+				Spec: wordpressv1alpha1.WordpressSpec{
+					Image: "docker.io/bitpoke/wordpress-runtime:5.9",
+				},
+			},
+		)
+	})
+
+	It("counts the sites by namespace, image, condition and volume source", func() {
+		expected := `
+# HELP wordpress_operator_sites Number of sites, by namespace.
+# TYPE wordpress_operator_sites gauge
+wordpress_operator_sites{namespace="default"} 2
+wordpress_operator_sites{namespace="other"} 1
+# HELP wordpress_operator_sites_by_condition Number of sites, by condition type and status.
+# TYPE wordpress_operator_sites_by_condition gauge
+wordpress_operator_sites_by_condition{status="False",type="Ready"} 1
+wordpress_operator_sites_by_condition{status="True",type="Ready"} 1
+# HELP wordpress_operator_sites_by_image Number of sites, by runtime image.
+# TYPE wordpress_operator_sites_by_image gauge
+wordpress_operator_sites_by_image{image="docker.io/bitpoke/wordpress-runtime:5.9"} 1
+wordpress_operator_sites_by_image{image="docker.io/bitpoke/wordpress-runtime:6.0"} 2
+# HELP wordpress_operator_sites_by_volume_source Number of sites, by volume (code or media) and volume source type.
+# TYPE wordpress_operator_sites_by_volume_source gauge
+wordpress_operator_sites_by_volume_source{source="git",volume="code"} 1
+wordpress_operator_sites_by_volume_source{source="none",volume="code"} 1
+wordpress_operator_sites_by_volume_source{source="none",volume="media"} 2
+wordpress_operator_sites_by_volume_source{source="persistentVolumeClaim",volume="code"} 1
+wordpress_operator_sites_by_volume_source{source="s3",volume="media"} 1
+`
+		Expect(promtestutil.CollectAndCompare(newFleetCollector(c), strings.NewReader(expected))).To(Succeed())
+	})
+
+	It("counts the syncer results", func() {
+		syncerResultsTotal.Reset()
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+		s := syncer.NewObjectSyncer("TestConfigMap", nil, cm, c, func() error {
+			cm.Data = map[string]string{"key": "value"}
+
+			return nil
+		})
+
+		_, err := (&instrumentedSyncer{s}).Sync(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = (&instrumentedSyncer{s}).Sync(context.TODO())
+		Expect(err).NotTo(HaveOccurred())
+
+		s.(*syncer.ObjectSyncer).SyncFn = func() error { return errors.New("sync failed") }
+		_, err = (&instrumentedSyncer{s}).Sync(context.TODO())
+		Expect(err).To(HaveOccurred())
+
+		Expect(promtestutil.ToFloat64(syncerResultsTotal.WithLabelValues("TestConfigMap", "created"))).To(Equal(1.0))
+		Expect(promtestutil.ToFloat64(syncerResultsTotal.WithLabelValues("TestConfigMap", "unchanged"))).To(Equal(1.0))
+		Expect(promtestutil.ToFloat64(syncerResultsTotal.WithLabelValues("TestConfigMap", syncFailed))).To(Equal(1.0))
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+// ignoreCronStatusUpdates filters out the updates of sites which change only
+// status.cron. The wp-cron controller records every wp-cron.php request in
+// it, which would otherwise reconcile all sites at every wp-cron interval.
+var ignoreCronStatusUpdates = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldWP, ok := e.ObjectOld.(*wordpressv1alpha1.Wordpress)
+		if !ok {
+			return true
+		}
+
+		newWP, ok := e.ObjectNew.(*wordpressv1alpha1.Wordpress)
+		if !ok {
+			return true
+		}
+
+		return !onlyCronStatusChanged(oldWP, newWP)
+	},
+}
+
+func onlyCronStatusChanged(oldWP, newWP *wordpressv1alpha1.Wordpress) bool {
+	if equality.Semantic.DeepEqual(oldWP.Status.Cron, newWP.Status.Cron) {
+		return false
+	}
+
+	oldWP = oldWP.DeepCopy()
+	newWP = newWP.DeepCopy()
+
+	for _, wp := range []*wordpressv1alpha1.Wordpress{oldWP, newWP} {
+		wp.Status.Cron = nil
+		wp.ResourceVersion = ""
+		wp.ManagedFields = nil
+	}
+
+	return equality.Semantic.DeepEqual(oldWP, newWP)
+}
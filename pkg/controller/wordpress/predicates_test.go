@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+var _ = Describe("Wordpress watch predicates", func() {
+	var oldWP, newWP *wordpressv1alpha1.Wordpress
+
+	BeforeEach(func() {
+		oldWP = &wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", ResourceVersion: "1"},
+			Spec:       wordpressv1alpha1.WordpressSpec{Image: "bitpoke/wordpress-runtime"},
+		}
+		newWP = oldWP.DeepCopy()
+		newWP.ResourceVersion = "2"
+	})
+
+	update := func() bool {
+		return ignoreCronStatusUpdates.Update(event.UpdateEvent{ObjectOld: oldWP, ObjectNew: newWP})
+	}
+
+	It("ignores updates of status.cron alone", func() {
+		now := metav1.NewTime(time.Now())
+		newWP.Status.Cron = &wordpressv1alpha1.CronStatus{LastAttemptTime: &now}
+
+		Expect(update()).To(BeFalse())
+	})
+
+	It("passes other updates", func() {
+		Expect(update()).To(BeTrue())
+
+		now := metav1.NewTime(time.Now())
+		newWP.Status.Cron = &wordpressv1alpha1.CronStatus{LastAttemptTime: &now}
+		newWP.Spec.Image = "bitpoke/wordpress-runtime:latest"
+
+		Expect(update()).To(BeTrue())
+	})
+})
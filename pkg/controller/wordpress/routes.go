@@ -0,0 +1,196 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// routeKeyField indexes Wordpress objects by the keys of the routes they claim.
+const routeKeyField = "spec.routes.key"
+
+var log = logf.Log.WithName(controllerName)
+
+func indexRouteKeys(obj client.Object) []string {
+	return wordpress.New(obj.(*wordpressv1alpha1.Wordpress)).RouteKeys()
+}
+
+// checkRouteConflicts records the routes of the site claimed first by other
+// sites into the status and sets the RouteConflict condition.
+func (r *ReconcileWordpress) checkRouteConflicts(ctx context.Context, wp *wordpress.Wordpress) error {
+	var (
+		conflicts []wordpressv1alpha1.RouteSpec
+		claims    []string
+	)
+
+	for _, route := range wp.Spec.Routes {
+		key := wordpress.RouteKey(route)
+
+		sites := &wordpressv1alpha1.WordpressList{}
+		if err := r.List(ctx, sites, client.MatchingFields{routeKeyField: key}); err != nil {
+			return err
+		}
+
+		for i := range sites.Items {
+			other := wordpress.New(&sites.Items[i])
+			if other.ClaimsRoutesBefore(wp) {
+				conflicts = append(conflicts, route)
+				claims = append(claims, fmt.Sprintf("%s (claimed by %s/%s)", key, other.Namespace, other.Name))
+
+				break
+			}
+		}
+	}
+
+	wp.Status.ConflictingRoutes = conflicts
+
+	if len(conflicts) > 0 {
+		msg := fmt.Sprintf("routes claimed by other sites: %s", strings.Join(claims, ", "))
+		if wp.SetCondition(wordpressv1alpha1.RouteConflictCondition, corev1.ConditionTrue, wordpressv1alpha1.RouteClaimedReason, msg) {
+			r.recorder.Event(wp.Unwrap(), corev1.EventTypeWarning, string(wordpressv1alpha1.RouteConflictCondition), msg)
+		}
+	} else if wp.GetCondition(wordpressv1alpha1.RouteConflictCondition) != nil {
+		wp.SetCondition(wordpressv1alpha1.RouteConflictCondition, corev1.ConditionFalse,
+			wordpressv1alpha1.NoRouteConflictReason, "all routes are claimed by this site")
+	}
+
+	return nil
+}
+
+// cleanupIngresses removes the site's web and redirect ingresses which are no
+// longer needed. In gateway mode, all of them are removed, as the routes and
+// the redirects are rendered into the HTTPRoutes instead.
+func (r *ReconcileWordpress) cleanupIngresses(ctx context.Context, wp *wordpress.Wordpress) error {
+	keep := map[string]bool{}
+
+	if wp.RoutingMode() != wordpressv1alpha1.RoutingModeGateway {
+		for _, group := range wp.IngressGroups() {
+			keep[wp.IngressName(group)] = true
+		}
+
+		for _, redirect := range wp.Redirects() {
+			keep[wp.RedirectName(redirect)] = true
+		}
+	}
+
+	for _, objLabels := range []map[string]string{
+		wp.ComponentLabels(wordpress.WordpressIngress),
+		wp.ComponentLabels(wordpress.WordpressRedirect),
+	} {
+		list := &netv1.IngressList{}
+		if err := r.List(ctx, list, client.InNamespace(wp.Namespace), client.MatchingLabels(objLabels)); err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			ingress := &list.Items[i]
+			if keep[ingress.Name] || !isOwnedBy(ingress.OwnerReferences, wp) {
+				continue
+			}
+
+			if err := r.Delete(ctx, ingress); ignoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// cleanupHTTPRoutes removes the HTTPRoutes of the site for domains which are
+// no longer routed through a Gateway.
+func (r *ReconcileWordpress) cleanupHTTPRoutes(ctx context.Context, wp *wordpress.Wordpress) error {
+	if !r.gatewayAPI {
+		return nil
+	}
+
+	keep := map[string]bool{}
+
+	if wp.RoutingMode() == wordpressv1alpha1.RoutingModeGateway {
+		for _, domain := range wp.ActiveDomains() {
+			keep[wp.HTTPRouteName(domain)] = true
+		}
+	}
+
+	return r.cleanupUnstructured(ctx, wp, sync.HTTPRouteGVK, wp.ComponentLabels(wordpress.WordpressHTTPRoute), keep)
+}
+
+// enqueueRouteClaimants enqueues the sites that claim the same routes as the
+// changed site, as the changed site may have released some of them, or may
+// win them when created within the same second as their current owner.
+type enqueueRouteClaimants struct {
+	client client.Client
+}
+
+func (e *enqueueRouteClaimants) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q, evt.Object)
+}
+
+func (e *enqueueRouteClaimants) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q, evt.ObjectOld, evt.ObjectNew)
+}
+
+func (e *enqueueRouteClaimants) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q, evt.Object)
+}
+
+func (e *enqueueRouteClaimants) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(q, evt.Object)
+}
+
+func (e *enqueueRouteClaimants) enqueue(q workqueue.RateLimitingInterface, objs ...client.Object) {
+	keys := map[string]bool{}
+
+	for _, obj := range objs {
+		for _, key := range indexRouteKeys(obj) {
+			keys[key] = true
+		}
+	}
+
+	for key := range keys {
+		sites := &wordpressv1alpha1.WordpressList{}
+		if err := e.client.List(context.TODO(), sites, client.MatchingFields{routeKeyField: key}); err != nil {
+			log.Error(err, "failed to list sites claiming route", "route", key)
+
+			continue
+		}
+
+		for i := range sites.Items {
+			site := &sites.Items[i]
+			if site.Namespace == objs[0].GetNamespace() && site.Name == objs[0].GetName() {
+				continue
+			}
+
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: site.Name, Namespace: site.Namespace}})
+		}
+	}
+}
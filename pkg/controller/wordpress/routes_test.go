@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+)
+
+// routeIndexClient serves the route key field selectors, which the fake
+// client doesn't index.
+type routeIndexClient struct {
+	client.Client
+}
+
+func (c *routeIndexClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	if err := c.Client.List(ctx, list); err != nil {
+		return err
+	}
+
+	key, _ := listOpts.FieldSelector.RequiresExactMatch(routeKeyField)
+	sites := list.(*wordpressv1alpha1.WordpressList)
+	items := sites.Items[:0]
+
+	for i := range sites.Items {
+		for _, k := range indexRouteKeys(&sites.Items[i]) {
+			if k == key {
+				items = append(items, sites.Items[i])
+
+				break
+			}
+		}
+	}
+
+	sites.Items = items
+
+	return nil
+}
+
+var _ = Describe("Route claimants handler", func() {
+	var (
+		h  *enqueueRouteClaimants
+		q  workqueue.RateLimitingInterface
+		wp *wordpressv1alpha1.Wordpress
+	)
+
+	site := func(name string, domains ...string) *wordpressv1alpha1.Wordpress {
+		obj := &wordpressv1alpha1.Wordpress{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		for _, domain := range domains {
+			obj.Spec.Routes = append(obj.Spec.Routes, wordpressv1alpha1.RouteSpec{Domain: domain})
+		}
+
+		return obj
+	}
+
+	queued := func() []reconcile.Request {
+		reqs := []reconcile.Request{}
+		for q.Len() > 0 {
+			item, _ := q.Get()
+			reqs = append(reqs, item.(reconcile.Request))
+			q.Done(item)
+		}
+
+		return reqs
+	}
+
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}
+	}
+
+	BeforeEach(func() {
+		wp = site("new", "example.com")
+		c := testutil.NewFakeClient(wp, site("owner", "example.com", "other.com"), site("unrelated", "unrelated.com"))
+
+		h = &enqueueRouteClaimants{client: &routeIndexClient{c}}
+		q = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	})
+
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	It("enqueues the other claimants of a created site", func() {
+		h.Create(event.CreateEvent{Object: wp}, q)
+		Expect(queued()).To(ConsistOf(request("owner")))
+	})
+
+	It("enqueues the claimants of the routes released by an updated site", func() {
+		updated := wp.DeepCopy()
+		updated.Spec.Routes = []wordpressv1alpha1.RouteSpec{{Domain: "unrelated.com"}}
+
+		h.Update(event.UpdateEvent{ObjectOld: wp, ObjectNew: updated}, q)
+		Expect(queued()).To(ConsistOf(request("owner"), request("unrelated")))
+	})
+
+	It("enqueues the other claimants of a deleted site", func() {
+		h.Delete(event.DeleteEvent{Object: wp}, q)
+		Expect(queued()).To(ConsistOf(request("owner")))
+	})
+})
@@ -0,0 +1,273 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// unhealthyRequeueInterval is the interval at which sites which are not
+// ready get re-checked, as pods are not watched.
+const unhealthyRequeueInterval = 30 * time.Second
+
+// deploymentTimedOutReason is the reason the deployment controller sets on
+// the Progressing condition once the progress deadline is exceeded.
+const deploymentTimedOutReason = "ProgressDeadlineExceeded"
+
+// initContainerFailureReasons maps the init containers which prepare the
+// site's code to the reason used when they fail.
+var initContainerFailureReasons = map[string]string{
+	"git":        wordpressv1alpha1.GitCloneFailedReason,
+	"install-wp": wordpressv1alpha1.InstallFailedReason,
+}
+
+// siteHealth holds the first reason for which the site is progressing and
+// the first reason for which it is degraded. The routing objects waiting to
+// be admitted are reported as progressing, without making the site not
+// ready, as not all ingress controllers and gateways report their status.
+type siteHealth struct {
+	progressingReason, progressingMessage string
+	degradedReason, degradedMessage       string
+	routingReason, routingMessage         string
+}
+
+func (h *siteHealth) progressing(reason, message string) {
+	if h.progressingReason == "" {
+		h.progressingReason, h.progressingMessage = reason, message
+	}
+}
+
+func (h *siteHealth) degraded(reason, message string) {
+	if h.degradedReason == "" {
+		h.degradedReason, h.degradedMessage = reason, message
+	}
+}
+
+func (h *siteHealth) routing(reason, message string) {
+	if h.routingReason == "" {
+		h.routingReason, h.routingMessage = reason, message
+	}
+}
+
+func (h *siteHealth) healthy() bool {
+	return h.progressingReason == "" && h.degradedReason == ""
+}
+
+// updateHealth sets the Ready, Progressing and Degraded conditions from the
+// state of the site's components and returns true if the site is ready.
+func (r *ReconcileWordpress) updateHealth(ctx context.Context, wp *wordpress.Wordpress, upgrading bool, objs []client.Object) (bool, error) {
+	h := &siteHealth{}
+
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			checkDeployment(h, o)
+		case *corev1.PersistentVolumeClaim:
+			checkPVC(h, o)
+		case *netv1.Ingress:
+			checkIngress(h, o)
+		case *unstructured.Unstructured:
+			if o.GroupVersionKind() == sync.HTTPRouteGVK {
+				checkHTTPRoute(h, o)
+			}
+		}
+	}
+
+	if upgrading {
+		if cond := wp.GetCondition(wordpressv1alpha1.DBUpgradedCondition); cond != nil && cond.Reason == wordpressv1alpha1.DBUpgradeFailedReason {
+			h.degraded(cond.Reason, cond.Message)
+		} else {
+			h.progressing(wordpressv1alpha1.DBUpgradeInProgressReason, "waiting for the database upgrade to complete")
+		}
+	}
+
+	if wp.IsBeingRestored() {
+		h.progressing(wordpressv1alpha1.RestoringReason,
+			fmt.Sprintf("the web pods are stopped while %s restores the site", wp.Annotations[wordpress.RestoreAnnotation]))
+	}
+
+	r.checkGateway(h, wp)
+
+	if len(wp.Status.ConflictingRoutes) > 0 {
+		h.degraded(wordpressv1alpha1.RouteClaimedReason, wp.GetCondition(wordpressv1alpha1.RouteConflictCondition).Message)
+	}
+
+	// pods are inspected only for sites which are not healthy, to find out why
+	if !h.healthy() {
+		pods := &corev1.PodList{}
+		if err := r.apiReader.List(ctx, pods, client.InNamespace(wp.Namespace), client.MatchingLabels(wp.WebPodLabels())); err != nil {
+			return false, err
+		}
+
+		checkPods(h, pods.Items)
+	}
+
+	setHealthConditions(wp, h)
+
+	return h.healthy(), nil
+}
+
+func setHealthConditions(wp *wordpress.Wordpress, h *siteHealth) {
+	if h.progressingReason != "" {
+		wp.SetCondition(wordpressv1alpha1.ProgressingCondition, corev1.ConditionTrue, h.progressingReason, h.progressingMessage)
+	} else if h.routingReason != "" {
+		wp.SetCondition(wordpressv1alpha1.ProgressingCondition, corev1.ConditionTrue, h.routingReason, h.routingMessage)
+	} else {
+		wp.SetCondition(wordpressv1alpha1.ProgressingCondition, corev1.ConditionFalse,
+			wordpressv1alpha1.RolloutCompleteReason, "all components are up to date")
+	}
+
+	if h.degradedReason != "" {
+		wp.SetCondition(wordpressv1alpha1.DegradedCondition, corev1.ConditionTrue, h.degradedReason, h.degradedMessage)
+	} else {
+		wp.SetCondition(wordpressv1alpha1.DegradedCondition, corev1.ConditionFalse, wordpressv1alpha1.AsExpectedReason, "")
+	}
+
+	switch {
+	case h.degradedReason != "":
+		wp.SetCondition(wordpressv1alpha1.ReadyCondition, corev1.ConditionFalse, h.degradedReason, h.degradedMessage)
+	case h.progressingReason != "":
+		wp.SetCondition(wordpressv1alpha1.ReadyCondition, corev1.ConditionFalse, h.progressingReason, h.progressingMessage)
+	default:
+		wp.SetCondition(wordpressv1alpha1.ReadyCondition, corev1.ConditionTrue, wordpressv1alpha1.ReadyReason, "the site is ready")
+	}
+}
+
+// checkDeployment checks the rollout status of the web deployment, the same
+// way `kubectl rollout status` does.
+func checkDeployment(h *siteHealth, d *appsv1.Deployment) {
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == deploymentTimedOutReason {
+			h.degraded(wordpressv1alpha1.ProgressDeadlineExceededReason, cond.Message)
+		}
+
+		if cond.Type == appsv1.DeploymentReplicaFailure && cond.Status == corev1.ConditionTrue {
+			h.degraded(wordpressv1alpha1.ReplicaFailureReason, cond.Message)
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	switch {
+	case d.Generation > d.Status.ObservedGeneration:
+		h.progressing(wordpressv1alpha1.DeploymentProgressingReason, "waiting for the deployment spec update to be observed")
+	case d.Status.UpdatedReplicas < replicas:
+		h.progressing(wordpressv1alpha1.DeploymentProgressingReason,
+			fmt.Sprintf("%d out of %d new replicas have been updated", d.Status.UpdatedReplicas, replicas))
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		h.progressing(wordpressv1alpha1.DeploymentProgressingReason,
+			fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas))
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		h.progressing(wordpressv1alpha1.DeploymentProgressingReason,
+			fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas))
+	}
+}
+
+func checkPVC(h *siteHealth, pvc *corev1.PersistentVolumeClaim) {
+	if pvc.Status.Phase == corev1.ClaimLost {
+		h.degraded(wordpressv1alpha1.PVCLostReason, fmt.Sprintf("persistent volume claim %s lost its volume", pvc.Name))
+	} else if pvc.Status.Phase != corev1.ClaimBound {
+		h.progressing(wordpressv1alpha1.PVCPendingReason, fmt.Sprintf("persistent volume claim %s is not bound", pvc.Name))
+	}
+}
+
+// checkGateway reports the sites routed through a Gateway for which no
+// HTTPRoutes can be created.
+func (r *ReconcileWordpress) checkGateway(h *siteHealth, wp *wordpress.Wordpress) {
+	if len(wp.ActiveRoutes()) == 0 || wp.RoutingMode() != wordpressv1alpha1.RoutingModeGateway {
+		return
+	}
+
+	if !r.gatewayAPI {
+		h.degraded(wordpressv1alpha1.GatewayAPINotInstalledReason, "the site is routed through a gateway, but the Gateway API is not installed")
+	} else if wp.Gateway() == nil {
+		h.degraded(wordpressv1alpha1.GatewayNotSetReason, "the site is routed through a gateway, but none is set in spec.routing.gateway or --gateway")
+	}
+}
+
+func checkIngress(h *siteHealth, ingress *netv1.Ingress) {
+	if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+		h.routing(wordpressv1alpha1.IngressPendingReason, fmt.Sprintf("ingress %s has no load balancer address", ingress.Name))
+	}
+}
+
+// checkHTTPRoute checks that the HTTPRoute is accepted by its Gateway.
+func checkHTTPRoute(h *siteHealth, route *unstructured.Unstructured) {
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		conditions, _, _ := unstructured.NestedSlice(parent, "conditions")
+		for _, c := range conditions {
+			cond, ok := c.(map[string]interface{})
+			if ok && cond["type"] == "Accepted" && cond["status"] == string(corev1.ConditionTrue) {
+				return
+			}
+		}
+	}
+
+	h.routing(wordpressv1alpha1.HTTPRoutePendingReason, fmt.Sprintf("httproute %s is not accepted by its gateway", route.GetName()))
+}
+
+// checkPods looks for failed init containers, which leave the web pods
+// stuck in the init phase.
+func checkPods(h *siteHealth, pods []corev1.Pod) {
+	for i := range pods {
+		for _, cs := range pods[i].Status.InitContainerStatuses {
+			reason, ok := initContainerFailureReasons[cs.Name]
+			if !ok {
+				continue
+			}
+
+			state := cs.State.Terminated
+			if state == nil || state.ExitCode == 0 {
+				state = cs.LastTerminationState.Terminated
+			}
+
+			if state == nil || state.ExitCode == 0 {
+				continue
+			}
+
+			msg := fmt.Sprintf("init container %s of pod %s exited with code %d", cs.Name, pods[i].Name, state.ExitCode)
+			if state.Message != "" {
+				msg = fmt.Sprintf("%s: %s", msg, state.Message)
+			}
+
+			h.degraded(reason, msg)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("Wordpress health", func() {
+	var (
+		h      *siteHealth
+		deploy *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		h = &siteHealth{}
+
+		replicas := int32(2)
+		deploy = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				Replicas:           2,
+				UpdatedReplicas:    2,
+				AvailableReplicas:  2,
+			},
+		}
+	})
+
+	It("considers a rolled out deployment healthy", func() {
+		checkDeployment(h, deploy)
+		Expect(h.healthy()).To(BeTrue())
+	})
+
+	It("reports deployments which are rolling out as progressing", func() {
+		deploy.Status.AvailableReplicas = 1
+		checkDeployment(h, deploy)
+
+		Expect(h.progressingReason).To(Equal(wordpressv1alpha1.DeploymentProgressingReason))
+		Expect(h.progressingMessage).To(Equal("1 of 2 updated replicas are available"))
+		Expect(h.degradedReason).To(BeEmpty())
+	})
+
+	It("reports deployments which exceeded their progress deadline as degraded", func() {
+		deploy.Status.UpdatedReplicas = 1
+		deploy.Status.Conditions = []appsv1.DeploymentCondition{
+			{
+				Type:    appsv1.DeploymentProgressing,
+				Status:  corev1.ConditionFalse,
+				Reason:  "ProgressDeadlineExceeded",
+				Message: "deadline exceeded",
+			},
+		}
+		checkDeployment(h, deploy)
+
+		Expect(h.progressingReason).To(Equal(wordpressv1alpha1.DeploymentProgressingReason))
+		Expect(h.degradedReason).To(Equal(wordpressv1alpha1.ProgressDeadlineExceededReason))
+		Expect(h.degradedMessage).To(Equal("deadline exceeded"))
+	})
+
+	It("reports ingresses without a load balancer address as progressing, while ready", func() {
+		r := &ReconcileWordpress{}
+		wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+		ingress := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+
+		ready, err := r.updateHealth(context.TODO(), wp, false, []client.Object{deploy, ingress})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ready).To(BeTrue())
+		Expect(wp.GetCondition(wordpressv1alpha1.ReadyCondition).Status).To(Equal(corev1.ConditionTrue))
+		Expect(wp.GetCondition(wordpressv1alpha1.ProgressingCondition).Status).To(Equal(corev1.ConditionTrue))
+		Expect(wp.GetCondition(wordpressv1alpha1.ProgressingCondition).Reason).To(Equal(wordpressv1alpha1.IngressPendingReason))
+
+		ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+
+		_, err = r.updateHealth(context.TODO(), wp, false, []client.Object{deploy, ingress})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wp.GetCondition(wordpressv1alpha1.ProgressingCondition).Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("reports HTTPRoutes not accepted by their gateway as progressing", func() {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(sync.HTTPRouteGVK)
+		route.SetName("test")
+
+		checkHTTPRoute(h, route)
+		Expect(h.routingReason).To(Equal(wordpressv1alpha1.HTTPRoutePendingReason))
+		Expect(h.healthy()).To(BeTrue())
+
+		h = &siteHealth{}
+		Expect(unstructured.SetNestedSlice(route.Object, []interface{}{
+			map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Accepted", "status": "True"},
+				},
+			},
+		}, "status", "parents")).To(Succeed())
+
+		checkHTTPRoute(h, route)
+		Expect(h.routingReason).To(BeEmpty())
+	})
+
+	It("reports sites routed through a gateway which can't be used as degraded", func() {
+		r := &ReconcileWordpress{}
+		wp := wordpress.New(&wordpressv1alpha1.Wordpress{
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes:  []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+				Routing: &wordpressv1alpha1.RoutingSpec{Mode: wordpressv1alpha1.RoutingModeGateway},
+			},
+		})
+		wp.SetDefaults()
+
+		Expect(r.componentSyncers(wp)).To(HaveLen(1))
+		r.checkGateway(h, wp)
+		Expect(h.degradedReason).To(Equal(wordpressv1alpha1.GatewayAPINotInstalledReason))
+
+		r.gatewayAPI = true
+		h = &siteHealth{}
+		Expect(r.componentSyncers(wp)).To(HaveLen(1))
+		r.checkGateway(h, wp)
+		Expect(h.degradedReason).To(Equal(wordpressv1alpha1.GatewayNotSetReason))
+
+		wp.Spec.Routing.Gateway = &wordpressv1alpha1.GatewayReference{Name: "public"}
+		h = &siteHealth{}
+		Expect(r.componentSyncers(wp)).To(HaveLen(2))
+		r.checkGateway(h, wp)
+		Expect(h.healthy()).To(BeTrue())
+	})
+
+	It("reports failed git clones as degraded", func() {
+		pods := []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-1"},
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name: "git",
+							State: corev1.ContainerState{
+								Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+							},
+							LastTerminationState: corev1.ContainerState{
+								Terminated: &corev1.ContainerStateTerminated{ExitCode: 128, Message: "repository not found"},
+							},
+						},
+					},
+				},
+			},
+		}
+		checkPods(h, pods)
+
+		Expect(h.degradedReason).To(Equal(wordpressv1alpha1.GitCloneFailedReason))
+		Expect(h.degradedMessage).To(Equal("init container git of pod test-1 exited with code 128: repository not found"))
+	})
+
+	It("sets the conditions", func() {
+		wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+
+		setHealthConditions(wp, h)
+		Expect(wp.GetCondition(wordpressv1alpha1.ReadyCondition).Status).To(Equal(corev1.ConditionTrue))
+		Expect(wp.GetCondition(wordpressv1alpha1.ProgressingCondition).Status).To(Equal(corev1.ConditionFalse))
+		Expect(wp.GetCondition(wordpressv1alpha1.DegradedCondition).Status).To(Equal(corev1.ConditionFalse))
+
+		h.progressing(wordpressv1alpha1.PVCPendingReason, "pending")
+		setHealthConditions(wp, h)
+		Expect(wp.GetCondition(wordpressv1alpha1.ReadyCondition).Status).To(Equal(corev1.ConditionFalse))
+		Expect(wp.GetCondition(wordpressv1alpha1.ReadyCondition).Reason).To(Equal(wordpressv1alpha1.PVCPendingReason))
+		Expect(wp.GetCondition(wordpressv1alpha1.ProgressingCondition).Status).To(Equal(corev1.ConditionTrue))
+	})
+})
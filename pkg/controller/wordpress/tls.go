@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// setTLSCondition sets the TLSReady condition from the readiness of the
+// site's cert-manager certificates. The certManager flag tells whether
+// cert-manager is installed.
+func setTLSCondition(wp *wordpress.Wordpress, certManager bool, objs []client.Object) {
+	if !wp.UsesCertManager() {
+		wp.RemoveCondition(wordpressv1alpha1.TLSReadyCondition)
+
+		return
+	}
+
+	if !certManager {
+		wp.SetCondition(wordpressv1alpha1.TLSReadyCondition, corev1.ConditionFalse, wordpressv1alpha1.CertManagerNotInstalledReason,
+			"the certificates can't be issued, as cert-manager is not installed")
+
+		return
+	}
+
+	pending := []string{}
+
+	for _, obj := range objs {
+		cert, ok := obj.(*unstructured.Unstructured)
+		if !ok || cert.GroupVersionKind() != sync.CertificateGVK {
+			continue
+		}
+
+		if !certificateReady(cert) {
+			pending = append(pending, cert.GetName())
+		}
+	}
+
+	if len(pending) > 0 {
+		wp.SetCondition(wordpressv1alpha1.TLSReadyCondition, corev1.ConditionFalse, wordpressv1alpha1.CertificatesPendingReason,
+			fmt.Sprintf("waiting for certificates to be issued: %s", strings.Join(pending, ", ")))
+
+		return
+	}
+
+	wp.SetCondition(wordpressv1alpha1.TLSReadyCondition, corev1.ConditionTrue, wordpressv1alpha1.CertificatesReadyReason,
+		"all certificates are issued")
+}
+
+// certificateReady returns true if the cert-manager Certificate has the Ready
+// condition set.
+func certificateReady(cert *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == "Ready" {
+			return cond["status"] == string(corev1.ConditionTrue)
+		}
+	}
+
+	return false
+}
+
+// cleanupCertificates removes the Certificates of the site for TLS secrets
+// which are no longer issued with cert-manager, including the ones which are
+// now supplied by the user.
+func (r *ReconcileWordpress) cleanupCertificates(ctx context.Context, wp *wordpress.Wordpress) error {
+	if !r.certManager {
+		return nil
+	}
+
+	keep := map[string]bool{}
+
+	if wp.UsesCertManager() {
+		for _, group := range wp.TLSGroups() {
+			keep[group.SecretName] = group.Managed
+		}
+	}
+
+	return r.cleanupUnstructured(ctx, wp, sync.CertificateGVK, wp.ComponentLabels(wordpress.WordpressCertificate), keep)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpress/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("Wordpress TLS condition", func() {
+	var (
+		wp   *wordpress.Wordpress
+		cert *unstructured.Unstructured
+	)
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				CertManager: &wordpressv1alpha1.CertManagerSpec{
+					IssuerRef: wordpressv1alpha1.CertManagerIssuerReference{Name: "letsencrypt"},
+				},
+			},
+		})
+
+		cert = &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(sync.CertificateGVK)
+		cert.SetName("test-tls")
+	})
+
+	setReady := func(status string) {
+		Expect(unstructured.SetNestedSlice(cert.Object, []interface{}{
+			map[string]interface{}{"type": "Ready", "status": status},
+		}, "status", "conditions")).To(Succeed())
+	}
+
+	It("waits for the certificates to be issued", func() {
+		setTLSCondition(wp, true, []client.Object{cert})
+
+		cond := wp.GetCondition(wordpressv1alpha1.TLSReadyCondition)
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(wordpressv1alpha1.CertificatesPendingReason))
+		Expect(cond.Message).To(ContainSubstring("test-tls"))
+
+		setReady("False")
+		setTLSCondition(wp, true, []client.Object{cert})
+		Expect(wp.GetCondition(wordpressv1alpha1.TLSReadyCondition).Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("reports issued certificates", func() {
+		setReady("True")
+		setTLSCondition(wp, true, []client.Object{cert})
+
+		cond := wp.GetCondition(wordpressv1alpha1.TLSReadyCondition)
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(wordpressv1alpha1.CertificatesReadyReason))
+	})
+
+	It("reports that cert-manager is not installed", func() {
+		wp.Spec.Routes = []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}}
+		wp.SetDefaults()
+
+		r := &ReconcileWordpress{}
+		for _, s := range r.componentSyncers(wp) {
+			Expect(s.Object()).NotTo(BeAssignableToTypeOf(cert))
+		}
+
+		setTLSCondition(wp, false, nil)
+
+		cond := wp.GetCondition(wordpressv1alpha1.TLSReadyCondition)
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(wordpressv1alpha1.CertManagerNotInstalledReason))
+		Expect(wp.ServesHTTPS()).To(BeFalse())
+	})
+
+	It("removes the condition without cert-manager", func() {
+		setTLSCondition(wp, true, []client.Object{cert})
+		Expect(wp.GetCondition(wordpressv1alpha1.TLSReadyCondition)).NotTo(BeNil())
+
+		wp.Spec.CertManager = nil
+		setTLSCondition(wp, true, nil)
+		Expect(wp.GetCondition(wordpressv1alpha1.TLSReadyCondition)).To(BeNil())
+	})
+})
@@ -18,21 +18,28 @@ package wordpress
 
 import (
 	"context"
+	"time"
 
 	"github.com/presslabs/controller-util/syncer"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -46,12 +53,42 @@ const controllerName = "wordpress-controller"
 // Add creates a new Wordpress Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
+	if err := metrics.Registry.Register(newFleetCollector(mgr.GetClient())); err != nil {
+		return err
+	}
+
 	return add(mgr, newReconciler(mgr))
 }
 
 // newReconciler returns a new reconcile.Reconciler.
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileWordpress{Client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor(controllerName)}
+	recorder := mgr.GetEventRecorderFor(controllerName)
+
+	return &ReconcileWordpress{
+		Client:      mgr.GetClient(),
+		apiReader:   mgr.GetAPIReader(),
+		scheme:      mgr.GetScheme(),
+		recorder:    recorder,
+		gatewayAPI:  isServed(mgr, sync.HTTPRouteGVK),
+		certManager: isServed(mgr, sync.CertificateGVK),
+		monitors: map[schema.GroupVersionKind]bool{
+			sync.ServiceMonitorGVK: isServed(mgr, sync.ServiceMonitorGVK),
+			sync.PodMonitorGVK:     isServed(mgr, sync.PodMonitorGVK),
+		},
+		resolver: &jobRevisionResolver{
+			client:    mgr.GetClient(),
+			apiReader: mgr.GetAPIReader(),
+			recorder:  recorder,
+		},
+	}
+}
+
+// isServed returns whether the API server serves the given kind, eg. when
+// its CRDs are installed.
+func isServed(mgr manager.Manager, gvk schema.GroupVersionKind) bool {
+	_, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	return err == nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
@@ -62,8 +99,19 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	err = mgr.GetFieldIndexer().IndexField(context.TODO(), &wordpressv1alpha1.Wordpress{}, routeKeyField, indexRouteKeys)
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to Wordpress
-	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.Wordpress{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.Wordpress{}}, &handler.EnqueueRequestForObject{}, ignoreCronStatusUpdates)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to Wordpress sites which may release routes
+	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.Wordpress{}}, &enqueueRouteClaimants{client: mgr.GetClient()}, ignoreCronStatusUpdates)
 	if err != nil {
 		return err
 	}
@@ -74,6 +122,21 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		&corev1.Service{},
 		&corev1.Secret{},
 		&netv1.Ingress{},
+		&netv1.NetworkPolicy{},
+		&batchv1.Job{},
+		&batchv1.CronJob{},
+		&policyv1.PodDisruptionBudget{},
+		&autoscalingv1.HorizontalPodAutoscaler{},
+	}
+
+	// Watch HTTPRoutes, Certificates and monitors only if the Gateway API,
+	// cert-manager and the Prometheus Operator are installed
+	for _, gvk := range []schema.GroupVersionKind{sync.HTTPRouteGVK, sync.CertificateGVK, sync.ServiceMonitorGVK, sync.PodMonitorGVK} {
+		if isServed(mgr, gvk) {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			subresources = append(subresources, obj)
+		}
 	}
 
 	for _, subresource := range subresources {
@@ -94,15 +157,31 @@ var _ reconcile.Reconciler = &ReconcileWordpress{}
 // ReconcileWordpress reconciles a Wordpress object.
 type ReconcileWordpress struct {
 	client.Client
-	scheme   *runtime.Scheme
-	recorder record.EventRecorder
+	// apiReader reads objects which are not cached, like pods
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
+	// gatewayAPI is set if the Gateway API is installed
+	gatewayAPI bool
+	// certManager is set if cert-manager is installed
+	certManager bool
+	// monitors holds the Prometheus Operator monitor kinds which are installed
+	monitors map[schema.GroupVersionKind]bool
+	// resolver resolves the git references of the sites' code to commits
+	resolver RevisionResolver
 }
 
 // Automatically generate RBAC rules to allow the Controller to read and write Deployments
 // +kubebuilder:rbac:groups=core,resources=secrets;services;persistentvolumeclaims;events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses;networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpresses;wordpresses/status,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile reads that state of the cluster for a Wordpress object and makes changes based on the state read
@@ -125,14 +204,129 @@ func (r *ReconcileWordpress) Reconcile(ctx context.Context, request reconcile.Re
 	r.scheme.Default(wp.Unwrap())
 	wp.SetDefaults()
 
+	oldStatus := wp.Status.DeepCopy()
+
+	if err = r.checkRouteConflicts(ctx, wp); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	resolveAfter := r.resolveCodeRevision(ctx, wp)
+
 	secretSyncer := sync.NewSecretSyncer(wp, r.Client)
-	deploySyncer := sync.NewDeploymentSyncer(wp, secretSyncer.Object().(*corev1.Secret), r.Client)
+	if err = r.sync(ctx, []syncer.Interface{secretSyncer}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	web, retryUpgradeAfter, err := r.upgradeDatabase(ctx, wp)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	deploySyncer := sync.NewDeploymentSyncer(web, secretSyncer.Object().(*corev1.Secret), r.Client)
+	syncers := append([]syncer.Interface{deploySyncer}, r.componentSyncers(wp)...)
+
+	if err = r.sync(ctx, syncers); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	objs := make([]client.Object, len(syncers))
+	for i := range syncers {
+		objs[i] = syncers[i].Object().(client.Object)
+	}
+
+	setTLSCondition(wp, r.certManager, objs)
+
+	wp.Status.Replicas = deploySyncer.Object().(*appsv1.Deployment).Status.Replicas
+	wp.Status.URL = wp.HomeURL()
+	wp.Status.ObservedGeneration = wp.Generation
+
+	ready, err := r.updateHealth(ctx, wp, web != wp, objs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !equality.Semantic.DeepEqual(oldStatus, &wp.Status) {
+		if errUp := r.Status().Update(ctx, wp.Unwrap()); errUp != nil {
+			return reconcile.Result{}, errUp
+		}
+	}
+
+	if err = r.cleanup(ctx, wp); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	requeueAfter := earliest(resolveAfter, retryUpgradeAfter)
+	if !ready {
+		requeueAfter = earliest(requeueAfter, unhealthyRequeueInterval)
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// earliest returns the shortest of the given requeue intervals, ignoring the
+// zero ones, or 0 if all of them are zero.
+func earliest(intervals ...time.Duration) time.Duration {
+	var min time.Duration
+
+	for _, d := range intervals {
+		if d > 0 && (min == 0 || d < min) {
+			min = d
+		}
+	}
+
+	return min
+}
+
+// componentSyncers returns the syncers for the site's components, other than
+// the secret and the deployment.
+func (r *ReconcileWordpress) componentSyncers(wp *wordpress.Wordpress) []syncer.Interface {
 	syncers := []syncer.Interface{
-		secretSyncer,
-		deploySyncer,
 		sync.NewServiceSyncer(wp, r.Client),
-		sync.NewIngressSyncer(wp, r.Client),
-		// sync.NewDBUpgradeJobSyncer(wp, r.Client),
+	}
+
+	if len(wp.ActiveRoutes()) > 0 {
+		switch {
+		case wp.RoutingMode() != wordpressv1alpha1.RoutingModeGateway:
+			for _, group := range wp.IngressGroups() {
+				syncers = append(syncers, sync.NewIngressSyncer(wp, group, r.Client))
+			}
+
+			for _, redirect := range wp.Redirects() {
+				syncers = append(syncers, sync.NewRedirectIngressSyncer(wp, redirect, r.Client))
+			}
+		case r.gatewayAPI && wp.Gateway() != nil:
+			for _, domain := range wp.ActiveDomains() {
+				syncers = append(syncers, sync.NewHTTPRouteSyncer(wp, domain, r.Client))
+			}
+		}
+		// otherwise the site is reported as degraded, by checkGateway
+	}
+
+	// certificates are issued only if cert-manager is installed, otherwise the
+	// TLSReady condition reports it
+	if wp.UsesCertManager() && r.certManager {
+		for _, group := range wp.TLSGroups() {
+			if group.Managed {
+				syncers = append(syncers, sync.NewCertificateSyncer(wp, group, r.Client))
+			}
+		}
+	}
+
+	// monitors are created only if their CRD is installed
+	if wp.Spec.Monitoring != nil && r.monitors[sync.MonitorGVK(wp)] {
+		syncers = append(syncers, sync.NewMonitorSyncer(wp, r.Client))
+	}
+
+	if wp.Spec.NetworkPolicy != nil {
+		syncers = append(syncers, sync.NewWebNetworkPolicySyncer(wp, r.Client), sync.NewJobNetworkPolicySyncer(wp, r.Client))
+	}
+
+	if wp.Spec.DisruptionBudget != nil {
+		syncers = append(syncers, sync.NewPDBSyncer(wp, r.Client))
+	}
+
+	if wp.Spec.Autoscaling != nil {
+		syncers = append(syncers, sync.NewHPASyncer(wp, r.Client))
 	}
 
 	if wp.Spec.CodeVolumeSpec != nil && wp.Spec.CodeVolumeSpec.PersistentVolumeClaim != nil {
@@ -143,25 +337,110 @@ func (r *ReconcileWordpress) Reconcile(ctx context.Context, request reconcile.Re
 		syncers = append(syncers, sync.NewMediaPVCSyncer(wp, r.Client))
 	}
 
-	if err = r.sync(ctx, syncers); err != nil {
-		return reconcile.Result{}, err
+	if wp.Spec.Cron.Mode == wordpressv1alpha1.CronModeJob {
+		syncers = append(syncers, sync.NewCronJobSyncer(wp, r.Client))
 	}
 
-	oldStatus := wp.Status.DeepCopy()
-	wp.Status.Replicas = deploySyncer.Object().(*appsv1.Deployment).Status.Replicas
+	return syncers
+}
 
-	if oldStatus.Replicas != wp.Status.Replicas {
-		if errUp := r.Status().Update(ctx, wp.Unwrap()); errUp != nil {
-			return reconcile.Result{}, errUp
+// cleanup removes the site's resources which are no longer needed.
+func (r *ReconcileWordpress) cleanup(ctx context.Context, wp *wordpress.Wordpress) error {
+	// remove the wp-cron job if wp-cron no longer runs in job mode
+	if wp.Spec.Cron.Mode != wordpressv1alpha1.CronModeJob {
+		if err := r.cleanupCronJob(ctx, wp); err != nil {
+			return err
 		}
 	}
 
-	// remove old cron job if exists
-	if err = r.cleanupCronJob(ctx, wp); err != nil {
-		return reconcile.Result{}, err
+	if wp.Spec.NetworkPolicy == nil {
+		for _, name := range []string{
+			wp.ComponentName(wordpress.WordpressWebNetworkPolicy),
+			wp.ComponentName(wordpress.WordpressJobNetworkPolicy),
+		} {
+			if err := r.cleanupOwned(ctx, wp, &netv1.NetworkPolicy{}, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if wp.Spec.DisruptionBudget == nil {
+		if err := r.cleanupOwned(ctx, wp, &policyv1.PodDisruptionBudget{}, wp.ComponentName(wordpress.WordpressPDB)); err != nil {
+			return err
+		}
+	}
+
+	if wp.Spec.Autoscaling == nil {
+		if err := r.cleanupOwned(ctx, wp, &autoscalingv1.HorizontalPodAutoscaler{}, wp.ComponentName(wordpress.WordpressHPA)); err != nil {
+			return err
+		}
+	}
+
+	if wp.GitResolveInterval() <= 0 {
+		if err := r.cleanupOwned(ctx, wp, &batchv1.Job{}, wp.ComponentName(wordpress.WordpressGitResolve)); err != nil {
+			return err
+		}
+	}
+
+	// remove upgrade jobs for previous images
+	if err := r.cleanupDBUpgradeJobs(ctx, wp); err != nil {
+		return err
 	}
 
-	return reconcile.Result{}, nil
+	if err := r.cleanupIngresses(ctx, wp); err != nil {
+		return err
+	}
+
+	if err := r.cleanupHTTPRoutes(ctx, wp); err != nil {
+		return err
+	}
+
+	if err := r.cleanupCertificates(ctx, wp); err != nil {
+		return err
+	}
+
+	return r.cleanupMonitors(ctx, wp)
+}
+
+// cleanupMonitors removes the monitors of the site which are no longer
+// needed, eg. after switching between a ServiceMonitor and a PodMonitor.
+func (r *ReconcileWordpress) cleanupMonitors(ctx context.Context, wp *wordpress.Wordpress) error {
+	for gvk, served := range r.monitors {
+		if !served || (wp.Spec.Monitoring != nil && gvk == sync.MonitorGVK(wp)) {
+			continue
+		}
+
+		if err := r.cleanupUnstructured(ctx, wp, gvk, wp.ComponentLabels(wordpress.WordpressMonitor), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanupUnstructured removes the objects of the given kind which are owned by
+// the site and match the labels, except for the ones to keep.
+func (r *ReconcileWordpress) cleanupUnstructured(ctx context.Context, wp *wordpress.Wordpress, gvk schema.GroupVersionKind,
+	objLabels map[string]string, keep map[string]bool) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := r.apiReader.List(ctx, list, client.InNamespace(wp.Namespace), client.MatchingLabels(objLabels)); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if keep[obj.GetName()] || !isOwnedBy(obj.GetOwnerReferences(), wp) {
+			continue
+		}
+
+		if err := r.Delete(ctx, obj); ignoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func ignoreNotFound(err error) error {
@@ -174,7 +453,7 @@ func ignoreNotFound(err error) error {
 
 func (r *ReconcileWordpress) sync(ctx context.Context, syncers []syncer.Interface) error {
 	for _, s := range syncers {
-		if err := syncer.Sync(ctx, s, r.recorder); err != nil {
+		if err := syncer.Sync(ctx, &instrumentedSyncer{s}, r.recorder); err != nil {
 			return err
 		}
 	}
@@ -203,22 +482,26 @@ func (r *ReconcileWordpress) maybeMigrate(wp *wordpressv1alpha1.Wordpress) (*wor
 }
 
 func (r *ReconcileWordpress) cleanupCronJob(ctx context.Context, wp *wordpress.Wordpress) error {
-	cronKey := types.NamespacedName{
-		Name:      wp.ComponentName(wordpress.WordpressCron),
+	return r.cleanupOwned(ctx, wp, &batchv1.CronJob{}, wp.ComponentName(wordpress.WordpressCron))
+}
+
+// cleanupOwned removes the object with the given name, if it's owned by the
+// site.
+func (r *ReconcileWordpress) cleanupOwned(ctx context.Context, wp *wordpress.Wordpress, obj client.Object, name string) error {
+	key := types.NamespacedName{
+		Name:      name,
 		Namespace: wp.Namespace,
 	}
 
-	cronJob := &batchv1.CronJob{}
-
-	if err := r.Get(ctx, cronKey, cronJob); err != nil {
+	if err := r.Get(ctx, key, obj); err != nil {
 		return ignoreNotFound(err)
 	}
 
-	if !isOwnedBy(cronJob.OwnerReferences, wp) {
+	if !isOwnedBy(obj.GetOwnerReferences(), wp) {
 		return nil
 	}
 
-	return r.Delete(ctx, cronJob)
+	return ignoreNotFound(r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)))
 }
 
 func isOwnedBy(refs []metav1.OwnerReference, owner *wordpress.Wordpress) bool {
@@ -27,6 +27,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -37,6 +38,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
 )
 
 const timeout = time.Second * 5
@@ -182,5 +184,100 @@ var _ = Describe("Wordpress controller", func() {
 			Expect(c.Get(context.TODO(), key, deploy)).To(Succeed())
 			Expect(deploy.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
 		})
+
+		It("does not render routes claimed by other sites", func() {
+			// drain reconcile requests, as the controller blocks on them
+			go func() {
+				for range requests {
+				}
+			}()
+
+			other := &wordpressv1alpha1.Wordpress{
+				ObjectMeta: metav1.ObjectMeta{Name: wp.Name + "-other", Namespace: wp.Namespace},
+				Spec: wordpressv1alpha1.WordpressSpec{
+					Routes: wp.Spec.Routes,
+				},
+			}
+			Expect(c.Create(context.TODO(), other)).To(Succeed())
+			defer c.Delete(context.TODO(), other) // nolint: errcheck
+
+			otherKey := types.NamespacedName{Name: other.Name, Namespace: other.Namespace}
+
+			Eventually(func() []wordpressv1alpha1.RouteSpec {
+				Expect(c.Get(context.TODO(), otherKey, other)).To(Succeed())
+				return other.Status.ConflictingRoutes
+			}, timeout).Should(Equal(wp.Spec.Routes))
+
+			cond := wordpress.New(other).GetCondition(wordpressv1alpha1.RouteConflictCondition)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+			Expect(c.Get(context.TODO(), otherKey, &netv1.Ingress{})).ToNot(Succeed())
+
+			// release the route
+			Expect(c.Get(context.TODO(), types.NamespacedName{Name: wp.Name, Namespace: wp.Namespace}, wp)).To(Succeed())
+			wp.Spec.Routes = []wordpressv1alpha1.RouteSpec{{Domain: fmt.Sprintf("%s.example.org", wp.Name)}}
+			Expect(c.Update(context.TODO(), wp)).To(Succeed())
+
+			Eventually(func() error { return c.Get(context.TODO(), otherKey, &netv1.Ingress{}) }, timeout).Should(Succeed())
+
+			Eventually(func() []wordpressv1alpha1.RouteSpec {
+				Expect(c.Get(context.TODO(), otherKey, other)).To(Succeed())
+				return other.Status.ConflictingRoutes
+			}, timeout).Should(BeEmpty())
+			Expect(wordpress.New(other).GetCondition(wordpressv1alpha1.RouteConflictCondition).Status).To(Equal(corev1.ConditionFalse))
+		})
+
+		It("upgrades the database before rolling out a new image", func() {
+			// drain reconcile requests, as the controller blocks on them
+			go func() {
+				for range requests {
+				}
+			}()
+
+			key := types.NamespacedName{
+				Name:      wp.Name,
+				Namespace: wp.Namespace,
+			}
+			deploy := &appsv1.Deployment{}
+			Eventually(func() error { return c.Get(context.TODO(), key, deploy) }, timeout).Should(Succeed())
+			oldImage := deploy.Spec.Template.Spec.Containers[0].Image
+			newImage := "docker.io/bitpoke/wordpress-runtime:test"
+
+			Expect(c.Get(context.TODO(), key, wp)).To(Succeed())
+			wp.Spec.Image = newImage
+			Expect(c.Update(context.TODO(), wp)).To(Succeed())
+
+			job := &batchv1.Job{}
+			jobKey := types.NamespacedName{
+				Name:      wordpress.New(wp).ComponentName(wordpress.WordpressDBUpgrade),
+				Namespace: wp.Namespace,
+			}
+			Eventually(func() error { return c.Get(context.TODO(), jobKey, job) }, timeout).Should(Succeed())
+
+			// the deployment keeps the old image while the upgrade is running
+			Consistently(func() string {
+				Expect(c.Get(context.TODO(), key, deploy)).To(Succeed())
+				return deploy.Spec.Template.Spec.Containers[0].Image
+			}).Should(Equal(oldImage))
+
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			}
+			Expect(c.Status().Update(context.TODO(), job)).To(Succeed())
+
+			Eventually(func() string {
+				Expect(c.Get(context.TODO(), key, deploy)).To(Succeed())
+				return deploy.Spec.Template.Spec.Containers[0].Image
+			}, timeout).Should(Equal(newImage))
+
+			Eventually(func() corev1.ConditionStatus {
+				Expect(c.Get(context.TODO(), key, wp)).To(Succeed())
+				cond := wordpress.New(wp).GetCondition(wordpressv1alpha1.DBUpgradedCondition)
+				if cond == nil {
+					return corev1.ConditionUnknown
+				}
+				return cond.Status
+			}, timeout).Should(Equal(corev1.ConditionTrue))
+		})
 	})
 })
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"github.com/appscode/mergo"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/presslabs/controller-util/mergo/transformers"
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+)
+
+const cleanupBackoffLimit int32 = 3
+
+// NewCleanupJobSyncer returns a new sync.Interface for reconciling the Job
+// which removes the backup artifacts. The job runs while the backup is being
+// deleted, so the owner reference is set here rather than by the syncer, which
+// refuses to create objects for owners which are being deleted.
+func NewCleanupJobSyncer(b *backup.Backup, c client.Client) syncer.Interface {
+	obj := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.CleanupJobName(),
+			Namespace: b.Namespace,
+		},
+	}
+
+	backoffLimit := cleanupBackoffLimit
+
+	return syncer.NewObjectSyncer("BackupCleanupJob", nil, obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, b.Labels()), controllerLabels)
+
+		if err := controllerutil.SetControllerReference(b.Unwrap(), obj, c.Scheme()); err != nil {
+			return err
+		}
+
+		if !obj.CreationTimestamp.IsZero() {
+			return nil
+		}
+
+		obj.Spec.BackoffLimit = &backoffLimit
+
+		template := b.CleanupPodTemplateSpec()
+		obj.Spec.Template.ObjectMeta = template.ObjectMeta
+
+		return mergo.Merge(&obj.Spec.Template.Spec, template.Spec, mergo.WithTransformers(transformers.PodSpec))
+	})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressbackup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+)
+
+// updateStatus updates the backup status and conditions from the status of
+// the backup job.
+func (r *ReconcileWordpressBackup) updateStatus(ctx context.Context, b *backup.Backup, job *batchv1.Job) error {
+	if job.Status.StartTime != nil {
+		b.Status.StartTime = job.Status.StartTime
+	}
+
+	if cond := jobCondition(job, batchv1.JobFailed); cond != nil {
+		msg := fmt.Sprintf("job %s failed: %s", job.Name, cond.Message)
+		b.SetCondition(wordpressv1alpha1.BackupCompleteCondition, corev1.ConditionFalse, wordpressv1alpha1.BackupFailedReason, msg)
+
+		if b.SetCondition(wordpressv1alpha1.BackupFailedCondition, corev1.ConditionTrue, wordpressv1alpha1.BackupFailedReason, msg) {
+			r.recorder.Event(b.Unwrap(), corev1.EventTypeWarning, wordpressv1alpha1.BackupFailedReason, msg)
+		}
+
+		return nil
+	}
+
+	if jobCondition(job, batchv1.JobComplete) == nil {
+		b.SetCondition(wordpressv1alpha1.BackupCompleteCondition, corev1.ConditionFalse, wordpressv1alpha1.BackupRunningReason,
+			fmt.Sprintf("job %s is running", job.Name))
+
+		return nil
+	}
+
+	size, err := r.artifactsSize(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	b.Status.Size = size
+	b.Status.CompletionTime = job.Status.CompletionTime
+
+	if b.Status.StartTime != nil && b.Status.CompletionTime != nil {
+		b.Status.Duration = &metav1.Duration{Duration: b.Status.CompletionTime.Sub(b.Status.StartTime.Time)}
+	}
+
+	msg := fmt.Sprintf("backup uploaded to %s", b.Status.Location)
+	b.SetCondition(wordpressv1alpha1.BackupFailedCondition, corev1.ConditionFalse, wordpressv1alpha1.BackupSucceededReason, "")
+
+	if b.SetCondition(wordpressv1alpha1.BackupCompleteCondition, corev1.ConditionTrue, wordpressv1alpha1.BackupSucceededReason, msg) {
+		r.recorder.Event(b.Unwrap(), corev1.EventTypeNormal, wordpressv1alpha1.BackupSucceededReason, msg)
+	}
+
+	return nil
+}
+
+// artifactsSize returns the size of the uploaded artifacts, as reported by the
+// upload container of the succeeded job pod, or nil if it's not known.
+func (r *ReconcileWordpressBackup) artifactsSize(ctx context.Context, job *batchv1.Job) (*resource.Quantity, error) {
+	pods := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != backup.UploadContainerName || status.State.Terminated == nil {
+				continue
+			}
+
+			// the termination message is the output of `rclone size --json`
+			size := struct {
+				Bytes int64 `json:"bytes"`
+			}{}
+
+			if err := json.Unmarshal([]byte(status.State.Terminated.Message), &size); err != nil {
+				return nil, nil // nolint: nilerr
+			}
+
+			return resource.NewQuantity(size.Bytes, resource.BinarySI), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func jobCondition(job *batchv1.Job, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == condType && job.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
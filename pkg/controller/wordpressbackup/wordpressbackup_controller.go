@@ -0,0 +1,249 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressbackup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/presslabs/controller-util/syncer"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpressbackup/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const controllerName = "wordpressbackup-controller"
+
+// Add creates a new WordpressBackup Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileWordpressBackup{
+		Client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		scheme:    mgr.GetScheme(),
+		recorder:  mgr.GetEventRecorderFor(controllerName),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler.
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to WordpressBackup
+	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.WordpressBackup{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the backup and cleanup jobs
+	return c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &wordpressv1alpha1.WordpressBackup{},
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileWordpressBackup{}
+
+// ReconcileWordpressBackup reconciles a WordpressBackup object.
+type ReconcileWordpressBackup struct {
+	client.Client
+	// apiReader reads objects which are not cached, like pods
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
+}
+
+// Automatically generate RBAC rules to allow the Controller to run backup jobs
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressbackups;wordpressbackups/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressbackups/finalizers,verbs=update
+
+// Reconcile runs the backup job of a WordpressBackup and records its outcome
+// in the WordpressBackup status.
+func (r *ReconcileWordpressBackup) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	b := backup.New(&wordpressv1alpha1.WordpressBackup{})
+
+	err := r.Get(ctx, request.NamespacedName, b.Unwrap())
+	if err != nil {
+		return reconcile.Result{}, ignoreNotFound(err)
+	}
+
+	r.scheme.Default(b.Unwrap())
+
+	if !b.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, r.finalize(ctx, b)
+	}
+
+	if err = r.updateFinalizer(ctx, b); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	oldStatus := b.Status.DeepCopy()
+
+	if !b.IsFinished() {
+		if err = r.runBackup(ctx, b); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(oldStatus, &b.Status) {
+		if err = r.Status().Update(ctx, b.Unwrap()); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// runBackup creates the backup job, if it doesn't exist, and updates the
+// backup status from it.
+func (r *ReconcileWordpressBackup) runBackup(ctx context.Context, b *backup.Backup) error {
+	job := &batchv1.Job{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: b.JobName(), Namespace: b.Namespace}, job)
+	if errors.IsNotFound(err) {
+		job, err = r.createJob(ctx, b)
+	}
+
+	if err != nil || job == nil {
+		return err
+	}
+
+	b.Status.Location = b.Location()
+
+	return r.updateStatus(ctx, b, job)
+}
+
+// createJob creates the backup job from the referenced site. It returns a nil
+// job if the site does not exist, in which case the backup is marked as failed.
+func (r *ReconcileWordpressBackup) createJob(ctx context.Context, b *backup.Backup) (*batchv1.Job, error) {
+	wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+
+	err := r.Get(ctx, types.NamespacedName{Name: b.Spec.WordpressRef.Name, Namespace: b.Namespace}, wp.Unwrap())
+	if errors.IsNotFound(err) {
+		msg := fmt.Sprintf("wordpress %s not found", b.Spec.WordpressRef.Name)
+		b.SetCondition(wordpressv1alpha1.BackupCompleteCondition, corev1.ConditionFalse, wordpressv1alpha1.WordpressNotFoundReason, msg)
+
+		if b.SetCondition(wordpressv1alpha1.BackupFailedCondition, corev1.ConditionTrue, wordpressv1alpha1.WordpressNotFoundReason, msg) {
+			r.recorder.Event(b.Unwrap(), corev1.EventTypeWarning, wordpressv1alpha1.WordpressNotFoundReason, msg)
+		}
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.scheme.Default(wp.Unwrap())
+	wp.SetDefaults()
+
+	jobSyncer := sync.NewJobSyncer(b, wp, r.Client)
+	if err = syncer.Sync(ctx, jobSyncer, r.recorder); err != nil {
+		return nil, err
+	}
+
+	return jobSyncer.Object().(*batchv1.Job), nil
+}
+
+// updateFinalizer makes sure the artifacts finalizer is set only on backups
+// whose artifacts have to be removed on deletion.
+func (r *ReconcileWordpressBackup) updateFinalizer(ctx context.Context, b *backup.Backup) error {
+	wanted := b.Spec.DeletionPolicy == wordpressv1alpha1.BackupDeletionPolicyDelete
+	if wanted == controllerutil.ContainsFinalizer(b.Unwrap(), backup.ArtifactsFinalizer) {
+		return nil
+	}
+
+	if wanted {
+		controllerutil.AddFinalizer(b.Unwrap(), backup.ArtifactsFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(b.Unwrap(), backup.ArtifactsFinalizer)
+	}
+
+	return r.Update(ctx, b.Unwrap())
+}
+
+// finalize removes the backup artifacts of a deleted backup by running the
+// cleanup job. The finalizer is kept if the cleanup job fails, so that the
+// artifacts are not orphaned silently.
+func (r *ReconcileWordpressBackup) finalize(ctx context.Context, b *backup.Backup) error {
+	if !controllerutil.ContainsFinalizer(b.Unwrap(), backup.ArtifactsFinalizer) {
+		return nil
+	}
+
+	// nothing was uploaded if the backup job was never created
+	if b.Status.Location != "" {
+		cleanupSyncer := sync.NewCleanupJobSyncer(b, r.Client)
+		if err := syncer.Sync(ctx, cleanupSyncer, r.recorder); err != nil {
+			return err
+		}
+
+		job := cleanupSyncer.Object().(*batchv1.Job)
+
+		if jobCondition(job, batchv1.JobFailed) != nil {
+			r.recorder.Eventf(b.Unwrap(), corev1.EventTypeWarning, "CleanupFailed",
+				"job %s failed to remove the artifacts at %s, remove the %s finalizer to delete the backup regardless",
+				job.Name, b.Status.Location, backup.ArtifactsFinalizer)
+
+			return nil
+		}
+
+		if jobCondition(job, batchv1.JobComplete) == nil {
+			return nil
+		}
+	}
+
+	controllerutil.RemoveFinalizer(b.Unwrap(), backup.ArtifactsFinalizer)
+
+	return r.Update(ctx, b.Unwrap())
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressbackup
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+)
+
+var _ = Describe("WordpressBackup controller", func() {
+	var (
+		r   *ReconcileWordpressBackup
+		c   client.Client
+		ctx context.Context
+		key types.NamespacedName
+		obj *wordpressv1alpha1.WordpressBackup
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "daily", Namespace: "default"}
+
+		obj = &wordpressv1alpha1.WordpressBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: wordpressv1alpha1.WordpressBackupSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: "site"},
+				Destination: wordpressv1alpha1.BackupDestination{
+					S3: &wordpressv1alpha1.S3VolumeSource{Bucket: "backups"},
+				},
+			},
+		}
+
+		wp := &wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: key.Namespace},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+			},
+		}
+
+		c = testutil.NewFakeClient(obj, wp)
+		r = &ReconcileWordpressBackup{
+			Client:    c,
+			apiReader: c,
+			scheme:    c.Scheme(),
+			recorder:  record.NewFakeRecorder(100),
+		}
+	})
+
+	reconcileBackup := func() *backup.Backup {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		b := backup.New(&wordpressv1alpha1.WordpressBackup{})
+		Expect(c.Get(ctx, key, b.Unwrap())).To(Succeed())
+
+		return b
+	}
+
+	setJobCondition := func(name string, condType batchv1.JobConditionType) {
+		job := &batchv1.Job{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: name, Namespace: key.Namespace}, job)).To(Succeed())
+
+		start := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+		completion := metav1.NewTime(start.Add(42 * time.Second))
+
+		job.Status.StartTime = &start
+		job.Status.CompletionTime = &completion
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condType, Status: corev1.ConditionTrue, Message: "done"}}
+		Expect(c.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("runs the backup job", func() {
+		b := reconcileBackup()
+
+		job := &batchv1.Job{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: b.JobName(), Namespace: key.Namespace}, job)).To(Succeed())
+		Expect(*job.Spec.BackoffLimit).To(BeEquivalentTo(0))
+		Expect(job.Spec.Template.Spec.Containers[0].Name).To(Equal(backup.UploadContainerName))
+
+		Expect(b.Status.Location).To(Equal("s3://backups/default/site/daily"))
+		Expect(b.GetCondition(wordpressv1alpha1.BackupCompleteCondition).Reason).To(Equal(wordpressv1alpha1.BackupRunningReason))
+		Expect(b.IsFinished()).To(BeFalse())
+	})
+
+	It("records the size and duration of completed backups", func() {
+		b := reconcileBackup()
+		setJobCondition(b.JobName(), batchv1.JobComplete)
+
+		Expect(c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "daily-abcde",
+				Namespace: key.Namespace,
+				Labels:    map[string]string{"job-name": b.JobName()},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: backup.UploadContainerName,
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{Message: `{"count":3,"bytes":2048}`},
+						},
+					},
+				},
+			},
+		})).To(Succeed())
+
+		b = reconcileBackup()
+		Expect(b.IsComplete()).To(BeTrue())
+		Expect(b.Status.Size.Cmp(resource.MustParse("2Ki"))).To(Equal(0))
+		Expect(b.Status.Duration.Duration).To(Equal(42 * time.Second))
+	})
+
+	It("marks failed backup jobs", func() {
+		b := reconcileBackup()
+		setJobCondition(b.JobName(), batchv1.JobFailed)
+
+		b = reconcileBackup()
+		Expect(b.IsFailed()).To(BeTrue())
+		Expect(b.IsComplete()).To(BeFalse())
+	})
+
+	It("fails backups of missing sites", func() {
+		obj.Spec.WordpressRef.Name = "missing"
+		Expect(c.Update(ctx, obj)).To(Succeed())
+
+		b := reconcileBackup()
+		Expect(b.IsFailed()).To(BeTrue())
+		Expect(b.GetCondition(wordpressv1alpha1.BackupFailedCondition).Reason).To(Equal(wordpressv1alpha1.WordpressNotFoundReason))
+		Expect(b.Status.Location).To(BeEmpty())
+	})
+
+	It("removes the artifacts of deleted backups with the Delete policy", func() {
+		obj.Spec.DeletionPolicy = wordpressv1alpha1.BackupDeletionPolicyDelete
+		Expect(c.Update(ctx, obj)).To(Succeed())
+
+		b := reconcileBackup()
+		Expect(b.Finalizers).To(ConsistOf(backup.ArtifactsFinalizer))
+
+		Expect(c.Delete(ctx, b.Unwrap())).To(Succeed())
+
+		b = reconcileBackup()
+		Expect(b.Finalizers).To(ConsistOf(backup.ArtifactsFinalizer))
+
+		setJobCondition(b.CleanupJobName(), batchv1.JobComplete)
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, key, &wordpressv1alpha1.WordpressBackup{})).NotTo(Succeed())
+	})
+
+	It("keeps the artifacts of deleted backups with the Retain policy", func() {
+		b := reconcileBackup()
+		Expect(b.Finalizers).To(BeEmpty())
+	})
+})
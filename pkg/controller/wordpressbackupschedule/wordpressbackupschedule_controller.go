@@ -0,0 +1,295 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressbackupschedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+)
+
+const (
+	controllerName = "wordpressbackupschedule-controller"
+
+	defaultSuccessfulBackupsHistoryLimit = 7
+	defaultFailedBackupsHistoryLimit     = 1
+)
+
+// Add creates a new WordpressBackupSchedule Controller and adds it to the Manager with default RBAC. The Manager will set fields on
+// the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileWordpressBackupSchedule{
+		Client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(controllerName),
+		now:      time.Now,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler.
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to WordpressBackupSchedule
+	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.WordpressBackupSchedule{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the scheduled backups, to enforce the history limits
+	return c.Watch(&source.Kind{Type: &wordpressv1alpha1.WordpressBackup{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &wordpressv1alpha1.WordpressBackupSchedule{},
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileWordpressBackupSchedule{}
+
+// ReconcileWordpressBackupSchedule reconciles a WordpressBackupSchedule object.
+type ReconcileWordpressBackupSchedule struct {
+	client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	// now returns the current time
+	now func() time.Time
+}
+
+// Automatically generate RBAC rules to allow the Controller to create backups
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressbackupschedules;wordpressbackupschedules/status,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates the WordpressBackups of a WordpressBackupSchedule when
+// they are due and removes the backups which exceed the history limits.
+func (r *ReconcileWordpressBackupSchedule) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	schedule := &wordpressv1alpha1.WordpressBackupSchedule{}
+
+	err := r.Get(ctx, request.NamespacedName, schedule)
+	if err != nil {
+		return reconcile.Result{}, ignoreNotFound(err)
+	}
+
+	r.scheme.Default(schedule)
+
+	sched, err := cron.ParseStandard(schedule.Spec.Schedule)
+	if err != nil {
+		r.recorder.Eventf(schedule, corev1.EventTypeWarning, "InvalidSchedule", "unparseable schedule %q: %s", schedule.Spec.Schedule, err)
+
+		// there's no point in retrying until the schedule gets updated
+		return reconcile.Result{}, nil
+	}
+
+	backups, err := r.listBackups(ctx, schedule)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = r.cleanupHistory(ctx, schedule, backups); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if schedule.Spec.Suspend {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := schedule.Status.DeepCopy()
+	now := r.now()
+
+	earliest := schedule.CreationTimestamp.Time
+	if schedule.Status.LastScheduleTime != nil {
+		earliest = schedule.Status.LastScheduleTime.Time
+	}
+
+	if scheduled := mostRecentScheduleTime(sched, earliest, now); scheduled != nil {
+		if err = r.scheduleBackup(ctx, schedule, backups, *scheduled); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(oldStatus, &schedule.Status) {
+		if err = r.Status().Update(ctx, schedule); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: sched.Next(now).Sub(now)}, nil
+}
+
+// scheduleBackup creates the backup scheduled at the given time, unless a
+// previous backup is still running.
+func (r *ReconcileWordpressBackupSchedule) scheduleBackup(ctx context.Context, schedule *wordpressv1alpha1.WordpressBackupSchedule,
+	backups []*backup.Backup, scheduled time.Time) error {
+	schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+
+	for _, b := range backups {
+		if !b.IsFinished() {
+			r.recorder.Eventf(schedule, corev1.EventTypeWarning, "BackupSkipped",
+				"backup %s is still running, skipping the backup scheduled at %s", b.Name, scheduled.Format(time.RFC3339))
+
+			return nil
+		}
+	}
+
+	b := &wordpressv1alpha1.WordpressBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", schedule.Name, scheduled.Unix()/60),
+			Namespace: schedule.Namespace,
+			Labels: map[string]string{
+				backup.ScheduleLabel: schedule.Name,
+			},
+		},
+		Spec: *schedule.Spec.BackupTemplate.DeepCopy(),
+	}
+
+	if err := controllerutil.SetControllerReference(schedule, b, r.scheme); err != nil {
+		return err
+	}
+
+	// the backup may have been created already, if updating the status failed
+	if err := r.Create(ctx, b); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	schedule.Status.LastBackup = b.Name
+
+	r.recorder.Eventf(schedule, corev1.EventTypeNormal, "BackupCreated", "created backup %s", b.Name)
+
+	return nil
+}
+
+// listBackups returns the backups created by the schedule, from the oldest to the newest.
+func (r *ReconcileWordpressBackupSchedule) listBackups(ctx context.Context,
+	schedule *wordpressv1alpha1.WordpressBackupSchedule) ([]*backup.Backup, error) {
+	list := &wordpressv1alpha1.WordpressBackupList{}
+	if err := r.List(ctx, list, client.InNamespace(schedule.Namespace), client.MatchingLabels{backup.ScheduleLabel: schedule.Name}); err != nil {
+		return nil, err
+	}
+
+	backups := []*backup.Backup{}
+
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], schedule) {
+			backups = append(backups, backup.New(&list.Items[i]))
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		if backups[i].CreationTimestamp.Equal(&backups[j].CreationTimestamp) {
+			return backups[i].Name < backups[j].Name
+		}
+
+		return backups[i].CreationTimestamp.Before(&backups[j].CreationTimestamp)
+	})
+
+	return backups, nil
+}
+
+// cleanupHistory removes the oldest finished backups which exceed the history limits.
+func (r *ReconcileWordpressBackupSchedule) cleanupHistory(ctx context.Context, schedule *wordpressv1alpha1.WordpressBackupSchedule,
+	backups []*backup.Backup) error {
+	successful := []*backup.Backup{}
+	failed := []*backup.Backup{}
+
+	for _, b := range backups {
+		switch {
+		case !b.DeletionTimestamp.IsZero():
+			continue
+		case b.IsComplete():
+			successful = append(successful, b)
+		case b.IsFailed():
+			failed = append(failed, b)
+		}
+	}
+
+	stale := exceeding(successful, historyLimit(schedule.Spec.SuccessfulBackupsHistoryLimit, defaultSuccessfulBackupsHistoryLimit))
+	stale = append(stale, exceeding(failed, historyLimit(schedule.Spec.FailedBackupsHistoryLimit, defaultFailedBackupsHistoryLimit))...)
+
+	for _, b := range stale {
+		if err := r.Delete(ctx, b.Unwrap()); ignoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mostRecentScheduleTime returns the latest time the schedule fired after
+// earliest and not later than now, or nil if it didn't fire in that interval.
+func mostRecentScheduleTime(sched cron.Schedule, earliest, now time.Time) *time.Time {
+	var last *time.Time
+
+	// Next returns the zero time if the schedule never fires
+	for t := sched.Next(earliest); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		scheduled := t
+		last = &scheduled
+	}
+
+	return last
+}
+
+// exceeding returns the oldest backups which exceed the limit.
+func exceeding(backups []*backup.Backup, limit int32) []*backup.Backup {
+	if len(backups) <= int(limit) {
+		return nil
+	}
+
+	return backups[:len(backups)-int(limit)]
+}
+
+func historyLimit(limit *int32, def int32) int32 {
+	if limit == nil {
+		return def
+	}
+
+	return *limit
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressbackupschedule
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+)
+
+var _ = Describe("WordpressBackupSchedule controller", func() {
+	var (
+		r        *ReconcileWordpressBackupSchedule
+		c        client.Client
+		ctx      context.Context
+		key      types.NamespacedName
+		schedule *wordpressv1alpha1.WordpressBackupSchedule
+		now      time.Time
+		created  time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "daily", Namespace: "default"}
+		created = time.Date(2021, 11, 1, 10, 30, 0, 0, time.UTC)
+		now = created
+
+		schedule = &wordpressv1alpha1.WordpressBackupSchedule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              key.Name,
+				Namespace:         key.Namespace,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: wordpressv1alpha1.WordpressBackupScheduleSpec{
+				Schedule: "0 3 * * *",
+				BackupTemplate: wordpressv1alpha1.WordpressBackupSpec{
+					WordpressRef: corev1.LocalObjectReference{Name: "site"},
+				},
+				SuccessfulBackupsHistoryLimit: pointer.Int32Ptr(2),
+				FailedBackupsHistoryLimit:     pointer.Int32Ptr(1),
+			},
+		}
+
+		c = testutil.NewFakeClient(schedule)
+		r = &ReconcileWordpressBackupSchedule{
+			Client:   c,
+			scheme:   c.Scheme(),
+			recorder: record.NewFakeRecorder(100),
+			now:      func() time.Time { return now },
+		}
+	})
+
+	reconcileSchedule := func() reconcile.Result {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, key, schedule)).To(Succeed())
+
+		return result
+	}
+
+	listBackups := func() []wordpressv1alpha1.WordpressBackup {
+		backups := &wordpressv1alpha1.WordpressBackupList{}
+		Expect(c.List(ctx, backups, client.InNamespace(key.Namespace))).To(Succeed())
+
+		return backups.Items
+	}
+
+	finishBackup := func(name string, condType wordpressv1alpha1.WordpressConditionType) {
+		b := backup.New(&wordpressv1alpha1.WordpressBackup{})
+		Expect(c.Get(ctx, types.NamespacedName{Name: name, Namespace: key.Namespace}, b.Unwrap())).To(Succeed())
+		b.SetCondition(condType, corev1.ConditionTrue, "", "")
+		Expect(c.Status().Update(ctx, b.Unwrap())).To(Succeed())
+	}
+
+	It("waits for the next scheduled time", func() {
+		result := reconcileSchedule()
+
+		Expect(listBackups()).To(BeEmpty())
+		Expect(result.RequeueAfter).To(Equal(16*time.Hour + 30*time.Minute))
+	})
+
+	It("creates the backups when they are due", func() {
+		now = created.Add(17 * time.Hour)
+		result := reconcileSchedule()
+
+		backups := listBackups()
+		Expect(backups).To(HaveLen(1))
+		Expect(backups[0].Spec).To(Equal(schedule.Spec.BackupTemplate))
+		Expect(backups[0].Labels).To(HaveKeyWithValue(backup.ScheduleLabel, key.Name))
+		Expect(metav1.IsControlledBy(&backups[0], schedule)).To(BeTrue())
+
+		Expect(schedule.Status.LastBackup).To(Equal(backups[0].Name))
+		Expect(schedule.Status.LastScheduleTime.Time).To(BeTemporally("==", time.Date(2021, 11, 2, 3, 0, 0, 0, time.UTC)))
+		Expect(result.RequeueAfter).To(Equal(23*time.Hour + 30*time.Minute))
+
+		// the same scheduled time does not create another backup
+		reconcileSchedule()
+		Expect(listBackups()).To(HaveLen(1))
+	})
+
+	It("skips the backups while the previous one is running", func() {
+		now = created.Add(17 * time.Hour)
+		reconcileSchedule()
+
+		now = now.Add(24 * time.Hour)
+		reconcileSchedule()
+		Expect(listBackups()).To(HaveLen(1))
+		Expect(schedule.Status.LastScheduleTime.Time).To(BeTemporally("==", time.Date(2021, 11, 3, 3, 0, 0, 0, time.UTC)))
+
+		finishBackup(schedule.Status.LastBackup, wordpressv1alpha1.BackupCompleteCondition)
+
+		now = now.Add(24 * time.Hour)
+		reconcileSchedule()
+		Expect(listBackups()).To(HaveLen(2))
+	})
+
+	It("doesn't create backups while suspended", func() {
+		schedule.Spec.Suspend = true
+		Expect(c.Update(ctx, schedule)).To(Succeed())
+
+		now = created.Add(72 * time.Hour)
+		reconcileSchedule()
+		Expect(listBackups()).To(BeEmpty())
+	})
+
+	It("removes the oldest backups exceeding the history limits", func() {
+		schedule.Spec.Suspend = true
+		Expect(c.Update(ctx, schedule)).To(Succeed())
+
+		for i, name := range []string{"b1", "b2", "b3", "b4", "b5", "b6"} {
+			b := &wordpressv1alpha1.WordpressBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         key.Namespace,
+					Labels:            map[string]string{backup.ScheduleLabel: key.Name},
+					CreationTimestamp: metav1.NewTime(created.Add(time.Duration(i) * time.Hour)),
+				},
+			}
+			Expect(controllerutil.SetControllerReference(schedule, b, r.scheme)).To(Succeed())
+			Expect(c.Create(ctx, b)).To(Succeed())
+		}
+
+		finishBackup("b1", wordpressv1alpha1.BackupCompleteCondition)
+		finishBackup("b2", wordpressv1alpha1.BackupFailedCondition)
+		finishBackup("b3", wordpressv1alpha1.BackupCompleteCondition)
+		finishBackup("b4", wordpressv1alpha1.BackupFailedCondition)
+		finishBackup("b5", wordpressv1alpha1.BackupCompleteCondition)
+
+		reconcileSchedule()
+
+		names := []string{}
+		for _, b := range listBackups() {
+			names = append(names, b.Name)
+		}
+
+		Expect(names).To(ConsistOf("b3", "b4", "b5", "b6"))
+	})
+
+	It("ignores invalid schedules", func() {
+		schedule.Spec.Schedule = "every day"
+		Expect(c.Update(ctx, schedule)).To(Succeed())
+
+		now = created.Add(72 * time.Hour)
+		result := reconcileSchedule()
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(listBackups()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("mostRecentScheduleTime", func() {
+	It("returns the latest missed schedule time", func() {
+		sched, err := cron.ParseStandard("*/15 * * * *")
+		Expect(err).NotTo(HaveOccurred())
+
+		start := time.Date(2021, 11, 1, 10, 0, 0, 0, time.UTC)
+
+		Expect(mostRecentScheduleTime(sched, start, start.Add(10*time.Minute))).To(BeNil())
+		Expect(*mostRecentScheduleTime(sched, start, start.Add(15*time.Minute))).To(Equal(start.Add(15 * time.Minute)))
+		Expect(*mostRecentScheduleTime(sched, start, start.Add(100*time.Minute))).To(Equal(start.Add(90 * time.Minute)))
+	})
+})
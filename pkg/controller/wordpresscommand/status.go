@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpresscommand
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/command"
+)
+
+// updateStatus updates the command status and conditions from the status of
+// the command job and of its pod.
+func (r *ReconcileWordpressCommand) updateStatus(ctx context.Context, cmd *command.Command, job *batchv1.Job) error {
+	if job.Status.StartTime != nil {
+		cmd.Status.StartTime = job.Status.StartTime
+	}
+
+	failed := jobCondition(job, batchv1.JobFailed)
+	complete := jobCondition(job, batchv1.JobComplete)
+
+	if failed == nil && complete == nil {
+		cmd.SetCondition(wordpressv1alpha1.CommandCompleteCondition, corev1.ConditionFalse, wordpressv1alpha1.CommandRunningReason,
+			fmt.Sprintf("job %s is running", job.Name))
+
+		return nil
+	}
+
+	terminated, err := r.terminatedState(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	if terminated != nil {
+		exitCode := terminated.ExitCode
+		cmd.Status.ExitCode = &exitCode
+		cmd.Status.Output = command.OutputTail(terminated.Message)
+	}
+
+	if failed != nil {
+		if !failed.LastTransitionTime.IsZero() {
+			cmd.Status.CompletionTime = &failed.LastTransitionTime
+		}
+
+		r.fail(cmd, wordpressv1alpha1.CommandFailedReason, fmt.Sprintf("job %s failed: %s", job.Name, failed.Message))
+
+		return nil
+	}
+
+	cmd.Status.CompletionTime = job.Status.CompletionTime
+
+	msg := fmt.Sprintf("command %s completed", cmd.Name)
+	cmd.SetCondition(wordpressv1alpha1.CommandFailedCondition, corev1.ConditionFalse, wordpressv1alpha1.CommandSucceededReason, "")
+
+	if cmd.SetCondition(wordpressv1alpha1.CommandCompleteCondition, corev1.ConditionTrue, wordpressv1alpha1.CommandSucceededReason, msg) {
+		r.recorder.Event(cmd.Unwrap(), corev1.EventTypeNormal, wordpressv1alpha1.CommandSucceededReason, msg)
+	}
+
+	return nil
+}
+
+// terminatedState returns the state of the last terminated command container
+// of the job pods, or nil if the command didn't run, eg. when the job
+// deadline was exceeded before the pod started.
+func (r *ReconcileWordpressCommand) terminatedState(ctx context.Context, job *batchv1.Job) (*corev1.ContainerStateTerminated, error) {
+	pods := &corev1.PodList{}
+	if err := r.apiReader.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	var last *corev1.ContainerStateTerminated
+
+	for i := range pods.Items {
+		for _, status := range pods.Items[i].Status.ContainerStatuses {
+			state := status.State.Terminated
+			if status.Name != command.ContainerName || state == nil {
+				continue
+			}
+
+			if last == nil || last.FinishedAt.Before(&state.FinishedAt) {
+				last = state
+			}
+		}
+	}
+
+	return last, nil
+}
+
+func jobCondition(job *batchv1.Job, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == condType && job.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
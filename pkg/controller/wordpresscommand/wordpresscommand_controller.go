@@ -0,0 +1,222 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpresscommand
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/presslabs/controller-util/syncer"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpresscommand/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/command"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const controllerName = "wordpresscommand-controller"
+
+// Add creates a new WordpressCommand Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileWordpressCommand{
+		Client:    mgr.GetClient(),
+		apiReader: mgr.GetAPIReader(),
+		scheme:    mgr.GetScheme(),
+		recorder:  mgr.GetEventRecorderFor(controllerName),
+		now:       time.Now,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler.
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to WordpressCommand
+	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.WordpressCommand{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the command jobs
+	return c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &wordpressv1alpha1.WordpressCommand{},
+	})
+}
+
+var _ reconcile.Reconciler = &ReconcileWordpressCommand{}
+
+// ReconcileWordpressCommand reconciles a WordpressCommand object.
+type ReconcileWordpressCommand struct {
+	client.Client
+	// apiReader reads objects which are not cached, like pods
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
+	now       func() time.Time
+}
+
+// Automatically generate RBAC rules to allow the Controller to run command jobs
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpresscommands;wordpresscommands/status,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs the job of a WordpressCommand, records its exit code and
+// output in the WordpressCommand status and deletes finished commands once
+// their TTL expires.
+func (r *ReconcileWordpressCommand) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cmd := command.New(&wordpressv1alpha1.WordpressCommand{})
+
+	err := r.Get(ctx, request.NamespacedName, cmd.Unwrap())
+	if err != nil {
+		return reconcile.Result{}, ignoreNotFound(err)
+	}
+
+	if !cmd.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	oldStatus := cmd.Status.DeepCopy()
+
+	if !cmd.IsFinished() {
+		if err = r.runCommand(ctx, cmd); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(oldStatus, &cmd.Status) {
+		if err = r.Status().Update(ctx, cmd.Unwrap()); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if cmd.IsFinished() {
+		return r.expire(ctx, cmd)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// runCommand creates the command job, if it doesn't exist, and updates the
+// command status from it.
+func (r *ReconcileWordpressCommand) runCommand(ctx context.Context, cmd *command.Command) error {
+	job := &batchv1.Job{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: cmd.JobName(), Namespace: cmd.Namespace}, job)
+	if errors.IsNotFound(err) {
+		job, err = r.createJob(ctx, cmd)
+	}
+
+	if err != nil || job == nil {
+		return err
+	}
+
+	return r.updateStatus(ctx, cmd, job)
+}
+
+// createJob creates the command job for the referenced site. It returns a nil
+// job if the site does not exist, in which case the command is marked as failed.
+func (r *ReconcileWordpressCommand) createJob(ctx context.Context, cmd *command.Command) (*batchv1.Job, error) {
+	wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+
+	err := r.Get(ctx, types.NamespacedName{Name: cmd.Spec.WordpressRef.Name, Namespace: cmd.Namespace}, wp.Unwrap())
+	if errors.IsNotFound(err) {
+		r.fail(cmd, wordpressv1alpha1.WordpressNotFoundReason, fmt.Sprintf("wordpress %s not found", cmd.Spec.WordpressRef.Name))
+
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.scheme.Default(wp.Unwrap())
+	wp.SetDefaults()
+
+	jobSyncer := sync.NewJobSyncer(cmd, wp, r.Client)
+	if err = syncer.Sync(ctx, jobSyncer, r.recorder); err != nil {
+		return nil, err
+	}
+
+	return jobSyncer.Object().(*batchv1.Job), nil
+}
+
+// expire deletes the command once its TTL expires and requeues it until then.
+func (r *ReconcileWordpressCommand) expire(ctx context.Context, cmd *command.Command) (reconcile.Result, error) {
+	if cmd.Spec.TTLSecondsAfterFinished == nil || cmd.Status.CompletionTime == nil {
+		return reconcile.Result{}, nil
+	}
+
+	ttl := time.Duration(*cmd.Spec.TTLSecondsAfterFinished) * time.Second
+	if left := cmd.Status.CompletionTime.Add(ttl).Sub(r.now()); left > 0 {
+		return reconcile.Result{RequeueAfter: left}, nil
+	}
+
+	// the job and its pods are garbage collected along with the command
+	err := r.Delete(ctx, cmd.Unwrap(), client.PropagationPolicy(metav1.DeletePropagationBackground))
+
+	return reconcile.Result{}, ignoreNotFound(err)
+}
+
+// fail marks the command as failed.
+func (r *ReconcileWordpressCommand) fail(cmd *command.Command, reason, msg string) {
+	if cmd.Status.CompletionTime == nil {
+		now := metav1.NewTime(r.now())
+		cmd.Status.CompletionTime = &now
+	}
+
+	cmd.SetCondition(wordpressv1alpha1.CommandCompleteCondition, corev1.ConditionFalse, reason, msg)
+
+	if cmd.SetCondition(wordpressv1alpha1.CommandFailedCondition, corev1.ConditionTrue, reason, msg) {
+		r.recorder.Event(cmd.Unwrap(), corev1.EventTypeWarning, reason, msg)
+	}
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpresscommand
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/command"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+)
+
+var _ = Describe("WordpressCommand controller", func() {
+	var (
+		r   *ReconcileWordpressCommand
+		c   client.Client
+		ctx context.Context
+		key types.NamespacedName
+		obj *wordpressv1alpha1.WordpressCommand
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "flush", Namespace: "default"}
+		now = time.Now().Truncate(time.Second)
+
+		obj = &wordpressv1alpha1.WordpressCommand{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: wordpressv1alpha1.WordpressCommandSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: "site"},
+				Command:      []string{"wp", "cache", "flush"},
+			},
+		}
+
+		wp := &wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: key.Namespace},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+			},
+		}
+
+		c = testutil.NewFakeClient(obj, wp)
+		r = &ReconcileWordpressCommand{
+			Client:    c,
+			apiReader: c,
+			scheme:    c.Scheme(),
+			recorder:  record.NewFakeRecorder(100),
+			now:       func() time.Time { return now },
+		}
+	})
+
+	reconcileCommand := func() (*command.Command, reconcile.Result) {
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		cmd := command.New(&wordpressv1alpha1.WordpressCommand{})
+		Expect(c.Get(ctx, key, cmd.Unwrap())).To(Succeed())
+
+		return cmd, result
+	}
+
+	finishJob := func(condType batchv1.JobConditionType, exitCode int32, output string) {
+		job := &batchv1.Job{}
+		Expect(c.Get(ctx, key, job)).To(Succeed())
+
+		completion := metav1.NewTime(now.Add(-time.Minute))
+		job.Status.StartTime = &metav1.Time{Time: completion.Add(-time.Minute)}
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: condType, Status: corev1.ConditionTrue, LastTransitionTime: completion, Message: "done"},
+		}
+
+		if condType == batchv1.JobComplete {
+			job.Status.CompletionTime = &completion
+		}
+
+		Expect(c.Status().Update(ctx, job)).To(Succeed())
+
+		Expect(c.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "flush-abcde",
+				Namespace: key.Namespace,
+				Labels:    map[string]string{"job-name": job.Name},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: command.ContainerName,
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: output},
+						},
+					},
+				},
+			},
+		})).To(Succeed())
+	}
+
+	It("runs the command job", func() {
+		cmd, _ := reconcileCommand()
+
+		job := &batchv1.Job{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: cmd.JobName(), Namespace: key.Namespace}, job)).To(Succeed())
+		Expect(*job.Spec.BackoffLimit).To(BeEquivalentTo(0))
+		Expect(job.Spec.Template.Spec.Containers[0].Args[4:]).To(Equal([]string{"wp", "cache", "flush"}))
+
+		Expect(cmd.GetCondition(wordpressv1alpha1.CommandCompleteCondition).Reason).To(Equal(wordpressv1alpha1.CommandRunningReason))
+		Expect(cmd.IsFinished()).To(BeFalse())
+	})
+
+	It("records the exit code and output of completed commands", func() {
+		reconcileCommand()
+		finishJob(batchv1.JobComplete, 0, "Success: The cache was flushed.\n")
+
+		cmd, result := reconcileCommand()
+		Expect(cmd.IsComplete()).To(BeTrue())
+		Expect(*cmd.Status.ExitCode).To(BeEquivalentTo(0))
+		Expect(cmd.Status.Output).To(Equal("Success: The cache was flushed.\n"))
+		Expect(cmd.Status.CompletionTime).NotTo(BeNil())
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+
+	It("records the exit code and output of failed commands", func() {
+		reconcileCommand()
+		finishJob(batchv1.JobFailed, 1, "Error: 'flsh' is not a registered subcommand of 'cache'.\n")
+
+		cmd, _ := reconcileCommand()
+		Expect(cmd.IsFailed()).To(BeTrue())
+		Expect(cmd.IsComplete()).To(BeFalse())
+		Expect(*cmd.Status.ExitCode).To(BeEquivalentTo(1))
+		Expect(cmd.Status.Output).To(ContainSubstring("not a registered subcommand"))
+	})
+
+	It("fails commands for missing sites", func() {
+		obj.Spec.WordpressRef.Name = "missing"
+		Expect(c.Update(ctx, obj)).To(Succeed())
+
+		cmd, _ := reconcileCommand()
+		Expect(cmd.IsFailed()).To(BeTrue())
+		Expect(cmd.GetCondition(wordpressv1alpha1.CommandFailedCondition).Reason).To(Equal(wordpressv1alpha1.WordpressNotFoundReason))
+		Expect(cmd.Status.ExitCode).To(BeNil())
+	})
+
+	It("deletes finished commands once their TTL expires", func() {
+		ttl := int32(300)
+		obj.Spec.TTLSecondsAfterFinished = &ttl
+		Expect(c.Update(ctx, obj)).To(Succeed())
+
+		reconcileCommand()
+		finishJob(batchv1.JobComplete, 0, "")
+
+		_, result := reconcileCommand()
+		Expect(result.RequeueAfter).To(Equal(4 * time.Minute))
+
+		now = now.Add(4 * time.Minute)
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, key, &wordpressv1alpha1.WordpressCommand{})).NotTo(Succeed())
+	})
+})
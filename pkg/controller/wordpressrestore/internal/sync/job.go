@@ -0,0 +1,61 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"github.com/appscode/mergo"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/presslabs/controller-util/mergo/transformers"
+	"github.com/presslabs/controller-util/syncer"
+
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/restore"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// NewJobSyncer returns a new sync.Interface for reconciling the Job which
+// restores the site.
+func NewJobSyncer(rs *restore.Restore, wp *wordpress.Wordpress, artifacts *backup.Artifacts, c client.Client) syncer.Interface {
+	obj := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rs.JobName(),
+			Namespace: rs.Namespace,
+		},
+	}
+
+	var backoffLimit int32
+
+	return syncer.NewObjectSyncer("RestoreJob", rs.Unwrap(), obj, c, func() error {
+		obj.Labels = labels.Merge(labels.Merge(obj.Labels, rs.Labels()), controllerLabels)
+
+		// the job spec is immutable and a restore is run only once
+		if !obj.CreationTimestamp.IsZero() {
+			return nil
+		}
+
+		obj.Spec.BackoffLimit = &backoffLimit
+
+		template := rs.JobPodTemplateSpec(wp, artifacts)
+		obj.Spec.Template.ObjectMeta = template.ObjectMeta
+
+		return mergo.Merge(&obj.Spec.Template.Spec, template.Spec, mergo.WithTransformers(transformers.PodSpec))
+	})
+}
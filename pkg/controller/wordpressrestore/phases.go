@@ -0,0 +1,335 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressrestore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/presslabs/controller-util/syncer"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/controller/wordpressrestore/internal/sync"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/restore"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// step runs the current phase of the restore. Phases which don't need to wait
+// for other objects fall through to the next one.
+func (r *ReconcileWordpressRestore) step(ctx context.Context, rs *restore.Restore) (reconcile.Result, error) {
+	wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+
+	err := r.Get(ctx, types.NamespacedName{Name: rs.Spec.WordpressRef.Name, Namespace: rs.Namespace}, wp.Unwrap())
+	if errors.IsNotFound(err) {
+		r.fail(rs, wordpressv1alpha1.WordpressNotFoundReason, fmt.Sprintf("wordpress %s not found", rs.Spec.WordpressRef.Name))
+		r.finish(rs)
+
+		return reconcile.Result{}, nil
+	}
+
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	r.scheme.Default(wp.Unwrap())
+	wp.SetDefaults()
+
+	switch rs.Status.Phase {
+	case "", wordpressv1alpha1.RestorePending:
+		return r.start(ctx, rs, wp)
+	case wordpressv1alpha1.RestoreScalingDown:
+		return reconcile.Result{}, r.scaleDown(ctx, rs, wp)
+	case wordpressv1alpha1.RestoreRestoring:
+		return reconcile.Result{}, r.runJob(ctx, rs, wp)
+	case wordpressv1alpha1.RestoreScalingUp:
+		return reconcile.Result{}, r.scaleUp(ctx, rs, wp)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// start stops the site's web pods, once the restored backup is complete and
+// no other restore of the site is in progress.
+func (r *ReconcileWordpressRestore) start(ctx context.Context, rs *restore.Restore, wp *wordpress.Wordpress) (reconcile.Result, error) {
+	artifacts, wait, err := r.artifacts(ctx, rs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if artifacts == nil {
+		r.finish(rs)
+
+		return reconcile.Result{}, nil
+	}
+
+	if wait == "" {
+		if holder := wp.Annotations[wordpress.RestoreAnnotation]; holder != "" && holder != rs.Name {
+			wait = fmt.Sprintf("waiting for restore %s of %s to finish", holder, wp.Name)
+		}
+	}
+
+	if wait != "" {
+		rs.Status.Phase = wordpressv1alpha1.RestorePending
+		rs.SetCondition(wordpressv1alpha1.RestoreCompleteCondition, corev1.ConditionFalse, wordpressv1alpha1.RestoreInProgressReason, wait)
+
+		return reconcile.Result{RequeueAfter: pendingRequeueInterval}, nil
+	}
+
+	if wp.Annotations[wordpress.RestoreAnnotation] != rs.Name {
+		if err = r.annotateSite(ctx, wp, rs.Name); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	now := metav1.Now()
+	rs.Status.StartTime = &now
+	rs.Status.Location = artifacts.Location()
+	r.setPhase(rs, wordpressv1alpha1.RestoreScalingDown, fmt.Sprintf("stopping the web pods of %s", wp.Name))
+
+	return reconcile.Result{}, r.scaleDown(ctx, rs, wp)
+}
+
+// scaleDown waits for the site's web pods to stop.
+func (r *ReconcileWordpressRestore) scaleDown(ctx context.Context, rs *restore.Restore, wp *wordpress.Wordpress) error {
+	deploy := &appsv1.Deployment{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: wp.ComponentName(wordpress.WordpressDeployment), Namespace: wp.Namespace}, deploy)
+	if ignoreNotFound(err) != nil {
+		return err
+	}
+
+	if err == nil && (deploy.Spec.Replicas == nil || *deploy.Spec.Replicas > 0 || deploy.Status.Replicas > 0) {
+		return nil
+	}
+
+	r.setPhase(rs, wordpressv1alpha1.RestoreRestoring, fmt.Sprintf("running job %s", rs.JobName()))
+
+	return r.runJob(ctx, rs, wp)
+}
+
+// runJob runs the restore job and waits for it to finish.
+func (r *ReconcileWordpressRestore) runJob(ctx context.Context, rs *restore.Restore, wp *wordpress.Wordpress) error {
+	job := &batchv1.Job{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: rs.JobName(), Namespace: rs.Namespace}, job)
+	if errors.IsNotFound(err) {
+		job, err = r.createJob(ctx, rs, wp)
+	}
+
+	if err != nil || job == nil {
+		return err
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		if cond.Type == batchv1.JobFailed {
+			r.fail(rs, wordpressv1alpha1.RestoreFailedReason, fmt.Sprintf("job %s failed: %s", job.Name, cond.Message))
+
+			return r.scaleUp(ctx, rs, wp)
+		}
+
+		if cond.Type == batchv1.JobComplete {
+			r.setPhase(rs, wordpressv1alpha1.RestoreScalingUp, fmt.Sprintf("starting the web pods of %s", wp.Name))
+
+			return r.scaleUp(ctx, rs, wp)
+		}
+	}
+
+	return nil
+}
+
+// createJob creates the restore job. It returns a nil job if the restored
+// artifacts can no longer be found, in which case the restore is failed.
+func (r *ReconcileWordpressRestore) createJob(ctx context.Context, rs *restore.Restore, wp *wordpress.Wordpress) (*batchv1.Job, error) {
+	artifacts, wait, err := r.artifacts(ctx, rs)
+	if err != nil {
+		return nil, err
+	}
+
+	if wait != "" {
+		r.fail(rs, wordpressv1alpha1.BackupNotCompleteReason, wait)
+	}
+
+	if artifacts == nil || wait != "" {
+		return nil, r.scaleUp(ctx, rs, wp)
+	}
+
+	jobSyncer := sync.NewJobSyncer(rs, wp, artifacts, r.Client)
+	if err = syncer.Sync(ctx, jobSyncer, r.recorder); err != nil {
+		return nil, err
+	}
+
+	return jobSyncer.Object().(*batchv1.Job), nil
+}
+
+// scaleUp starts the site's web pods again and waits for the web deployment
+// to be scaled back.
+func (r *ReconcileWordpressRestore) scaleUp(ctx context.Context, rs *restore.Restore, wp *wordpress.Wordpress) error {
+	if err := r.releaseSite(ctx, rs, wp); err != nil {
+		return err
+	}
+
+	deploy := &appsv1.Deployment{}
+
+	err := r.Get(ctx, types.NamespacedName{Name: wp.ComponentName(wordpress.WordpressDeployment), Namespace: wp.Namespace}, deploy)
+	if ignoreNotFound(err) != nil {
+		return err
+	}
+
+	if err == nil && deploy.Annotations[wordpress.ReplicasBeforeRestoreAnnotation] != "" {
+		return nil
+	}
+
+	r.finish(rs)
+
+	return nil
+}
+
+// releaseSite removes the restore annotation from the site, if it's held by
+// the given restore.
+func (r *ReconcileWordpressRestore) releaseSite(ctx context.Context, rs *restore.Restore, wp *wordpress.Wordpress) error {
+	if wp.Annotations[wordpress.RestoreAnnotation] != rs.Name {
+		return nil
+	}
+
+	return r.annotateSite(ctx, wp, "")
+}
+
+// annotateSite sets the restore annotation of the site, or removes it if the
+// holder is empty. The site is patched from a copy holding only its metadata,
+// so that the defaults set on wp are not written into its spec. The patch
+// fails with a conflict if the site changed since it was read, as the holder
+// may have been read from a stale cache.
+func (r *ReconcileWordpressRestore) annotateSite(ctx context.Context, wp *wordpress.Wordpress, holder string) error {
+	obj := &wordpressv1alpha1.Wordpress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            wp.Name,
+			Namespace:       wp.Namespace,
+			ResourceVersion: wp.ResourceVersion,
+			Annotations:     map[string]string{},
+		},
+	}
+
+	for k, v := range wp.Annotations {
+		obj.Annotations[k] = v
+	}
+
+	patch := client.MergeFromWithOptions(obj.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+	if holder == "" {
+		delete(obj.Annotations, wordpress.RestoreAnnotation)
+	} else {
+		obj.Annotations[wordpress.RestoreAnnotation] = holder
+	}
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		return err
+	}
+
+	wp.Annotations = obj.Annotations
+
+	return nil
+}
+
+// artifacts returns the artifacts to restore. A non empty message is returned
+// if the restored backup is not complete yet. The artifacts are nil if the
+// source is invalid, in which case the restore is marked as failed.
+func (r *ReconcileWordpressRestore) artifacts(ctx context.Context, rs *restore.Restore) (*backup.Artifacts, string, error) {
+	src := rs.Spec.Source
+
+	switch {
+	case src.BackupRef != nil && src.Destination == nil:
+		b := backup.New(&wordpressv1alpha1.WordpressBackup{})
+
+		err := r.Get(ctx, types.NamespacedName{Name: src.BackupRef.Name, Namespace: rs.Namespace}, b.Unwrap())
+		if errors.IsNotFound(err) {
+			r.fail(rs, wordpressv1alpha1.InvalidSourceReason, fmt.Sprintf("backup %s not found", src.BackupRef.Name))
+
+			return nil, "", nil
+		}
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		if b.IsFailed() {
+			r.fail(rs, wordpressv1alpha1.BackupNotCompleteReason, fmt.Sprintf("backup %s failed", b.Name))
+
+			return nil, "", nil
+		}
+
+		if !b.IsComplete() {
+			return b.Artifacts(), fmt.Sprintf("waiting for backup %s to complete", b.Name), nil
+		}
+
+		return b.Artifacts(), "", nil
+	case src.Destination != nil && src.BackupRef == nil && src.Path != "":
+		return backup.NewArtifacts(src.Destination, src.Path), "", nil
+	}
+
+	r.fail(rs, wordpressv1alpha1.InvalidSourceReason, "either a backup or a destination and a path must be specified as source")
+
+	return nil, "", nil
+}
+
+func (r *ReconcileWordpressRestore) setPhase(rs *restore.Restore, phase wordpressv1alpha1.WordpressRestorePhase, msg string) {
+	rs.Status.Phase = phase
+	rs.SetCondition(wordpressv1alpha1.RestoreCompleteCondition, corev1.ConditionFalse, wordpressv1alpha1.RestoreInProgressReason, msg)
+}
+
+// fail marks the restore as failed. The site still has to be released, before
+// the restore is finished.
+func (r *ReconcileWordpressRestore) fail(rs *restore.Restore, reason, msg string) {
+	rs.Status.Phase = wordpressv1alpha1.RestoreScalingUp
+	rs.SetCondition(wordpressv1alpha1.RestoreCompleteCondition, corev1.ConditionFalse, reason, msg)
+
+	if rs.SetCondition(wordpressv1alpha1.RestoreFailedCondition, corev1.ConditionTrue, reason, msg) {
+		r.recorder.Event(rs.Unwrap(), corev1.EventTypeWarning, reason, msg)
+	}
+}
+
+// finish moves the restore to its final phase.
+func (r *ReconcileWordpressRestore) finish(rs *restore.Restore) {
+	now := metav1.Now()
+	rs.Status.CompletionTime = &now
+
+	if rs.IsFailed() {
+		rs.Status.Phase = wordpressv1alpha1.RestoreFailed
+
+		return
+	}
+
+	msg := fmt.Sprintf("%s restored from %s", rs.Spec.WordpressRef.Name, rs.Status.Location)
+	rs.Status.Phase = wordpressv1alpha1.RestoreCompleted
+	rs.SetCondition(wordpressv1alpha1.RestoreFailedCondition, corev1.ConditionFalse, wordpressv1alpha1.RestoreSucceededReason, "")
+
+	if rs.SetCondition(wordpressv1alpha1.RestoreCompleteCondition, corev1.ConditionTrue, wordpressv1alpha1.RestoreSucceededReason, msg) {
+		r.recorder.Event(rs.Unwrap(), corev1.EventTypeNormal, wordpressv1alpha1.RestoreSucceededReason, msg)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressrestore
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/restore"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	controllerName = "wordpressrestore-controller"
+
+	// pendingRequeueInterval is the interval at which pending restores
+	// re-check the backup they wait for.
+	pendingRequeueInterval = 30 * time.Second
+)
+
+// Add creates a new WordpressRestore Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileWordpressRestore{
+		Client:   mgr.GetClient(),
+		scheme:   mgr.GetScheme(),
+		recorder: mgr.GetEventRecorderFor(controllerName),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler.
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to WordpressRestore
+	err = c.Watch(&source.Kind{Type: &wordpressv1alpha1.WordpressRestore{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the restore jobs
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &wordpressv1alpha1.WordpressRestore{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the web deployments which are scaled for restores
+	return c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(restoresForDeployment(mgr.GetClient())))
+}
+
+// restoresForDeployment maps a web deployment to the restores of its site
+// which are in progress.
+func restoresForDeployment(c client.Client) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		site := obj.GetLabels()["app.kubernetes.io/instance"]
+		if site == "" {
+			return nil
+		}
+
+		restores := &wordpressv1alpha1.WordpressRestoreList{}
+		if err := c.List(context.TODO(), restores, client.InNamespace(obj.GetNamespace())); err != nil {
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+
+		for i := range restores.Items {
+			rs := restore.New(&restores.Items[i])
+			if rs.Spec.WordpressRef.Name == site && !rs.IsFinished() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: rs.Name, Namespace: rs.Namespace},
+				})
+			}
+		}
+
+		return requests
+	}
+}
+
+var _ reconcile.Reconciler = &ReconcileWordpressRestore{}
+
+// ReconcileWordpressRestore reconciles a WordpressRestore object.
+type ReconcileWordpressRestore struct {
+	client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+// Automatically generate RBAC rules to allow the Controller to restore sites
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpresses,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressbackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressrestores;wordpressrestores/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=wordpress.presslabs.org,resources=wordpressrestores/finalizers,verbs=update
+
+// Reconcile moves a WordpressRestore through its phases: the site's web pods
+// are stopped, the restore job is run and the web pods are started again.
+func (r *ReconcileWordpressRestore) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	rs := restore.New(&wordpressv1alpha1.WordpressRestore{})
+
+	err := r.Get(ctx, request.NamespacedName, rs.Unwrap())
+	if err != nil {
+		return reconcile.Result{}, ignoreNotFound(err)
+	}
+
+	if !rs.DeletionTimestamp.IsZero() {
+		return requeueOnConflict(reconcile.Result{}, r.finalize(ctx, rs))
+	}
+
+	if rs.IsFinished() {
+		return reconcile.Result{}, r.removeFinalizer(ctx, rs)
+	}
+
+	if !controllerutil.ContainsFinalizer(rs.Unwrap(), restore.SiteFinalizer) {
+		controllerutil.AddFinalizer(rs.Unwrap(), restore.SiteFinalizer)
+
+		if err = r.Update(ctx, rs.Unwrap()); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	oldStatus := rs.Status.DeepCopy()
+
+	result, err := r.step(ctx, rs)
+	if err != nil {
+		return requeueOnConflict(reconcile.Result{}, err)
+	}
+
+	if !equality.Semantic.DeepEqual(oldStatus, &rs.Status) {
+		if err = r.Status().Update(ctx, rs.Unwrap()); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if rs.IsFinished() {
+		return result, r.removeFinalizer(ctx, rs)
+	}
+
+	return result, nil
+}
+
+// finalize starts the site again if the restore is deleted while in progress.
+func (r *ReconcileWordpressRestore) finalize(ctx context.Context, rs *restore.Restore) error {
+	wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+
+	err := r.Get(ctx, types.NamespacedName{Name: rs.Spec.WordpressRef.Name, Namespace: rs.Namespace}, wp.Unwrap())
+	if ignoreNotFound(err) != nil {
+		return err
+	}
+
+	if err == nil {
+		if err = r.releaseSite(ctx, rs, wp); err != nil {
+			return err
+		}
+	}
+
+	return r.removeFinalizer(ctx, rs)
+}
+
+func (r *ReconcileWordpressRestore) removeFinalizer(ctx context.Context, rs *restore.Restore) error {
+	if !controllerutil.ContainsFinalizer(rs.Unwrap(), restore.SiteFinalizer) {
+		return nil
+	}
+
+	controllerutil.RemoveFinalizer(rs.Unwrap(), restore.SiteFinalizer)
+
+	return r.Update(ctx, rs.Unwrap())
+}
+
+// requeueOnConflict requeues the request right away if the site's restore
+// annotation was changed by someone else since it was read.
+func requeueOnConflict(result reconcile.Result, err error) (reconcile.Result, error) {
+	if errors.IsConflict(err) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	return result, err
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
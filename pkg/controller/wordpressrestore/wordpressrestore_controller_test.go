@@ -0,0 +1,312 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpressrestore
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/restore"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("WordpressRestore controller", func() {
+	var (
+		r      *ReconcileWordpressRestore
+		c      client.Client
+		ctx    context.Context
+		key    types.NamespacedName
+		wpKey  types.NamespacedName
+		obj    *wordpressv1alpha1.WordpressRestore
+		bk     *wordpressv1alpha1.WordpressBackup
+		deploy *appsv1.Deployment
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "rollback", Namespace: "default"}
+		wpKey = types.NamespacedName{Name: "site", Namespace: key.Namespace}
+
+		obj = &wordpressv1alpha1.WordpressRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: wordpressv1alpha1.WordpressRestoreSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: wpKey.Name},
+				Source: wordpressv1alpha1.RestoreSource{
+					BackupRef: &corev1.LocalObjectReference{Name: "daily"},
+				},
+			},
+		}
+
+		bk = &wordpressv1alpha1.WordpressBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: key.Namespace},
+			Spec: wordpressv1alpha1.WordpressBackupSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: wpKey.Name},
+				Destination: wordpressv1alpha1.BackupDestination{
+					S3: &wordpressv1alpha1.S3VolumeSource{Bucket: "backups"},
+				},
+			},
+			Status: wordpressv1alpha1.WordpressBackupStatus{
+				Conditions: []wordpressv1alpha1.WordpressCondition{
+					{Type: wordpressv1alpha1.BackupCompleteCondition, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+
+		wp := &wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: wpKey.Name, Namespace: wpKey.Namespace},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+			},
+		}
+
+		replicas := int32(2)
+		deploy = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: wpKey.Name, Namespace: wpKey.Namespace},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{Replicas: replicas},
+		}
+
+		c = testutil.NewFakeClient(obj, bk, wp, deploy)
+		r = &ReconcileWordpressRestore{
+			Client:   c,
+			scheme:   c.Scheme(),
+			recorder: record.NewFakeRecorder(100),
+		}
+	})
+
+	reconcileRestore := func() *restore.Restore {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		rs := restore.New(&wordpressv1alpha1.WordpressRestore{})
+		Expect(c.Get(ctx, key, rs.Unwrap())).To(Succeed())
+
+		return rs
+	}
+
+	getWordpress := func() *wordpressv1alpha1.Wordpress {
+		wp := &wordpressv1alpha1.Wordpress{}
+		Expect(c.Get(ctx, wpKey, wp)).To(Succeed())
+
+		return wp
+	}
+
+	// scaleDeployment mimics the wordpress controller scaling the web
+	// deployment for the restore.
+	scaleDeployment := func(replicas int32, restoring bool) {
+		Expect(c.Get(ctx, wpKey, deploy)).To(Succeed())
+
+		deploy.Spec.Replicas = &replicas
+		deploy.Status.Replicas = replicas
+		deploy.Annotations = nil
+
+		if restoring {
+			deploy.Annotations = map[string]string{wordpress.ReplicasBeforeRestoreAnnotation: "2"}
+		}
+
+		Expect(c.Update(ctx, deploy)).To(Succeed())
+	}
+
+	setJobCondition := func(condType batchv1.JobConditionType) {
+		job := &batchv1.Job{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: key.Name + "-restore", Namespace: key.Namespace}, job)).To(Succeed())
+
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condType, Status: corev1.ConditionTrue, Message: "done"}}
+		Expect(c.Status().Update(ctx, job)).To(Succeed())
+	}
+
+	It("restores the site from a backup", func() {
+		spec := getWordpress().Spec
+
+		rs := reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreScalingDown))
+		Expect(rs.Status.Location).To(Equal("s3://backups/default/site/daily"))
+		Expect(rs.Status.StartTime).NotTo(BeNil())
+		Expect(rs.Finalizers).To(ConsistOf(restore.SiteFinalizer))
+		Expect(getWordpress().Annotations).To(HaveKeyWithValue(wordpress.RestoreAnnotation, key.Name))
+
+		scaleDeployment(0, true)
+
+		rs = reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreRestoring))
+
+		job := &batchv1.Job{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: rs.JobName(), Namespace: key.Namespace}, job)).To(Succeed())
+		Expect(*job.Spec.BackoffLimit).To(BeEquivalentTo(0))
+		Expect(job.Spec.Template.Spec.InitContainers).To(ContainElement(MatchFields(IgnoreExtras, Fields{"Name": Equal("download")})))
+
+		setJobCondition(batchv1.JobComplete)
+
+		rs = reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreScalingUp))
+		Expect(getWordpress().Annotations).NotTo(HaveKey(wordpress.RestoreAnnotation))
+
+		scaleDeployment(2, false)
+
+		rs = reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreCompleted))
+		Expect(rs.Status.CompletionTime).NotTo(BeNil())
+		Expect(rs.GetCondition(wordpressv1alpha1.RestoreCompleteCondition).Status).To(Equal(corev1.ConditionTrue))
+		Expect(rs.Finalizers).To(BeEmpty())
+		Expect(getWordpress().Spec).To(Equal(spec))
+	})
+
+	It("waits for the backup to complete", func() {
+		bk.Status.Conditions = nil
+		Expect(c.Status().Update(ctx, bk)).To(Succeed())
+
+		result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(pendingRequeueInterval))
+
+		rs := restore.New(&wordpressv1alpha1.WordpressRestore{})
+		Expect(c.Get(ctx, key, rs.Unwrap())).To(Succeed())
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestorePending))
+		Expect(getWordpress().Annotations).NotTo(HaveKey(wordpress.RestoreAnnotation))
+	})
+
+	It("waits for other restores of the site to finish", func() {
+		wp := getWordpress()
+		wp.Annotations = map[string]string{wordpress.RestoreAnnotation: "other"}
+		Expect(c.Update(ctx, wp)).To(Succeed())
+
+		rs := reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestorePending))
+		Expect(getWordpress().Annotations).To(HaveKeyWithValue(wordpress.RestoreAnnotation, "other"))
+	})
+
+	It("doesn't let restores racing for the site take over its lock", func() {
+		stale := getWordpress()
+
+		other := obj.DeepCopy()
+		other.ObjectMeta = metav1.ObjectMeta{Name: "other", Namespace: key.Namespace}
+		Expect(c.Create(ctx, other)).To(Succeed())
+
+		reconcileRestore()
+		Expect(getWordpress().Annotations).To(HaveKeyWithValue(wordpress.RestoreAnnotation, key.Name))
+
+		// the other restore reads the site from a stale cache
+		racing := &ReconcileWordpressRestore{
+			Client:   &staleSiteClient{Client: c, site: stale},
+			scheme:   c.Scheme(),
+			recorder: record.NewFakeRecorder(100),
+		}
+
+		otherKey := types.NamespacedName{Name: other.Name, Namespace: other.Namespace}
+		result, err := racing.Reconcile(ctx, reconcile.Request{NamespacedName: otherKey})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(getWordpress().Annotations).To(HaveKeyWithValue(wordpress.RestoreAnnotation, key.Name))
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: otherKey})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, otherKey, other)).To(Succeed())
+		Expect(other.Status.Phase).To(Equal(wordpressv1alpha1.RestorePending))
+
+		// the restore releases the site only if it's up to date
+		racing.Client = &staleSiteClient{Client: c, site: getWordpress()}
+		wp := getWordpress()
+		wp.Labels = map[string]string{"changed": "true"}
+		Expect(c.Update(ctx, wp)).To(Succeed())
+
+		rs := restore.New(&wordpressv1alpha1.WordpressRestore{})
+		Expect(c.Get(ctx, key, rs.Unwrap())).To(Succeed())
+		Expect(c.Delete(ctx, rs.Unwrap())).To(Succeed())
+
+		result, err = racing.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(getWordpress().Annotations).To(HaveKeyWithValue(wordpress.RestoreAnnotation, key.Name))
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getWordpress().Annotations).NotTo(HaveKey(wordpress.RestoreAnnotation))
+	})
+
+	It("fails restores with an invalid source", func() {
+		obj.Spec.Source = wordpressv1alpha1.RestoreSource{}
+		Expect(c.Update(ctx, obj)).To(Succeed())
+
+		rs := reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreFailed))
+		Expect(rs.GetCondition(wordpressv1alpha1.RestoreFailedCondition).Reason).To(Equal(wordpressv1alpha1.InvalidSourceReason))
+		Expect(getWordpress().Annotations).NotTo(HaveKey(wordpress.RestoreAnnotation))
+	})
+
+	It("starts the site again if the restore job fails", func() {
+		reconcileRestore()
+		scaleDeployment(0, true)
+		reconcileRestore()
+		setJobCondition(batchv1.JobFailed)
+
+		rs := reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreScalingUp))
+		Expect(rs.IsFailed()).To(BeTrue())
+		Expect(getWordpress().Annotations).NotTo(HaveKey(wordpress.RestoreAnnotation))
+
+		scaleDeployment(2, false)
+
+		rs = reconcileRestore()
+		Expect(rs.Status.Phase).To(Equal(wordpressv1alpha1.RestoreFailed))
+	})
+
+	It("releases the site when deleted while in progress", func() {
+		spec := getWordpress().Spec
+
+		rs := reconcileRestore()
+		Expect(getWordpress().Annotations).To(HaveKey(wordpress.RestoreAnnotation))
+		Expect(getWordpress().Spec).To(Equal(spec))
+
+		Expect(c.Delete(ctx, rs.Unwrap())).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(ctx, key, &wordpressv1alpha1.WordpressRestore{})).NotTo(Succeed())
+		Expect(getWordpress().Annotations).NotTo(HaveKey(wordpress.RestoreAnnotation))
+	})
+})
+
+// staleSiteClient returns the given copy of the site, as read from a stale
+// cache.
+type staleSiteClient struct {
+	client.Client
+	site *wordpressv1alpha1.Wordpress
+}
+
+func (c *staleSiteClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if wp, ok := obj.(*wordpressv1alpha1.Wordpress); ok && key.Name == c.site.Name {
+		c.site.DeepCopyInto(wp)
+
+		return nil
+	}
+
+	return c.Client.Get(ctx, key, obj)
+}
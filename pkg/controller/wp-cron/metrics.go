@@ -0,0 +1,112 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "wordpress_operator"
+	metricsSubsystem = "wp_cron"
+
+	// noResponseCode is the code reported for triggers which got no response.
+	noResponseCode = "error"
+)
+
+var (
+	triggerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "trigger_duration_seconds",
+		Help:      "Duration of the wp-cron.php requests.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"namespace", "site"})
+
+	triggersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "triggers_total",
+		Help:      "Total number of wp-cron.php requests, by HTTP status code or \"" + noResponseCode + "\" if no response was received.",
+	}, []string{"namespace", "site", "code"})
+
+	consecutiveFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "consecutive_failures",
+		Help:      "Number of consecutive failed wp-cron.php requests.",
+	}, []string{"namespace", "site"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(triggerDuration, triggersTotal, consecutiveFailures)
+}
+
+// siteMetrics records the wp-cron metrics of sites and keeps track of the
+// status codes reported for each of them, so that all of a site's series can
+// be removed once it no longer needs wp-cron triggering.
+type siteMetrics struct {
+	mu    sync.Mutex
+	codes map[types.NamespacedName]map[string]bool
+}
+
+func newSiteMetrics() *siteMetrics {
+	return &siteMetrics{codes: map[types.NamespacedName]map[string]bool{}}
+}
+
+// observe records a wp-cron.php request which got the given status code, or
+// no response if the code is zero.
+func (m *siteMetrics) observe(key types.NamespacedName, code int, duration time.Duration, failures int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	codeLabel := noResponseCode
+	if code != 0 {
+		codeLabel = strconv.Itoa(code)
+	}
+
+	if m.codes[key] == nil {
+		m.codes[key] = map[string]bool{}
+	}
+
+	m.codes[key][codeLabel] = true
+
+	triggerDuration.WithLabelValues(key.Namespace, key.Name).Observe(duration.Seconds())
+	triggersTotal.WithLabelValues(key.Namespace, key.Name, codeLabel).Inc()
+	consecutiveFailures.WithLabelValues(key.Namespace, key.Name).Set(float64(failures))
+}
+
+// forget removes the series of a site.
+func (m *siteMetrics) forget(key types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for code := range m.codes[key] {
+		triggersTotal.DeleteLabelValues(key.Namespace, key.Name, code)
+	}
+
+	delete(m.codes, key)
+
+	triggerDuration.DeleteLabelValues(key.Namespace, key.Name)
+	consecutiveFailures.DeleteLabelValues(key.Namespace, key.Name)
+}
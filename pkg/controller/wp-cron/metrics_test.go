@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("wp-cron metrics", func() {
+	var (
+		m   *siteMetrics
+		key types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Name: "metrics-site", Namespace: "default"}
+		m = newSiteMetrics()
+	})
+
+	AfterEach(func() {
+		m.forget(key)
+	})
+
+	It("records triggers by status code", func() {
+		m.observe(key, 200, time.Second, 0)
+		m.observe(key, 200, time.Second, 0)
+		m.observe(key, 502, time.Second, 1)
+		m.observe(key, 0, time.Second, 2)
+
+		Expect(testutil.ToFloat64(triggersTotal.WithLabelValues("default", "metrics-site", "200"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(triggersTotal.WithLabelValues("default", "metrics-site", "502"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(triggersTotal.WithLabelValues("default", "metrics-site", noResponseCode))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(consecutiveFailures.WithLabelValues("default", "metrics-site"))).To(Equal(2.0))
+	})
+
+	It("removes the series of forgotten sites", func() {
+		m.observe(key, 200, time.Second, 0)
+		m.observe(key, 0, time.Second, 1)
+		Expect(testutil.CollectAndCount(triggersTotal)).To(Equal(2))
+		Expect(testutil.CollectAndCount(triggerDuration)).To(Equal(1))
+		Expect(testutil.CollectAndCount(consecutiveFailures)).To(Equal(1))
+
+		m.forget(key)
+		Expect(testutil.CollectAndCount(triggersTotal)).To(BeZero())
+		Expect(testutil.CollectAndCount(triggerDuration)).To(BeZero())
+		Expect(testutil.CollectAndCount(consecutiveFailures)).To(BeZero())
+	})
+})
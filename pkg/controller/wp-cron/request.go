@@ -0,0 +1,187 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	doingWPCronParam     = "doing_wp_cron"
+	forwardedProtoHeader = "X-Forwarded-Proto"
+)
+
+var (
+	errInvalidCABundle  = errors.New("invalid CA bundle")
+	errForbiddenHost    = errors.New("host is neither a route domain nor the service of the site")
+	errTooManyRedirects = errors.New("stopped after 10 redirects")
+)
+
+// cronHTTPSpec returns the wp-cron.php requests configuration of a site.
+func cronHTTPSpec(wp *wordpress.Wordpress) *wordpressv1alpha1.CronHTTPSpec {
+	if wp.Spec.Cron == nil || wp.Spec.Cron.HTTP == nil {
+		return &wordpressv1alpha1.CronHTTPSpec{}
+	}
+
+	return wp.Spec.Cron.HTTP
+}
+
+// newCronRequest returns the wp-cron.php request of a site. By default,
+// wp-cron.php is requested over http from the site's service, with the site's
+// main domain as Host header.
+func newCronRequest(ctx context.Context, wp *wordpress.Wordpress) (*http.Request, error) {
+	spec := cronHTTPSpec(wp)
+	host := spec.Host
+
+	u, err := url.Parse(spec.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.URL != "" && !options.WPCronAllowAnyURL && !wp.IsSiteHost(u.Host) {
+		return nil, fmt.Errorf("%w: %s", errForbiddenHost, u.Host)
+	}
+
+	if spec.URL == "" {
+		u, err = url.Parse(wp.SiteURL("wp-cron.php"))
+		if err != nil {
+			return nil, err
+		}
+
+		u.Scheme = "http"
+		u.Host = fmt.Sprintf("%s.%s.svc", wp.Name, wp.Namespace)
+
+		if host == "" {
+			host = wp.MainDomain()
+		}
+	}
+
+	query := u.Query()
+	if !query.Has(doingWPCronParam) {
+		if u.RawQuery != "" {
+			u.RawQuery += "&"
+		}
+
+		u.RawQuery += doingWPCronParam
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if host != "" {
+		req.Host = host
+	}
+
+	if spec.ForwardedProto != "" {
+		req.Header.Set(forwardedProtoHeader, spec.ForwardedProto)
+	}
+
+	return req, nil
+}
+
+// newCronClient returns the HTTP client for the wp-cron.php requests of a
+// site. Over https, the server certificate is verified for the Host header of
+// the request.
+func newCronClient(wp *wordpress.Wordpress, req *http.Request) (*http.Client, error) {
+	spec := cronHTTPSpec(wp)
+	c := &http.Client{}
+
+	switch {
+	case spec.RedirectPolicy == wordpressv1alpha1.CronRedirectAccept || spec.RedirectPolicy == wordpressv1alpha1.CronRedirectFail:
+		c.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	case !options.WPCronAllowAnyURL:
+		// redirects are followed only within the site, like the requested URL
+		c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if !wp.IsSiteHost(req.URL.Host) {
+				return fmt.Errorf("%w: %s", errForbiddenHost, req.URL.Host)
+			}
+
+			if len(via) >= 10 {
+				return errTooManyRedirects
+			}
+
+			return nil
+		}
+	}
+
+	if req.URL.Scheme != "https" {
+		return c, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: spec.InsecureSkipVerify, // nolint: gosec
+	}
+
+	if req.Host != "" && req.Host != req.URL.Host {
+		tlsConfig.ServerName = hostname(req.Host)
+	}
+
+	if len(spec.CABundle) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(spec.CABundle) {
+			return nil, errInvalidCABundle
+		}
+	}
+
+	if tlsConfig.ServerName == "" && tlsConfig.RootCAs == nil && !tlsConfig.InsecureSkipVerify {
+		return c, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	// the transport isn't reused by other requests
+	transport.DisableKeepAlives = true
+	c.Transport = transport
+
+	return c, nil
+}
+
+// cronSucceeded returns whether a wp-cron.php request which got the given
+// status code succeeded.
+func cronSucceeded(wp *wordpress.Wordpress, code int) bool {
+	if code == http.StatusOK {
+		return true
+	}
+
+	isRedirect := code >= http.StatusMultipleChoices && code < http.StatusBadRequest
+
+	return isRedirect && cronHTTPSpec(wp).RedirectPolicy == wordpressv1alpha1.CronRedirectAccept
+}
+
+// hostname returns the host without the port.
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+
+	return host
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("wp-cron requests", func() {
+	var (
+		r           *ReconcileWordpress
+		ctx         context.Context
+		wp          *wordpress.Wordpress
+		allowAnyURL bool
+	)
+
+	BeforeEach(func() {
+		allowAnyURL = options.WPCronAllowAnyURL
+		ctx = context.Background()
+		r = &ReconcileWordpress{Log: logf.Log}
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+				Cron:   &wordpressv1alpha1.CronSpec{Mode: wordpressv1alpha1.CronModeHTTP},
+			},
+		})
+	})
+
+	AfterEach(func() {
+		options.WPCronAllowAnyURL = allowAnyURL
+	})
+
+	It("requests wp-cron.php from the site's service by default", func() {
+		req, err := newCronRequest(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.URL.String()).To(Equal("http://site.default.svc/wp-cron.php?doing_wp_cron"))
+		Expect(req.Host).To(Equal("example.com"))
+		Expect(req.Header.Get(forwardedProtoHeader)).To(BeEmpty())
+	})
+
+	It("requests wp-cron.php at the configured URL", func() {
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{
+			URL:            "https://example.com/blog/wp-cron.php?lang=en",
+			ForwardedProto: "https",
+		}
+
+		req, err := newCronRequest(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.URL.String()).To(Equal("https://example.com/blog/wp-cron.php?lang=en&doing_wp_cron"))
+		Expect(req.Host).To(Equal("example.com"))
+		Expect(req.Header.Get(forwardedProtoHeader)).To(Equal("https"))
+	})
+
+	It("requests wp-cron.php only at the site's route domains and service", func() {
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{URL: "http://10.0.0.1/wp-cron.php"}
+
+		_, err := newCronRequest(ctx, wp)
+		Expect(err).To(MatchError(errForbiddenHost))
+
+		for _, u := range []string{"https://EXAMPLE.com/wp-cron.php", "http://site.default.svc.cluster.local:8080/wp-cron.php"} {
+			wp.Spec.Cron.HTTP.URL = u
+
+			_, err = newCronRequest(ctx, wp)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		options.WPCronAllowAnyURL = true
+		wp.Spec.Cron.HTTP.URL = "http://10.0.0.1/wp-cron.php"
+
+		_, err = newCronRequest(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("follows redirects only within the site", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Redirect(w, req, "http://10.0.0.1/", http.StatusFound)
+		}))
+		defer server.Close()
+
+		// the test server is reached on the loopback address
+		wp.Spec.Routes = append(wp.Spec.Routes, wordpressv1alpha1.RouteSpec{Domain: "127.0.0.1"})
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{URL: server.URL + "/wp-cron.php"}
+
+		code, err := r.triggerWPCron(ctx, wp)
+		Expect(err).To(MatchError(errForbiddenHost))
+		Expect(code).To(BeZero())
+	})
+
+	It("verifies the server certificate with the CA bundle", func() {
+		var host string
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			host = req.Host
+		}))
+		defer server.Close()
+
+		options.WPCronAllowAnyURL = true
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{URL: server.URL + "/wp-cron.php"}
+
+		code, err := r.triggerWPCron(ctx, wp)
+		Expect(err).To(HaveOccurred())
+		Expect(code).To(BeZero())
+
+		wp.Spec.Cron.HTTP.CABundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		wp.Spec.Cron.HTTP.Host = "example.com"
+
+		code, err = r.triggerWPCron(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(host).To(Equal("example.com"))
+
+		wp.Spec.Cron.HTTP.CABundle = nil
+		wp.Spec.Cron.HTTP.InsecureSkipVerify = true
+
+		code, err = r.triggerWPCron(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal(http.StatusOK))
+	})
+
+	It("treats redirects according to the redirect policy", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/wp-cron.php" {
+				http.Redirect(w, req, "/redirected", http.StatusMovedPermanently)
+			}
+		}))
+		defer server.Close()
+
+		options.WPCronAllowAnyURL = true
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{
+			URL:            server.URL + "/wp-cron.php",
+			RedirectPolicy: wordpressv1alpha1.CronRedirectFollow,
+		}
+
+		code, err := r.triggerWPCron(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal(http.StatusOK))
+
+		wp.Spec.Cron.HTTP.RedirectPolicy = wordpressv1alpha1.CronRedirectAccept
+
+		code, err = r.triggerWPCron(ctx, wp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal(http.StatusMovedPermanently))
+
+		wp.Spec.Cron.HTTP.RedirectPolicy = wordpressv1alpha1.CronRedirectFail
+
+		code, err = r.triggerWPCron(ctx, wp)
+		Expect(err).To(MatchError(errHTTP))
+		Expect(code).To(Equal(http.StatusMovedPermanently))
+	})
+})
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+// siteState holds the wp-cron triggering state of a site.
+type siteState struct {
+	// failures is the number of consecutive failed triggers
+	failures int
+	// next is the time at which wp-cron is due to be triggered again
+	next time.Time
+	// interval is the interval with which next was computed
+	interval time.Duration
+}
+
+// scheduler keeps track of when wp-cron is due to be triggered for each
+// site, so that triggers are spread over time and failing sites are backed
+// off, regardless of how often the sites get reconciled.
+type scheduler struct {
+	mu    sync.Mutex
+	sites map[types.NamespacedName]*siteState
+	// rand returns a random number in [0, 1)
+	rand func() float64
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		sites: map[types.NamespacedName]*siteState{},
+		rand:  rand.Float64, // nolint: gosec
+	}
+}
+
+// due returns the time left until wp-cron is due to be triggered for the
+// site, or zero if it is due now. The first trigger of a site is scheduled at
+// a random time within its interval, to avoid triggering all sites at once
+// when the operator starts. When the interval of a site changes, its next
+// trigger is brought forward to within the new interval.
+func (s *scheduler) due(key types.NamespacedName, interval time.Duration, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.sites[key]
+	if !ok {
+		st = &siteState{next: now.Add(time.Duration(s.rand() * float64(interval))), interval: interval}
+		s.sites[key] = st
+	}
+
+	if st.interval != interval {
+		if next := now.Add(backoff(interval, st.failures)); next.Before(st.next) {
+			st.next = next
+		}
+
+		st.interval = interval
+	}
+
+	if left := st.next.Sub(now); left > 0 {
+		return left
+	}
+
+	return 0
+}
+
+// record records the outcome of a trigger and returns the delay until the
+// next one. The delay is doubled for each consecutive failure, up to
+// options.WPCronMaxBackoff, and a random jitter of up to options.WPCronJitter
+// of it is added.
+func (s *scheduler) record(key types.NamespacedName, interval time.Duration, now time.Time, err error) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.sites[key]
+	if !ok {
+		st = &siteState{}
+		s.sites[key] = st
+	}
+
+	if err != nil {
+		st.failures++
+	} else {
+		st.failures = 0
+	}
+
+	delay := backoff(interval, st.failures)
+	delay += time.Duration(s.rand() * options.WPCronJitter * float64(delay))
+	st.next = now.Add(delay)
+	st.interval = interval
+
+	return delay
+}
+
+// failures returns the number of consecutive failed triggers of a site.
+func (s *scheduler) failures(key types.NamespacedName) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.sites[key]; ok {
+		return st.failures
+	}
+
+	return 0
+}
+
+// forget drops the state of a site which no longer needs wp-cron triggering.
+func (s *scheduler) forget(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sites, key)
+}
+
+// backoff returns the interval doubled for each failure, up to
+// options.WPCronMaxBackoff. Intervals longer than that are not shortened.
+func backoff(interval time.Duration, failures int) time.Duration {
+	max := options.WPCronMaxBackoff
+	if interval > max {
+		max = interval
+	}
+
+	delay := interval
+	for i := 0; i < failures && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
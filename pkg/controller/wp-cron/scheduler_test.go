@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+var _ = Describe("wp-cron scheduler", func() {
+	var (
+		s        *scheduler
+		key      types.NamespacedName
+		now      time.Time
+		interval time.Duration
+		random   float64
+		errPing  = errors.New("ping failed")
+	)
+
+	BeforeEach(func() {
+		key = types.NamespacedName{Name: "site", Namespace: "default"}
+		now = time.Now()
+		interval = 30 * time.Second
+		random = 0
+
+		s = newScheduler()
+		s.rand = func() float64 { return random }
+	})
+
+	It("spreads the first trigger of sites within their interval", func() {
+		random = 0.5
+		Expect(s.due(key, interval, now)).To(Equal(15 * time.Second))
+		Expect(s.due(key, interval, now.Add(15*time.Second))).To(BeZero())
+	})
+
+	It("adds jitter to the interval", func() {
+		random = 0.5
+		Expect(s.record(key, interval, now, nil)).To(Equal(interval + time.Duration(0.5*options.WPCronJitter*float64(interval))))
+	})
+
+	It("waits for the interval between triggers", func() {
+		Expect(s.due(key, interval, now)).To(BeZero())
+		Expect(s.record(key, interval, now, nil)).To(Equal(interval))
+
+		Expect(s.due(key, interval, now.Add(10*time.Second))).To(Equal(20 * time.Second))
+		Expect(s.due(key, interval, now.Add(interval))).To(BeZero())
+	})
+
+	It("applies a shortened interval right away", func() {
+		interval = time.Hour
+		Expect(s.record(key, interval, now, nil)).To(Equal(time.Hour))
+
+		Expect(s.due(key, time.Minute, now.Add(10*time.Second))).To(Equal(time.Minute))
+		Expect(s.due(key, time.Minute, now.Add(70*time.Second))).To(BeZero())
+	})
+
+	It("keeps the next trigger when the interval is lengthened", func() {
+		Expect(s.record(key, interval, now, nil)).To(Equal(interval))
+		Expect(s.due(key, time.Hour, now.Add(10*time.Second))).To(Equal(20 * time.Second))
+	})
+
+	It("backs off failing sites exponentially", func() {
+		Expect(s.record(key, interval, now, errPing)).To(Equal(time.Minute))
+		Expect(s.record(key, interval, now, errPing)).To(Equal(2 * time.Minute))
+		Expect(s.record(key, interval, now, errPing)).To(Equal(4 * time.Minute))
+		Expect(s.record(key, interval, now, errPing)).To(Equal(8 * time.Minute))
+		Expect(s.record(key, interval, now, errPing)).To(Equal(options.WPCronMaxBackoff))
+		Expect(s.record(key, interval, now, errPing)).To(Equal(options.WPCronMaxBackoff))
+
+		Expect(s.record(key, interval, now, nil)).To(Equal(interval))
+	})
+
+	It("doesn't shorten intervals longer than the maximum backoff", func() {
+		interval = time.Hour
+		Expect(s.record(key, interval, now, errPing)).To(Equal(time.Hour))
+	})
+
+	It("starts over for forgotten sites", func() {
+		s.record(key, interval, now, errPing)
+		s.forget(key)
+
+		Expect(s.due(key, interval, now)).To(BeZero())
+	})
+})
@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,6 +28,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,11 +42,7 @@ import (
 	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
 )
 
-const (
-	controllerName      = "wp-cron-controller"
-	cronTriggerInterval = 30 * time.Second
-	cronTriggerTimeout  = 30 * time.Second
-)
+const controllerName = "wp-cron-controller"
 
 var errHTTP = errors.New("HTTP error")
 
@@ -63,6 +59,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		Log:      logf.Log.WithName(controllerName).WithValues("controller", controllerName),
 		scheme:   mgr.GetScheme(),
 		recorder: mgr.GetEventRecorderFor(controllerName),
+		sites:    newScheduler(),
+		metrics:  newSiteMetrics(),
 	}
 }
 
@@ -91,6 +89,8 @@ type ReconcileWordpress struct {
 	Log      logr.Logger
 	scheme   *runtime.Scheme
 	recorder record.EventRecorder
+	sites    *scheduler
+	metrics  *siteMetrics
 }
 
 // Reconcile reads that state of the cluster for a Wordpress object and makes changes based on the state read
@@ -103,8 +103,14 @@ func (r *ReconcileWordpress) Reconcile(ctx context.Context, request reconcile.Re
 	wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
 
 	err := r.Get(ctx, request.NamespacedName, wp.Unwrap())
+	if k8serrors.IsNotFound(err) {
+		r.forget(request.NamespacedName)
+
+		return reconcile.Result{}, nil
+	}
+
 	if err != nil {
-		return reconcile.Result{}, ignoreNotFound(err)
+		return reconcile.Result{}, err
 	}
 
 	r.scheme.Default(wp.Unwrap())
@@ -112,33 +118,39 @@ func (r *ReconcileWordpress) Reconcile(ctx context.Context, request reconcile.Re
 
 	log := r.Log.WithValues("key", request.NamespacedName)
 
-	requeue := reconcile.Result{
-		Requeue:      true,
-		RequeueAfter: cronTriggerInterval,
-	}
-
-	svcHostname := fmt.Sprintf("%s.%s.svc", wp.Name, wp.Namespace)
-	u := wp.SiteURL("wp-cron.php") + "?doing_wp_cron"
-
-	_u, err := url.Parse(u)
-	if err != nil {
-		log.Error(err, "error parsing url", "url", u)
+	// wp-cron.php is requested only in http mode
+	if wp.Spec.Cron.Mode != wordpressv1alpha1.CronModeHTTP {
+		r.forget(request.NamespacedName)
 
-		return requeue, nil
+		return reconcile.Result{}, r.removeWPCronStatus(ctx, wp)
 	}
 
-	_u.Scheme = "http"
-	_u.Host = svcHostname
+	// the site may be reconciled before wp-cron is due, eg. when it's updated
+	interval := wp.CronInterval()
+	if wait := r.sites.due(request.NamespacedName, interval, time.Now()); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
 
-	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), cronTriggerTimeout)
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), wp.CronTimeout())
 	defer cancel()
 
-	err = r.pingURL(ctxWithTimeout, _u.String(), wp.MainDomain())
+	start := time.Now()
+
+	code, err := r.triggerWPCron(ctxWithTimeout, wp)
 	if err != nil {
 		log.Error(err, "error while triggering wp-cron")
 	}
 
-	err = r.updateWPCronStatus(ctx, wp, err)
+	duration := time.Since(start)
+
+	requeue := reconcile.Result{
+		Requeue:      true,
+		RequeueAfter: r.sites.record(request.NamespacedName, interval, time.Now(), err),
+	}
+
+	r.metrics.observe(request.NamespacedName, code, duration, r.sites.failures(request.NamespacedName))
+
+	err = r.updateWPCronStatus(ctx, wp, start, duration, code, err)
 	if err != nil {
 		log.Error(err, "error updating wordpress wp-cron status")
 	}
@@ -183,9 +195,40 @@ func maybeUpdateWPCronCondition(cond wordpressv1alpha1.WordpressCondition, err e
 	return cond, needsUpdate
 }
 
-func (r *ReconcileWordpress) updateWPCronStatus(ctx context.Context, wp *wordpress.Wordpress, e error) error {
+// updateCronStatus returns the wp-cron status after a wp-cron.php request
+// which started at the given time and got the given status code.
+func updateCronStatus(in *wordpressv1alpha1.CronStatus, start time.Time, duration time.Duration,
+	code int, err error) *wordpressv1alpha1.CronStatus {
+	status := &wordpressv1alpha1.CronStatus{}
+	if in != nil {
+		in.DeepCopyInto(status)
+	}
+
+	attempt := metav1.NewTime(start)
+
+	status.LastAttemptTime = &attempt
+	status.LastDuration = &metav1.Duration{Duration: duration.Round(time.Millisecond)}
+	status.LastHTTPStatus = int32(code)
+
+	if err != nil {
+		status.ConsecutiveFailures++
+	} else {
+		status.LastSuccessTime = &attempt
+		status.ConsecutiveFailures = 0
+	}
+
+	return status
+}
+
+// updateWPCronStatus records the outcome of a wp-cron.php request in
+// status.cron and in the WPCronTriggering condition, by patching the status.
+// The patch is made with an optimistic lock when the condition changes,
+// because the conditions list is replaced as a whole.
+func (r *ReconcileWordpress) updateWPCronStatus(ctx context.Context, wp *wordpress.Wordpress, start time.Time,
+	duration time.Duration, code int, e error) error {
 	var needsUpdate bool
 
+	base := wp.Unwrap().DeepCopy()
 	idx := -1
 
 	for i := range wp.Status.Conditions {
@@ -202,30 +245,58 @@ func (r *ReconcileWordpress) updateWPCronStatus(ctx context.Context, wp *wordpre
 	}
 
 	wp.Status.Conditions[idx], needsUpdate = maybeUpdateWPCronCondition(wp.Status.Conditions[idx], e)
+	if !needsUpdate {
+		wp.Status.Conditions = base.Status.Conditions
+	}
+
+	wp.Status.Cron = updateCronStatus(wp.Status.Cron, start, duration, code, e)
 
+	patch := client.MergeFrom(base)
 	if needsUpdate {
-		err := r.Client.Status().Update(ctx, wp.Unwrap())
-		if err != nil {
-			return err
-		}
+		patch = client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})
 	}
 
-	return nil
+	return r.Client.Status().Patch(ctx, wp.Unwrap(), patch)
 }
 
-func (r *ReconcileWordpress) pingURL(ctx context.Context, url, hostOverride string) error {
-	client := &http.Client{}
+// removeWPCronStatus removes the WPCronTriggering condition and status.cron
+// of sites which don't run wp-cron in http mode.
+func (r *ReconcileWordpress) removeWPCronStatus(ctx context.Context, wp *wordpress.Wordpress) error {
+	base := wp.Unwrap().DeepCopy()
+	conditions := []wordpressv1alpha1.WordpressCondition{}
+
+	for _, cond := range wp.Status.Conditions {
+		if cond.Type != wordpressv1alpha1.WPCronTriggeringCondition {
+			conditions = append(conditions, cond)
+		}
+	}
+
+	if len(conditions) == len(wp.Status.Conditions) && wp.Status.Cron == nil {
+		return nil
+	}
+
+	wp.Status.Conditions = conditions
+	wp.Status.Cron = nil
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	return r.Client.Status().Patch(ctx, wp.Unwrap(), client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
+
+// triggerWPCron requests wp-cron.php for the site and returns the HTTP
+// status code of the response, or zero if no response was received.
+func (r *ReconcileWordpress) triggerWPCron(ctx context.Context, wp *wordpress.Wordpress) (int, error) {
+	req, err := newCronRequest(ctx, wp)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	req.Host = hostOverride
+	c, err := newCronClient(wp, req)
+	if err != nil {
+		return 0, err
+	}
 
-	resp, err := client.Do(req)
+	resp, err := c.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	defer func() {
@@ -235,11 +306,17 @@ func (r *ReconcileWordpress) pingURL(ctx context.Context, url, hostOverride stri
 		}
 	}()
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("%w: %v, %v", errHTTP, resp.StatusCode, http.StatusText(resp.StatusCode))
+	if !cronSucceeded(wp, resp.StatusCode) {
+		return resp.StatusCode, fmt.Errorf("%w: %v, %v", errHTTP, resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
 
-	return nil
+	return resp.StatusCode, nil
+}
+
+// forget drops the wp-cron triggering state and metrics of a site.
+func (r *ReconcileWordpress) forget(key types.NamespacedName) {
+	r.sites.forget(key)
+	r.metrics.forget(key)
 }
 
 func ignoreNotFound(err error) error {
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wpcron
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("wp-cron status", func() {
+	var (
+		r       *ReconcileWordpress
+		c       client.Client
+		ctx     context.Context
+		key     types.NamespacedName
+		start   time.Time
+		errPing = errors.New("ping failed")
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "site", Namespace: "default"}
+		start = time.Now().Truncate(time.Second)
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(wordpressv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		wp := &wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Status: wordpressv1alpha1.WordpressStatus{
+				Conditions: []wordpressv1alpha1.WordpressCondition{
+					{Type: wordpressv1alpha1.ReadyCondition, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(wp).Build()
+		r = &ReconcileWordpress{Client: c, scheme: scheme}
+	})
+
+	getSite := func() *wordpress.Wordpress {
+		wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+		Expect(c.Get(ctx, key, wp.Unwrap())).To(Succeed())
+
+		return wp
+	}
+
+	It("records successful requests", func() {
+		Expect(r.updateWPCronStatus(ctx, getSite(), start, 1500*time.Millisecond, 200, nil)).To(Succeed())
+
+		wp := getSite()
+		Expect(wp.Status.Cron).NotTo(BeNil())
+		Expect(wp.Status.Cron.LastAttemptTime.Time).To(BeTemporally("==", start))
+		Expect(wp.Status.Cron.LastSuccessTime.Time).To(BeTemporally("==", start))
+		Expect(wp.Status.Cron.LastDuration.Duration).To(Equal(1500 * time.Millisecond))
+		Expect(wp.Status.Cron.LastHTTPStatus).To(Equal(int32(200)))
+		Expect(wp.Status.Cron.ConsecutiveFailures).To(BeZero())
+
+		cond := wp.GetCondition(wordpressv1alpha1.WPCronTriggeringCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+		Expect(wp.Status.Conditions).To(HaveLen(2))
+	})
+
+	It("counts consecutive failures", func() {
+		Expect(r.updateWPCronStatus(ctx, getSite(), start, time.Second, 200, nil)).To(Succeed())
+
+		failed := start.Add(time.Minute)
+		Expect(r.updateWPCronStatus(ctx, getSite(), failed, time.Second, 502, errPing)).To(Succeed())
+		Expect(r.updateWPCronStatus(ctx, getSite(), failed, 30*time.Second, 0, errPing)).To(Succeed())
+
+		wp := getSite()
+		Expect(wp.Status.Cron.LastAttemptTime.Time).To(BeTemporally("==", failed))
+		Expect(wp.Status.Cron.LastSuccessTime.Time).To(BeTemporally("==", start))
+		Expect(wp.Status.Cron.LastHTTPStatus).To(BeZero())
+		Expect(wp.Status.Cron.ConsecutiveFailures).To(Equal(int32(2)))
+
+		cond := wp.GetCondition(wordpressv1alpha1.WPCronTriggeringCondition)
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Message).To(Equal(errPing.Error()))
+
+		Expect(r.updateWPCronStatus(ctx, getSite(), failed.Add(time.Minute), time.Second, 200, nil)).To(Succeed())
+		Expect(getSite().Status.Cron.ConsecutiveFailures).To(BeZero())
+	})
+
+	It("removes the wp-cron status", func() {
+		Expect(r.updateWPCronStatus(ctx, getSite(), start, time.Second, 200, nil)).To(Succeed())
+		Expect(r.removeWPCronStatus(ctx, getSite())).To(Succeed())
+
+		wp := getSite()
+		Expect(wp.Status.Cron).To(BeNil())
+		Expect(wp.GetCondition(wordpressv1alpha1.WPCronTriggeringCondition)).To(BeNil())
+		Expect(wp.Status.Conditions).To(HaveLen(1))
+	})
+})
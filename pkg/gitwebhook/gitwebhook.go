@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitwebhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+// Path is the path on which push webhooks are received.
+const Path = "/git/push"
+
+const shutdownTimeout = 10 * time.Second
+
+var log = logf.Log.WithName("git-webhook")
+
+// AddToManager adds the git webhook receiver to the Manager. It listens on
+// options.GitWebhookBindAddress and verifies the webhooks with
+// options.GitWebhookSecret.
+func AddToManager(mgr manager.Manager) error {
+	if options.GitWebhookSecret == "" {
+		return errors.New("the GIT_WEBHOOK_SECRET env variable must be set to receive git webhooks")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, &Receiver{
+		Client: mgr.GetClient(),
+		Secret: []byte(options.GitWebhookSecret),
+	})
+
+	return mgr.Add(&server{
+		addr:    options.GitWebhookBindAddress,
+		handler: mux,
+	})
+}
+
+// server serves the receiver until the Manager stops. It runs on all the
+// replicas of the operator, not only on the leader.
+type server struct {
+	addr    string
+	handler http.Handler
+}
+
+func (s *server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		log.Info("starting git webhook receiver", "addr", s.addr, "path", Path)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("git webhook receiver failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (s *server) NeedLeaderElection() bool {
+	return false
+}
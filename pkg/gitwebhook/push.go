@@ -0,0 +1,162 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// pushEvent holds the fields of GitHub, GitLab and Gitea push events used to
+// find the sites whose code got pushed to.
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		// GitHub and Gitea
+		CloneURL      string `json:"clone_url"`
+		SSHURL        string `json:"ssh_url"`
+		GitURL        string `json:"git_url"`
+		HTMLURL       string `json:"html_url"`
+		DefaultBranch string `json:"default_branch"`
+		// GitLab
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+		Homepage   string `json:"homepage"`
+	} `json:"repository"`
+	// GitLab
+	Project struct {
+		GitHTTPURL    string `json:"git_http_url"`
+		GitSSHURL     string `json:"git_ssh_url"`
+		WebURL        string `json:"web_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+}
+
+// repositories returns the normalized URLs of the pushed repository.
+func (e *pushEvent) repositories() map[string]bool {
+	out := map[string]bool{}
+
+	for _, u := range []string{
+		e.Repository.CloneURL, e.Repository.SSHURL, e.Repository.GitURL, e.Repository.HTMLURL,
+		e.Repository.GitHTTPURL, e.Repository.GitSSHURL, e.Repository.Homepage,
+		e.Project.GitHTTPURL, e.Project.GitSSHURL, e.Project.WebURL,
+	} {
+		if n := normalizeRepository(u); n != "" {
+			out[n] = true
+		}
+	}
+
+	return out
+}
+
+func (e *pushEvent) defaultBranch() string {
+	if e.Repository.DefaultBranch != "" {
+		return e.Repository.DefaultBranch
+	}
+
+	return e.Project.DefaultBranch
+}
+
+// matchesRef returns true if the push updated the given git reference, as
+// set on sites. An empty reference matches the default branch.
+func (e *pushEvent) matchesRef(ref string) bool {
+	if ref == "" {
+		ref = e.defaultBranch()
+	}
+
+	if ref == "" {
+		return false
+	}
+
+	return e.Ref == ref || e.Ref == "refs/heads/"+ref || e.Ref == "refs/tags/"+ref
+}
+
+// isPushEvent returns false for the events other than pushes, like the ping
+// sent by GitHub when the webhook gets created.
+func isPushEvent(h http.Header) bool {
+	if event := h.Get("X-GitHub-Event"); event != "" {
+		return event == "push"
+	}
+
+	if event := h.Get("X-Gitea-Event"); event != "" {
+		return event == "push"
+	}
+
+	if event := h.Get("X-Gitlab-Event"); event != "" {
+		return event == "Push Hook" || event == "Tag Push Hook"
+	}
+
+	return true
+}
+
+// verify checks the HMAC signature of the body, as sent by GitHub and Gitea,
+// or the secret token, as sent by GitLab.
+func verify(h http.Header, body, secret []byte) bool {
+	if token := h.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), secret) == 1
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, header := range []string{"X-Hub-Signature-256", "X-Gitea-Signature"} {
+		signature := h.Get(header)
+		if signature == "" {
+			continue
+		}
+
+		actual, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+		if err != nil {
+			return false
+		}
+
+		return hmac.Equal(actual, expected)
+	}
+
+	return false
+}
+
+// normalizeRepository returns the host and path of a repository URL, so that
+// the https, ssh and scp-like URLs of the same repository are equal.
+func normalizeRepository(raw string) string {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return ""
+	}
+
+	// scp-like syntax, eg. git@github.com:bitpoke/wordpress-operator.git
+	if !strings.Contains(s, "://") {
+		if i := strings.Index(s, ":"); i > 0 && !strings.Contains(s[:i], "/") {
+			s = "ssh://" + s[:i] + "/" + s[i+1:]
+		}
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	p := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+
+	return strings.ToLower(u.Hostname() + "/" + p)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+// maxBodySize is the maximum size of the webhook payloads. GitHub caps them
+// at 25MB, but push payloads are usually much smaller.
+const maxBodySize = 10 << 20
+
+// Receiver handles git push webhooks, redeploying the sites whose code is
+// cloned from the pushed repository and reference. The sites are annotated
+// with the time of the push, which makes the operator resolve their git
+// reference right away, or restart their pods if it's not resolved.
+type Receiver struct {
+	Client client.Client
+	Secret []byte
+}
+
+// pushResponse is the body of the responses to push webhooks.
+type pushResponse struct {
+	Sites []string `json:"sites"`
+}
+
+func (h *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+
+		return
+	}
+
+	if !verify(r.Header, body, h.Secret) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+
+		return
+	}
+
+	if !isPushEvent(r.Header) {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	event := &pushEvent{}
+	if err = json.Unmarshal(body, event); err != nil {
+		http.Error(w, "invalid push event", http.StatusBadRequest)
+
+		return
+	}
+
+	sites, err := h.redeploy(r.Context(), event)
+	if err != nil {
+		log.Error(err, "failed to redeploy sites", "ref", event.Ref)
+		http.Error(w, "failed to redeploy sites", http.StatusInternalServerError)
+
+		return
+	}
+
+	log.Info("received git push", "ref", event.Ref, "sites", sites)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pushResponse{Sites: sites})
+}
+
+// redeploy annotates the sites matching the push event with the time of the
+// push and returns their keys.
+func (h *Receiver) redeploy(ctx context.Context, event *pushEvent) ([]string, error) {
+	repositories := event.repositories()
+	sites := []string{}
+
+	if len(repositories) == 0 {
+		return sites, nil
+	}
+
+	list := &wordpressv1alpha1.WordpressList{}
+	if err := h.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	for i := range list.Items {
+		wp := &list.Items[i]
+
+		code := wp.Spec.CodeVolumeSpec
+		if code == nil || code.GitDir == nil {
+			continue
+		}
+
+		if !repositories[normalizeRepository(code.GitDir.Repository)] || !event.matchesRef(code.GitDir.GitRef) {
+			continue
+		}
+
+		patch := client.MergeFrom(wp.DeepCopy())
+
+		if wp.Annotations == nil {
+			wp.Annotations = map[string]string{}
+		}
+
+		wp.Annotations[wordpress.GitPushAnnotation] = now
+
+		if err := h.Client.Patch(ctx, wp, patch); err != nil {
+			return nil, err
+		}
+
+		sites = append(sites, client.ObjectKeyFromObject(wp).String())
+	}
+
+	return sites, nil
+}
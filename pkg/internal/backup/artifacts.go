@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+// Artifacts locates a set of backup artifacts within a backup destination.
+type Artifacts struct {
+	destination *wordpressv1alpha1.BackupDestination
+	subPath     string
+}
+
+// NewArtifacts returns the artifacts stored under subPath, relative to the
+// destination prefix.
+func NewArtifacts(dest *wordpressv1alpha1.BackupDestination, subPath string) *Artifacts {
+	return &Artifacts{destination: dest, subPath: subPath}
+}
+
+// Path returns the path of the artifacts, relative to the destination bucket or volume.
+func (a *Artifacts) Path() string {
+	return path.Join(destinationPrefix(a.destination), a.subPath)
+}
+
+// Location returns the URL of the artifacts.
+func (a *Artifacts) Location() string {
+	switch {
+	case a.destination.S3 != nil:
+		return fmt.Sprintf("s3://%s", path.Join(a.destination.S3.Bucket, a.Path()))
+	case a.destination.GCS != nil:
+		return fmt.Sprintf("gs://%s", path.Join(a.destination.GCS.Bucket, a.Path()))
+	case a.destination.PersistentVolumeClaim != nil:
+		return fmt.Sprintf("pvc://%s", path.Join(a.destination.PersistentVolumeClaim.ClaimName, a.Path()))
+	}
+
+	return ""
+}
+
+// Env returns the environment variables which configure the rclone remote
+// used by URL.
+func (a *Artifacts) Env() []corev1.EnvVar {
+	return remoteEnv(destinationRemote, a.destination.S3, a.destination.GCS)
+}
+
+// URL returns the rclone path of the artifacts.
+func (a *Artifacts) URL() string {
+	switch {
+	case a.destination.S3 != nil:
+		return remotePath(destinationRemote, a.destination.S3.Bucket, a.Path())
+	case a.destination.GCS != nil:
+		return remotePath(destinationRemote, a.destination.GCS.Bucket, a.Path())
+	}
+
+	return path.Join(destinationMountPath, a.Path())
+}
+
+// Volumes returns the volumes needed for accessing the artifacts, if they are
+// stored on a persistent volume claim.
+func (a *Artifacts) Volumes() []corev1.Volume {
+	if a.destination.PersistentVolumeClaim == nil {
+		return []corev1.Volume{}
+	}
+
+	return []corev1.Volume{
+		{
+			Name: destinationVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: a.destination.PersistentVolumeClaim.ClaimName,
+				},
+			},
+		},
+	}
+}
+
+// VolumeMounts returns the volume mounts for Volumes.
+func (a *Artifacts) VolumeMounts() []corev1.VolumeMount {
+	if a.destination.PersistentVolumeClaim == nil {
+		return []corev1.VolumeMount{}
+	}
+
+	return []corev1.VolumeMount{
+		{
+			Name:      destinationVolumeName,
+			MountPath: destinationMountPath,
+		},
+	}
+}
+
+func destinationPrefix(dest *wordpressv1alpha1.BackupDestination) string {
+	switch {
+	case dest.S3 != nil:
+		return dest.S3.PathPrefix
+	case dest.GCS != nil:
+		return dest.GCS.PathPrefix
+	case dest.PersistentVolumeClaim != nil:
+		return dest.PersistentVolumeClaim.PathPrefix
+	}
+
+	return ""
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	// BackupLabel is the label set on the jobs and pods of a backup, holding
+	// the backup name.
+	BackupLabel = "wordpress.presslabs.org/backup"
+
+	// ScheduleLabel is the label set on backups created by a
+	// WordpressBackupSchedule, holding the schedule name.
+	ScheduleLabel = "wordpress.presslabs.org/backup-schedule"
+
+	// ArtifactsFinalizer is the finalizer which makes sure the backup
+	// artifacts are removed before the backup is deleted.
+	ArtifactsFinalizer = "wordpress.presslabs.org/backup-artifacts"
+
+	// DatabaseArtifact is the name of the database dump within the backup.
+	DatabaseArtifact = "db.sql.gz"
+
+	// MediaArtifact is the name of the media archive within the backup, used
+	// when the media files are stored on a volume.
+	MediaArtifact = "media.tar.gz"
+
+	// MediaDirArtifact is the name of the directory holding the media files
+	// within the backup, used when the media files are stored in a bucket.
+	MediaDirArtifact = "media"
+)
+
+// Backup embeds wordpressv1alpha1.WordpressBackup and adds utility functions.
+type Backup struct {
+	*wordpressv1alpha1.WordpressBackup
+}
+
+// New wraps a wordpressv1alpha1.WordpressBackup into a Backup object.
+func New(obj *wordpressv1alpha1.WordpressBackup) *Backup {
+	return &Backup{obj}
+}
+
+// Unwrap returns the wrapped wordpressv1alpha1.WordpressBackup object.
+func (b *Backup) Unwrap() *wordpressv1alpha1.WordpressBackup {
+	return b.WordpressBackup
+}
+
+// Labels returns the labels applied to the backup jobs.
+func (b *Backup) Labels() labels.Set {
+	return labels.Set{
+		"app.kubernetes.io/name":      "wordpress",
+		"app.kubernetes.io/instance":  b.Spec.WordpressRef.Name,
+		"app.kubernetes.io/component": "backup",
+		BackupLabel:                   b.Name,
+	}
+}
+
+// JobName returns the name of the job which takes the backup.
+func (b *Backup) JobName() string {
+	return b.Name
+}
+
+// CleanupJobName returns the name of the job which removes the backup artifacts.
+func (b *Backup) CleanupJobName() string {
+	return fmt.Sprintf("%s-cleanup", b.Name)
+}
+
+// ArtifactPath returns the path of the backup artifacts, relative to the
+// destination bucket or volume.
+func (b *Backup) ArtifactPath() string {
+	return b.Artifacts().Path()
+}
+
+// Artifacts returns the location of the backup artifacts.
+func (b *Backup) Artifacts() *Artifacts {
+	return NewArtifacts(&b.Spec.Destination, path.Join(b.Namespace, b.Spec.WordpressRef.Name, b.Name))
+}
+
+// Location returns the URL of the backup artifacts.
+func (b *Backup) Location() string {
+	return b.Artifacts().Location()
+}
+
+// GetCondition returns the condition of the given type or nil if the condition is not set.
+func (b *Backup) GetCondition(condType wordpressv1alpha1.WordpressConditionType) *wordpressv1alpha1.WordpressCondition {
+	return wordpress.FindCondition(b.Status.Conditions, condType)
+}
+
+// SetCondition sets the status, reason and message of the given condition
+// type. It returns true if the condition has been changed.
+func (b *Backup) SetCondition(condType wordpressv1alpha1.WordpressConditionType,
+	status corev1.ConditionStatus, reason, message string) bool {
+	return wordpress.UpdateCondition(&b.Status.Conditions, condType, status, reason, message)
+}
+
+// IsComplete returns true if the backup artifacts were successfully uploaded.
+func (b *Backup) IsComplete() bool {
+	cond := b.GetCondition(wordpressv1alpha1.BackupCompleteCondition)
+
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// IsFailed returns true if the backup failed.
+func (b *Backup) IsFailed() bool {
+	cond := b.GetCondition(wordpressv1alpha1.BackupFailedCondition)
+
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// IsFinished returns true if the backup either completed or failed.
+func (b *Backup) IsFinished() bool {
+	return b.IsComplete() || b.IsFailed()
+}
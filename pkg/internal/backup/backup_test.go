@@ -0,0 +1,193 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("Backup", func() {
+	var (
+		b  *Backup
+		wp *wordpress.Wordpress
+	)
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+			},
+		})
+
+		b = New(&wordpressv1alpha1.WordpressBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressBackupSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: "site"},
+				Destination: wordpressv1alpha1.BackupDestination{
+					S3: &wordpressv1alpha1.S3VolumeSource{
+						Bucket:     "backups",
+						PathPrefix: "sites",
+						Env: []corev1.EnvVar{
+							{Name: "AWS_ACCESS_KEY_ID", Value: "key"},
+							{Name: "ENDPOINT", Value: "https://s3.example.com"},
+							{Name: "UNUSED", Value: "unused"},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	Describe("location", func() {
+		It("stores the artifacts under the namespace, site and backup name", func() {
+			Expect(b.ArtifactPath()).To(Equal("sites/default/site/daily"))
+			Expect(b.Location()).To(Equal("s3://backups/sites/default/site/daily"))
+			Expect(b.DestinationURL()).To(Equal("dest:backups/sites/default/site/daily"))
+		})
+
+		It("supports google cloud storage", func() {
+			b.Spec.Destination = wordpressv1alpha1.BackupDestination{
+				GCS: &wordpressv1alpha1.GCSVolumeSource{Bucket: "backups"},
+			}
+
+			Expect(b.Location()).To(Equal("gs://backups/default/site/daily"))
+			Expect(b.DestinationURL()).To(Equal("dest:backups/default/site/daily"))
+			Expect(b.DestinationEnv()).To(ContainElement(corev1.EnvVar{
+				Name: "RCLONE_CONFIG_DEST_TYPE", Value: "google cloud storage",
+			}))
+		})
+
+		It("supports persistent volume claims", func() {
+			b.Spec.Destination = wordpressv1alpha1.BackupDestination{
+				PersistentVolumeClaim: &wordpressv1alpha1.PVCBackupDestination{ClaimName: "backups"},
+			}
+
+			Expect(b.Location()).To(Equal("pvc://backups/default/site/daily"))
+			Expect(b.DestinationURL()).To(Equal("/mnt/destination/default/site/daily"))
+			Expect(b.DestinationEnv()).To(BeEmpty())
+			Expect(b.DestinationVolumes()).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("destination"),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"PersistentVolumeClaim": PointTo(MatchFields(IgnoreExtras, Fields{
+						"ClaimName": Equal("backups"),
+					})),
+				}),
+			})))
+		})
+	})
+
+	It("maps the bucket credentials to rclone options", func() {
+		Expect(b.DestinationEnv()).To(ConsistOf(
+			corev1.EnvVar{Name: "RCLONE_CONFIG_DEST_TYPE", Value: "s3"},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_DEST_PROVIDER", Value: "Other"},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_DEST_ENV_AUTH", Value: "true"},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_DEST_ACCESS_KEY_ID", Value: "key"},
+			corev1.EnvVar{Name: "RCLONE_CONFIG_DEST_ENDPOINT", Value: "https://s3.example.com"},
+		))
+	})
+
+	It("tracks the backup conditions", func() {
+		Expect(b.IsFinished()).To(BeFalse())
+
+		Expect(b.SetCondition(wordpressv1alpha1.BackupCompleteCondition, corev1.ConditionFalse, "", "")).To(BeTrue())
+		Expect(b.IsFinished()).To(BeFalse())
+
+		Expect(b.SetCondition(wordpressv1alpha1.BackupFailedCondition, corev1.ConditionTrue, "", "")).To(BeTrue())
+		Expect(b.IsFailed()).To(BeTrue())
+		Expect(b.IsComplete()).To(BeFalse())
+		Expect(b.IsFinished()).To(BeTrue())
+	})
+
+	Describe("job pod template", func() {
+		It("dumps the site with wp-cli and uploads the artifacts with rclone", func() {
+			pod := b.JobPodTemplateSpec(wp)
+
+			Expect(pod.Labels).To(HaveKeyWithValue(BackupLabel, "daily"))
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+			dump := lastInitContainer(pod)
+			Expect(dump.Name).To(Equal("dump"))
+			Expect(dump.Image).To(Equal(wp.Spec.Image))
+			Expect(dump.VolumeMounts).To(ContainElement(backupVolumeMount()))
+
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			upload := pod.Spec.Containers[0]
+			Expect(upload.Name).To(Equal(UploadContainerName))
+			Expect(upload.Image).To(Equal(options.RcloneImage))
+			Expect(upload.Env).To(ContainElement(corev1.EnvVar{Name: "DESTINATION", Value: b.DestinationURL()}))
+			Expect(upload.VolumeMounts).To(ContainElement(backupVolumeMount()))
+
+			Expect(pod.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal(backupVolumeName),
+			})))
+		})
+
+		It("archives the media files stored on volumes", func() {
+			wp.Spec.MediaVolumeSpec = &wordpressv1alpha1.MediaVolumeSpec{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			}
+			wp.SetDefaults()
+
+			dump := lastInitContainer(b.JobPodTemplateSpec(wp))
+			Expect(dump.Env).To(ContainElement(corev1.EnvVar{Name: "BACKUP_MEDIA_DIR", Value: wp.MediaDir()}))
+			Expect(wp.MediaDir()).NotTo(BeEmpty())
+
+			b.Spec.SkipMedia = true
+			dump = lastInitContainer(b.JobPodTemplateSpec(wp))
+			Expect(dump.Env).To(ContainElement(corev1.EnvVar{Name: "BACKUP_MEDIA_DIR", Value: ""}))
+		})
+
+		It("copies the media files stored in buckets", func() {
+			wp.Spec.MediaVolumeSpec = &wordpressv1alpha1.MediaVolumeSpec{
+				GCSVolumeSource: &wordpressv1alpha1.GCSVolumeSource{
+					Bucket:     "media",
+					PathPrefix: "site",
+					Env: []corev1.EnvVar{
+						{Name: "GOOGLE_CREDENTIALS", Value: "{}"},
+					},
+				},
+			}
+
+			upload := b.JobPodTemplateSpec(wp).Spec.Containers[0]
+			Expect(upload.Env).To(ContainElements(
+				corev1.EnvVar{Name: "MEDIA_SOURCE", Value: "media:media/site"},
+				corev1.EnvVar{Name: "RCLONE_CONFIG_MEDIA_TYPE", Value: "google cloud storage"},
+				corev1.EnvVar{Name: "RCLONE_CONFIG_MEDIA_SERVICE_ACCOUNT_CREDENTIALS", Value: "{}"},
+			))
+
+			b.Spec.SkipMedia = true
+			upload = b.JobPodTemplateSpec(wp).Spec.Containers[0]
+			Expect(upload.Env).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("MEDIA_SOURCE"),
+			})))
+		})
+	})
+})
+
+func lastInitContainer(pod corev1.PodTemplateSpec) corev1.Container {
+	return pod.Spec.InitContainers[len(pod.Spec.InitContainers)-1]
+}
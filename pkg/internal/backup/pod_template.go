@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	backupVolumeName = "backup"
+	backupMountPath  = "/backup"
+)
+
+const dumpScript = `#!/bin/bash
+set -e
+set -o pipefail
+
+wp db export - | gzip > "$BACKUP_DIR/` + DatabaseArtifact + `"
+
+if [ -n "$BACKUP_MEDIA_DIR" ] ; then
+    tar czf "$BACKUP_DIR/` + MediaArtifact + `" -C "$BACKUP_MEDIA_DIR" .
+fi
+`
+
+const uploadScript = `set -e
+
+rclone copy "$BACKUP_DIR" "$DESTINATION"
+
+if [ -n "$MEDIA_SOURCE" ] ; then
+    rclone copy "$MEDIA_SOURCE" "$DESTINATION/` + MediaDirArtifact + `"
+fi
+
+rclone size --json "$DESTINATION" > /dev/termination-log
+`
+
+const cleanupScript = `set -e
+
+if [ -z "$(rclone lsf --max-depth 1 "$DESTINATION" 2>/dev/null)" ] ; then
+    echo "No backup artifacts found at $DESTINATION"
+    exit 0
+fi
+
+rclone purge "$DESTINATION"
+`
+
+// UploadContainerName is the name of the backup job container which uploads
+// the artifacts. Its termination message holds the output of `rclone size --json`.
+const UploadContainerName = "upload"
+
+// JobPodTemplateSpec generates the pod template of the job which takes the
+// backup. The database and the media files found on volumes are dumped by
+// an init container running wp-cli, while the main container uploads them
+// to the backup destination along with the media files stored in a bucket.
+func (b *Backup) JobPodTemplateSpec(wp *wordpress.Wordpress) corev1.PodTemplateSpec {
+	out := wp.JobPodTemplateSpec("/bin/bash", "-c", dumpScript)
+	out.ObjectMeta.Labels = labels.Merge(out.ObjectMeta.Labels, labels.Set{BackupLabel: b.Name})
+
+	mediaDir := ""
+	if !b.Spec.SkipMedia {
+		mediaDir = wp.MediaDir()
+	}
+
+	dump := out.Spec.Containers[0]
+	dump.Name = "dump"
+	dump.Env = append(dump.Env,
+		corev1.EnvVar{Name: "BACKUP_DIR", Value: backupMountPath},
+		corev1.EnvVar{Name: "BACKUP_MEDIA_DIR", Value: mediaDir},
+	)
+	dump.VolumeMounts = append(dump.VolumeMounts, backupVolumeMount())
+
+	upload := b.rcloneContainer(UploadContainerName, uploadScript)
+	upload.Env = append(upload.Env, corev1.EnvVar{Name: "BACKUP_DIR", Value: backupMountPath})
+	upload.VolumeMounts = append(upload.VolumeMounts, backupVolumeMount())
+
+	if !b.Spec.SkipMedia && MediaURL(wp) != "" {
+		upload.Env = append(upload.Env, corev1.EnvVar{Name: "MEDIA_SOURCE", Value: MediaURL(wp)})
+		upload.Env = append(upload.Env, MediaEnv(wp)...)
+	}
+
+	// the sidecars are dropped, otherwise they would keep the job running
+	out.Spec.InitContainers = append(out.Spec.InitContainers, dump)
+	out.Spec.Containers = []corev1.Container{upload}
+
+	out.Spec.Volumes = append(out.Spec.Volumes, corev1.Volume{
+		Name: backupVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	out.Spec.Volumes = append(out.Spec.Volumes, b.DestinationVolumes()...)
+
+	return out
+}
+
+// CleanupPodTemplateSpec generates the pod template of the job which removes
+// the backup artifacts.
+func (b *Backup) CleanupPodTemplateSpec() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: b.Labels(),
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers:    []corev1.Container{b.rcloneContainer("cleanup", cleanupScript)},
+			Volumes:       b.DestinationVolumes(),
+		},
+	}
+}
+
+func (b *Backup) rcloneContainer(name, script string) corev1.Container {
+	return corev1.Container{
+		Name:    name,
+		Image:   options.RcloneImage,
+		Command: []string{"/bin/sh", "-c", script},
+		Env: append([]corev1.EnvVar{
+			{
+				Name:  "DESTINATION",
+				Value: b.DestinationURL(),
+			},
+		}, b.DestinationEnv()...),
+		VolumeMounts: b.DestinationVolumeMounts(),
+	}
+}
+
+func backupVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      backupVolumeName,
+		MountPath: backupMountPath,
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	destinationRemote     = "dest"
+	mediaRemote           = "media"
+	destinationVolumeName = "destination"
+	destinationMountPath  = "/mnt/destination"
+)
+
+var (
+	// map the environment variables used by the wordpress runtime for
+	// accessing buckets to the rclone remote configuration options
+	s3RcloneEnvVars = map[string]string{
+		"AWS_ACCESS_KEY_ID":     "ACCESS_KEY_ID",
+		"AWS_SECRET_ACCESS_KEY": "SECRET_ACCESS_KEY",
+		"AWS_REGION":            "REGION",
+		"ENDPOINT":              "ENDPOINT",
+	}
+	gcsRcloneEnvVars = map[string]string{
+		"GOOGLE_CREDENTIALS":             "SERVICE_ACCOUNT_CREDENTIALS",
+		"GOOGLE_APPLICATION_CREDENTIALS": "SERVICE_ACCOUNT_FILE",
+	}
+)
+
+// DestinationEnv returns the environment variables which configure the rclone
+// remote used by DestinationURL.
+func (b *Backup) DestinationEnv() []corev1.EnvVar {
+	return b.Artifacts().Env()
+}
+
+// DestinationURL returns the rclone path of the backup artifacts.
+func (b *Backup) DestinationURL() string {
+	return b.Artifacts().URL()
+}
+
+// DestinationVolumes returns the volumes needed for accessing the backup
+// artifacts, if they are stored on a persistent volume claim.
+func (b *Backup) DestinationVolumes() []corev1.Volume {
+	return b.Artifacts().Volumes()
+}
+
+// DestinationVolumeMounts returns the volume mounts for DestinationVolumes.
+func (b *Backup) DestinationVolumeMounts() []corev1.VolumeMount {
+	return b.Artifacts().VolumeMounts()
+}
+
+// MediaEnv returns the environment variables which configure the rclone
+// remote used by MediaURL.
+func MediaEnv(wp *wordpress.Wordpress) []corev1.EnvVar {
+	if wp.Spec.MediaVolumeSpec == nil {
+		return []corev1.EnvVar{}
+	}
+
+	return remoteEnv(mediaRemote, wp.Spec.MediaVolumeSpec.S3VolumeSource, wp.Spec.MediaVolumeSpec.GCSVolumeSource)
+}
+
+// MediaURL returns the rclone path of the media files of a site or an empty
+// string if the media files are not stored in a bucket.
+func MediaURL(wp *wordpress.Wordpress) string {
+	if wp.Spec.MediaVolumeSpec == nil {
+		return ""
+	}
+
+	switch {
+	case wp.Spec.MediaVolumeSpec.S3VolumeSource != nil:
+		src := wp.Spec.MediaVolumeSpec.S3VolumeSource
+
+		return remotePath(mediaRemote, src.Bucket, src.PathPrefix)
+	case wp.Spec.MediaVolumeSpec.GCSVolumeSource != nil:
+		src := wp.Spec.MediaVolumeSpec.GCSVolumeSource
+
+		return remotePath(mediaRemote, src.Bucket, src.PathPrefix)
+	}
+
+	return ""
+}
+
+func remotePath(remote, bucket, prefix string) string {
+	return fmt.Sprintf("%s:%s", remote, path.Join(bucket, prefix))
+}
+
+func remoteEnvVar(remote, option, value string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name:  fmt.Sprintf("RCLONE_CONFIG_%s_%s", strings.ToUpper(remote), option),
+		Value: value,
+	}
+}
+
+func remoteEnv(remote string, s3 *wordpressv1alpha1.S3VolumeSource, gcs *wordpressv1alpha1.GCSVolumeSource) []corev1.EnvVar {
+	switch {
+	case s3 != nil:
+		return mapRemoteEnv(remote, s3.Env, s3RcloneEnvVars, remoteEnvVar(remote, "TYPE", "s3"), s3Provider(remote, s3.Env))
+	case gcs != nil:
+		return mapRemoteEnv(remote, gcs.Env, gcsRcloneEnvVars, remoteEnvVar(remote, "TYPE", "google cloud storage"))
+	}
+
+	return []corev1.EnvVar{}
+}
+
+func mapRemoteEnv(remote string, env []corev1.EnvVar, names map[string]string, base ...corev1.EnvVar) []corev1.EnvVar {
+	out := append(base, remoteEnvVar(remote, "ENV_AUTH", "true"))
+
+	for _, e := range env {
+		if option, ok := names[e.Name]; ok {
+			_e := e.DeepCopy()
+			_e.Name = remoteEnvVar(remote, option, "").Name
+			out = append(out, *_e)
+		}
+	}
+
+	return out
+}
+
+func s3Provider(remote string, env []corev1.EnvVar) corev1.EnvVar {
+	for _, e := range env {
+		if e.Name == "ENDPOINT" {
+			return remoteEnvVar(remote, "PROVIDER", "Other")
+		}
+	}
+
+	return remoteEnvVar(remote, "PROVIDER", "AWS")
+}
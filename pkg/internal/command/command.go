@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	// CommandLabel is the label set on the jobs and pods of a command, holding
+	// the command name.
+	CommandLabel = "wordpress.presslabs.org/command"
+
+	// ContainerName is the name of the container running the command.
+	ContainerName = "wp-cli"
+
+	// MaxOutputLength is the maximum length of the command output recorded
+	// in status, which matches the kubelet termination message limit.
+	MaxOutputLength = 4096
+)
+
+// outputScript runs the command given as arguments and copies its output to
+// the termination log, from which the kubelet keeps the last 4096 bytes.
+const outputScript = `set -o pipefail
+"$@" 2>&1 | tee /dev/termination-log
+`
+
+// Command embeds wordpressv1alpha1.WordpressCommand and adds utility functions.
+type Command struct {
+	*wordpressv1alpha1.WordpressCommand
+}
+
+// New wraps a wordpressv1alpha1.WordpressCommand into a Command object.
+func New(obj *wordpressv1alpha1.WordpressCommand) *Command {
+	return &Command{obj}
+}
+
+// Unwrap returns the wrapped wordpressv1alpha1.WordpressCommand object.
+func (c *Command) Unwrap() *wordpressv1alpha1.WordpressCommand {
+	return c.WordpressCommand
+}
+
+// Labels returns the labels applied to the command jobs.
+func (c *Command) Labels() labels.Set {
+	return labels.Set{
+		"app.kubernetes.io/name":      "wordpress",
+		"app.kubernetes.io/instance":  c.Spec.WordpressRef.Name,
+		"app.kubernetes.io/component": "command",
+		CommandLabel:                  c.Name,
+	}
+}
+
+// JobName returns the name of the job which runs the command.
+func (c *Command) JobName() string {
+	return c.Name
+}
+
+// JobPodTemplateSpec returns the pod template of the command job. The command
+// runs in the site's wp-cli container, with the site's environment and volumes.
+func (c *Command) JobPodTemplateSpec(wp *wordpress.Wordpress) corev1.PodTemplateSpec {
+	args := append([]string{"/bin/bash", "-c", outputScript, "wordpress-command"}, c.Spec.Command...)
+
+	out := wp.JobPodTemplateSpec(args...)
+	out.ObjectMeta.Labels = labels.Merge(out.ObjectMeta.Labels, labels.Set{CommandLabel: c.Name})
+
+	// the sidecars are dropped, otherwise they would keep the job running
+	container := out.Spec.Containers[0]
+	container.TerminationMessagePolicy = corev1.TerminationMessageFallbackToLogsOnError
+	out.Spec.Containers = []corev1.Container{container}
+
+	return out
+}
+
+// GetCondition returns the condition of the given type or nil if the condition is not set.
+func (c *Command) GetCondition(condType wordpressv1alpha1.WordpressConditionType) *wordpressv1alpha1.WordpressCondition {
+	return wordpress.FindCondition(c.Status.Conditions, condType)
+}
+
+// SetCondition sets the status, reason and message of the given condition
+// type. It returns true if the condition has been changed.
+func (c *Command) SetCondition(condType wordpressv1alpha1.WordpressConditionType,
+	status corev1.ConditionStatus, reason, message string) bool {
+	return wordpress.UpdateCondition(&c.Status.Conditions, condType, status, reason, message)
+}
+
+// IsComplete returns true if the command exited successfully.
+func (c *Command) IsComplete() bool {
+	cond := c.GetCondition(wordpressv1alpha1.CommandCompleteCondition)
+
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// IsFailed returns true if the command failed.
+func (c *Command) IsFailed() bool {
+	cond := c.GetCondition(wordpressv1alpha1.CommandFailedCondition)
+
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// IsFinished returns true if the command either completed or failed.
+func (c *Command) IsFinished() bool {
+	return c.IsComplete() || c.IsFailed()
+}
+
+// OutputTail returns the last MaxOutputLength bytes of the given output,
+// without splitting UTF-8 characters.
+func OutputTail(output string) string {
+	if len(output) <= MaxOutputLength {
+		return output
+	}
+
+	output = output[len(output)-MaxOutputLength:]
+	for len(output) > 0 && !utf8.RuneStart(output[0]) {
+		output = output[1:]
+	}
+
+	return output
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("Command", func() {
+	var (
+		cmd *Command
+		wp  *wordpress.Wordpress
+	)
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes:   []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+				Sidecars: []corev1.Container{{Name: "proxy", Image: "proxy"}},
+			},
+		})
+		wp.SetDefaults()
+
+		cmd = New(&wordpressv1alpha1.WordpressCommand{
+			ObjectMeta: metav1.ObjectMeta{Name: "flush", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressCommandSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: "site"},
+				Command:      []string{"wp", "cache", "flush"},
+			},
+		})
+	})
+
+	It("runs the command in the site's wp-cli container", func() {
+		pod := cmd.JobPodTemplateSpec(wp)
+
+		Expect(pod.Labels).To(HaveKeyWithValue(CommandLabel, "flush"))
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+
+		container := pod.Spec.Containers[0]
+		Expect(container.Name).To(Equal(ContainerName))
+		Expect(container.Image).To(Equal(wp.Spec.Image))
+		Expect(container.Args).To(Equal([]string{"/bin/bash", "-c", outputScript, "wordpress-command", "wp", "cache", "flush"}))
+		Expect(container.TerminationMessagePolicy).To(Equal(corev1.TerminationMessageFallbackToLogsOnError))
+	})
+
+	It("keeps the tail of long outputs", func() {
+		Expect(OutputTail("done")).To(Equal("done"))
+
+		output := OutputTail(strings.Repeat("a", MaxOutputLength) + "done")
+		Expect(output).To(HaveLen(MaxOutputLength))
+		Expect(output).To(HaveSuffix("done"))
+
+		// multi-byte characters are not split
+		output = OutputTail("ă" + strings.Repeat("a", MaxOutputLength-1))
+		Expect(output).To(Equal(strings.Repeat("a", MaxOutputLength-1)))
+	})
+})
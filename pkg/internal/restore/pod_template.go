@@ -0,0 +1,119 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restore
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	backupVolumeName = "backup"
+	backupMountPath  = "/backup"
+)
+
+const downloadScript = `set -e
+
+rclone copy "$SOURCE" "$BACKUP_DIR" --exclude "/` + backup.MediaDirArtifact + `/**"
+
+if [ -n "$MEDIA_DESTINATION" ] && [ -n "$(rclone lsf --max-depth 1 "$SOURCE/` + backup.MediaDirArtifact + `" 2>/dev/null)" ] ; then
+    rclone copy "$SOURCE/` + backup.MediaDirArtifact + `" "$MEDIA_DESTINATION"
+fi
+`
+
+const importScript = `#!/bin/bash
+set -e
+set -o pipefail
+
+gunzip -c "$BACKUP_DIR/` + backup.DatabaseArtifact + `" | wp db import -
+
+if [ -n "$RESTORE_MEDIA_DIR" ] && [ -f "$BACKUP_DIR/` + backup.MediaArtifact + `" ] ; then
+    tar xzf "$BACKUP_DIR/` + backup.MediaArtifact + `" -C "$RESTORE_MEDIA_DIR"
+fi
+
+if [ -n "$SEARCH_DOMAIN" ] ; then
+    wp search-replace "//$SEARCH_DOMAIN" "//$REPLACE_DOMAIN" --all-tables-with-prefix --skip-columns=guid
+fi
+
+wp cache flush
+`
+
+// JobPodTemplateSpec generates the pod template of the job which restores the
+// site from the given artifacts. An init container downloads the artifacts and
+// copies the media files to the site's bucket, if it has one, while the main
+// container runs wp-cli to import the database and the media archive.
+func (r *Restore) JobPodTemplateSpec(wp *wordpress.Wordpress, artifacts *backup.Artifacts) corev1.PodTemplateSpec {
+	out := wp.JobPodTemplateSpec("/bin/bash", "-c", importScript)
+	out.ObjectMeta.Labels = labels.Merge(out.ObjectMeta.Labels, labels.Set{RestoreLabel: r.Name})
+
+	mediaDir := ""
+	if !r.Spec.SkipMedia {
+		mediaDir = wp.MediaDir()
+	}
+
+	search, replace := r.SearchReplace(wp)
+
+	wpCLI := out.Spec.Containers[0]
+	wpCLI.Env = append(wpCLI.Env,
+		corev1.EnvVar{Name: "BACKUP_DIR", Value: backupMountPath},
+		corev1.EnvVar{Name: "RESTORE_MEDIA_DIR", Value: mediaDir},
+		corev1.EnvVar{Name: "SEARCH_DOMAIN", Value: search},
+		corev1.EnvVar{Name: "REPLACE_DOMAIN", Value: replace},
+	)
+	wpCLI.VolumeMounts = append(wpCLI.VolumeMounts, backupVolumeMount())
+
+	download := corev1.Container{
+		Name:    "download",
+		Image:   options.RcloneImage,
+		Command: []string{"/bin/sh", "-c", downloadScript},
+		Env: append([]corev1.EnvVar{
+			{Name: "SOURCE", Value: artifacts.URL()},
+			{Name: "BACKUP_DIR", Value: backupMountPath},
+		}, artifacts.Env()...),
+		VolumeMounts: append([]corev1.VolumeMount{backupVolumeMount()}, artifacts.VolumeMounts()...),
+	}
+
+	if !r.Spec.SkipMedia && backup.MediaURL(wp) != "" {
+		download.Env = append(download.Env, corev1.EnvVar{Name: "MEDIA_DESTINATION", Value: backup.MediaURL(wp)})
+		download.Env = append(download.Env, backup.MediaEnv(wp)...)
+	}
+
+	// the sidecars are dropped, otherwise they would keep the job running
+	out.Spec.InitContainers = append(out.Spec.InitContainers, download)
+	out.Spec.Containers = []corev1.Container{wpCLI}
+
+	out.Spec.Volumes = append(out.Spec.Volumes, corev1.Volume{
+		Name: backupVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	out.Spec.Volumes = append(out.Spec.Volumes, artifacts.Volumes()...)
+
+	return out
+}
+
+func backupVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      backupVolumeName,
+		MountPath: backupMountPath,
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restore
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	// RestoreLabel is the label set on the jobs and pods of a restore,
+	// holding the restore name.
+	RestoreLabel = "wordpress.presslabs.org/restore"
+
+	// SiteFinalizer is the finalizer which makes sure the restored site is
+	// started again if the restore is deleted while in progress.
+	SiteFinalizer = "wordpress.presslabs.org/restore-site"
+)
+
+// Restore embeds wordpressv1alpha1.WordpressRestore and adds utility functions.
+type Restore struct {
+	*wordpressv1alpha1.WordpressRestore
+}
+
+// New wraps a wordpressv1alpha1.WordpressRestore into a Restore object.
+func New(obj *wordpressv1alpha1.WordpressRestore) *Restore {
+	return &Restore{obj}
+}
+
+// Unwrap returns the wrapped wordpressv1alpha1.WordpressRestore object.
+func (r *Restore) Unwrap() *wordpressv1alpha1.WordpressRestore {
+	return r.WordpressRestore
+}
+
+// Labels returns the labels applied to the restore jobs.
+func (r *Restore) Labels() labels.Set {
+	return labels.Set{
+		"app.kubernetes.io/name":      "wordpress",
+		"app.kubernetes.io/instance":  r.Spec.WordpressRef.Name,
+		"app.kubernetes.io/component": "restore",
+		RestoreLabel:                  r.Name,
+	}
+}
+
+// JobName returns the name of the job which restores the site.
+func (r *Restore) JobName() string {
+	return fmt.Sprintf("%s-restore", r.Name)
+}
+
+// GetCondition returns the condition of the given type or nil if the condition is not set.
+func (r *Restore) GetCondition(condType wordpressv1alpha1.WordpressConditionType) *wordpressv1alpha1.WordpressCondition {
+	return wordpress.FindCondition(r.Status.Conditions, condType)
+}
+
+// SetCondition sets the status, reason and message of the given condition
+// type. It returns true if the condition has been changed.
+func (r *Restore) SetCondition(condType wordpressv1alpha1.WordpressConditionType,
+	status corev1.ConditionStatus, reason, message string) bool {
+	return wordpress.UpdateCondition(&r.Status.Conditions, condType, status, reason, message)
+}
+
+// IsFailed returns true if the restore failed, even if the site is still
+// being scaled back up.
+func (r *Restore) IsFailed() bool {
+	cond := r.GetCondition(wordpressv1alpha1.RestoreFailedCondition)
+
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// IsFinished returns true if the restore either completed or failed.
+func (r *Restore) IsFinished() bool {
+	return r.Status.Phase == wordpressv1alpha1.RestoreCompleted || r.Status.Phase == wordpressv1alpha1.RestoreFailed
+}
+
+// SearchReplace returns the domain to replace within the database and its
+// replacement or empty strings if no replacement is needed.
+func (r *Restore) SearchReplace(wp *wordpress.Wordpress) (string, string) {
+	if r.Spec.SourceDomain == "" || r.Spec.SourceDomain == wp.MainDomain() {
+		return "", ""
+	}
+
+	return r.Spec.SourceDomain, wp.MainDomain()
+}
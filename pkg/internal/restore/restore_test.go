@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package restore
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/backup"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+var _ = Describe("Restore", func() {
+	var (
+		rs        *Restore
+		wp        *wordpress.Wordpress
+		artifacts *backup.Artifacts
+	)
+
+	BeforeEach(func() {
+		wp = wordpress.New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+			},
+		})
+
+		rs = New(&wordpressv1alpha1.WordpressRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "rollback", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressRestoreSpec{
+				WordpressRef: corev1.LocalObjectReference{Name: "site"},
+			},
+		})
+
+		artifacts = backup.NewArtifacts(&wordpressv1alpha1.BackupDestination{
+			PersistentVolumeClaim: &wordpressv1alpha1.PVCBackupDestination{ClaimName: "backups", PathPrefix: "sites"},
+		}, "default/site/daily")
+	})
+
+	It("replaces the source domain only if it differs from the site's main domain", func() {
+		search, replace := rs.SearchReplace(wp)
+		Expect(search).To(BeEmpty())
+		Expect(replace).To(BeEmpty())
+
+		rs.Spec.SourceDomain = "example.com"
+		search, _ = rs.SearchReplace(wp)
+		Expect(search).To(BeEmpty())
+
+		rs.Spec.SourceDomain = "staging.example.com"
+		search, replace = rs.SearchReplace(wp)
+		Expect(search).To(Equal("staging.example.com"))
+		Expect(replace).To(Equal("example.com"))
+	})
+
+	Describe("job pod template", func() {
+		It("downloads the artifacts with rclone and imports them with wp-cli", func() {
+			rs.Spec.SourceDomain = "staging.example.com"
+			pod := rs.JobPodTemplateSpec(wp, artifacts)
+
+			Expect(pod.Labels).To(HaveKeyWithValue(RestoreLabel, "rollback"))
+
+			download := pod.Spec.InitContainers[len(pod.Spec.InitContainers)-1]
+			Expect(download.Name).To(Equal("download"))
+			Expect(download.Image).To(Equal(options.RcloneImage))
+			Expect(download.Env).To(ContainElement(corev1.EnvVar{Name: "SOURCE", Value: "/mnt/destination/sites/default/site/daily"}))
+			Expect(download.VolumeMounts).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("destination"),
+			})))
+
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			wpCLI := pod.Spec.Containers[0]
+			Expect(wpCLI.Name).To(Equal("wp-cli"))
+			Expect(wpCLI.Image).To(Equal(wp.Spec.Image))
+			Expect(wpCLI.Env).To(ContainElements(
+				corev1.EnvVar{Name: "SEARCH_DOMAIN", Value: "staging.example.com"},
+				corev1.EnvVar{Name: "REPLACE_DOMAIN", Value: "example.com"},
+			))
+			Expect(wpCLI.VolumeMounts).To(ContainElement(backupVolumeMount()))
+
+			Expect(pod.Spec.Volumes).To(ContainElements(
+				MatchFields(IgnoreExtras, Fields{"Name": Equal(backupVolumeName)}),
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("destination")}),
+			))
+		})
+
+		It("copies the media files to the site's bucket", func() {
+			wp.Spec.MediaVolumeSpec = &wordpressv1alpha1.MediaVolumeSpec{
+				S3VolumeSource: &wordpressv1alpha1.S3VolumeSource{Bucket: "media"},
+			}
+
+			download := rs.JobPodTemplateSpec(wp, artifacts).Spec.InitContainers[0]
+			Expect(download.Env).To(ContainElements(
+				corev1.EnvVar{Name: "MEDIA_DESTINATION", Value: "media:media"},
+				corev1.EnvVar{Name: "RCLONE_CONFIG_MEDIA_TYPE", Value: "s3"},
+			))
+
+			rs.Spec.SkipMedia = true
+			download = rs.JobPodTemplateSpec(wp, artifacts).Spec.InitContainers[0]
+			Expect(download.Env).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("MEDIA_DESTINATION"),
+			})))
+		})
+
+		It("extracts the media archive on the site's volume", func() {
+			wp.Spec.MediaVolumeSpec = &wordpressv1alpha1.MediaVolumeSpec{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			}
+			wp.SetDefaults()
+
+			wpCLI := rs.JobPodTemplateSpec(wp, artifacts).Spec.Containers[0]
+			Expect(wpCLI.Env).To(ContainElement(corev1.EnvVar{Name: "RESTORE_MEDIA_DIR", Value: wp.MediaDir()}))
+		})
+	})
+})
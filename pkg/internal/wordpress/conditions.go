@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+// GetCondition returns the condition of the given type or nil if the condition is not set.
+func (wp *Wordpress) GetCondition(condType wordpressv1alpha1.WordpressConditionType) *wordpressv1alpha1.WordpressCondition {
+	return FindCondition(wp.Status.Conditions, condType)
+}
+
+// SetCondition sets the status, reason and message of the given condition
+// type. The transition time is updated only when the status changes. It
+// returns true if the condition has been changed.
+func (wp *Wordpress) SetCondition(condType wordpressv1alpha1.WordpressConditionType,
+	status corev1.ConditionStatus, reason, message string) bool {
+	return UpdateCondition(&wp.Status.Conditions, condType, status, reason, message)
+}
+
+// RemoveCondition removes the condition of the given type. It returns true if
+// the condition was set.
+func (wp *Wordpress) RemoveCondition(condType wordpressv1alpha1.WordpressConditionType) bool {
+	conditions := []wordpressv1alpha1.WordpressCondition{}
+
+	for _, cond := range wp.Status.Conditions {
+		if cond.Type != condType {
+			conditions = append(conditions, cond)
+		}
+	}
+
+	if len(conditions) == len(wp.Status.Conditions) {
+		return false
+	}
+
+	wp.Status.Conditions = conditions
+
+	return true
+}
+
+// FindCondition returns the condition of the given type from conditions or
+// nil if the condition is not set.
+func FindCondition(conditions []wordpressv1alpha1.WordpressCondition,
+	condType wordpressv1alpha1.WordpressConditionType) *wordpressv1alpha1.WordpressCondition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// UpdateCondition sets the status, reason and message of the given condition
+// type within conditions, as SetCondition does.
+func UpdateCondition(conditions *[]wordpressv1alpha1.WordpressCondition, condType wordpressv1alpha1.WordpressConditionType,
+	status corev1.ConditionStatus, reason, message string) bool {
+	now := metav1.Now()
+
+	cond := FindCondition(*conditions, condType)
+	if cond == nil {
+		*conditions = append(*conditions, wordpressv1alpha1.WordpressCondition{
+			Type:               condType,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastUpdateTime:     now,
+			LastTransitionTime: now,
+		})
+
+		return true
+	}
+
+	if cond.Status == status && cond.Reason == reason && cond.Message == message {
+		return false
+	}
+
+	if cond.Status != status {
+		cond.LastTransitionTime = now
+	}
+
+	cond.Status = status
+	cond.Reason = reason
+	cond.Message = message
+	cond.LastUpdateTime = now
+
+	return true
+}
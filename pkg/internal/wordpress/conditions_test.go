@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+var _ = Describe("Wordpress conditions", func() {
+	var wp *Wordpress
+
+	BeforeEach(func() {
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		})
+	})
+
+	It("should add a missing condition", func() {
+		Expect(wp.GetCondition(wordpressv1alpha1.DBUpgradedCondition)).To(BeNil())
+
+		Expect(wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionFalse, "Reason", "message")).To(BeTrue())
+
+		cond := wp.GetCondition(wordpressv1alpha1.DBUpgradedCondition)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal("Reason"))
+		Expect(cond.Message).To(Equal("message"))
+		Expect(cond.LastTransitionTime.IsZero()).To(BeFalse())
+	})
+
+	It("should not change an up to date condition", func() {
+		wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionFalse, "Reason", "message")
+		Expect(wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionFalse, "Reason", "message")).To(BeFalse())
+		Expect(wp.Status.Conditions).To(HaveLen(1))
+	})
+
+	It("should update the transition time only when the status changes", func() {
+		past := metav1.NewTime(metav1.Now().Add(-time.Hour))
+		wp.Status.Conditions = []wordpressv1alpha1.WordpressCondition{
+			{
+				Type:               wordpressv1alpha1.DBUpgradedCondition,
+				Status:             corev1.ConditionFalse,
+				Reason:             "Reason",
+				LastUpdateTime:     past,
+				LastTransitionTime: past,
+			},
+		}
+
+		Expect(wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionFalse, "Other", "message")).To(BeTrue())
+		cond := wp.GetCondition(wordpressv1alpha1.DBUpgradedCondition)
+		Expect(cond.LastTransitionTime).To(Equal(past))
+		Expect(cond.LastUpdateTime).ToNot(Equal(past))
+
+		Expect(wp.SetCondition(wordpressv1alpha1.DBUpgradedCondition, corev1.ConditionTrue, "Other", "message")).To(BeTrue())
+		Expect(cond.LastTransitionTime).ToNot(Equal(past))
+	})
+})
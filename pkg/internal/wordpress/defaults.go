@@ -17,11 +17,14 @@ limitations under the License.
 package wordpress
 
 import (
+	"net/http"
 	"path"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
 	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
 )
 
@@ -42,6 +45,8 @@ const (
 
 	knativeInternalVolume    = "knative-internal"
 	knativeInternalMountPath = "/var/knative-internal"
+
+	defaultCronSchedule = "* * * * *"
 )
 
 var varLogSizeLimit = resource.MustParse("1Gi")
@@ -79,4 +84,34 @@ func (wp *Wordpress) SetDefaults() {
 	if wp.Spec.WordpressPathPrefix == "" {
 		wp.Spec.WordpressPathPrefix = "/wp"
 	}
+
+	for i := range wp.Spec.Redirects {
+		if wp.Spec.Redirects[i].StatusCode == 0 {
+			wp.Spec.Redirects[i].StatusCode = http.StatusMovedPermanently
+		}
+	}
+
+	wp.setCronDefaults()
+}
+
+func (wp *Wordpress) setCronDefaults() {
+	if wp.Spec.Cron == nil {
+		wp.Spec.Cron = &wordpressv1alpha1.CronSpec{}
+	}
+
+	if wp.Spec.Cron.Mode == "" {
+		wp.Spec.Cron.Mode = wordpressv1alpha1.CronModeHTTP
+	}
+
+	if wp.Spec.Cron.Schedule == "" {
+		wp.Spec.Cron.Schedule = defaultCronSchedule
+	}
+
+	if wp.Spec.Cron.ConcurrencyPolicy == "" {
+		wp.Spec.Cron.ConcurrencyPolicy = batchv1.ForbidConcurrent
+	}
+
+	if wp.Spec.Cron.HTTP != nil && wp.Spec.Cron.HTTP.RedirectPolicy == "" {
+		wp.Spec.Cron.HTTP.RedirectPolicy = wordpressv1alpha1.CronRedirectFollow
+	}
 }
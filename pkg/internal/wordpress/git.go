@@ -0,0 +1,157 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+const (
+	// CodeRevisionAnnotation is set on the web pods, holding the commit of
+	// the code cloned from git.
+	CodeRevisionAnnotation = "wordpress.presslabs.org/code-revision"
+
+	// GitRefAnnotation is set on the jobs resolving the git reference of the
+	// code, holding the resolved reference.
+	GitRefAnnotation = "wordpress.presslabs.org/git-ref"
+
+	// GitPushAnnotation is set on sites by the git webhook receiver, holding
+	// the time of the last push to the git reference of the code. It's copied
+	// on the jobs resolving the reference, which are started after the push.
+	GitPushAnnotation = "wordpress.presslabs.org/git-push"
+)
+
+// gitResolveScript prints the commit of the $GIT_CLONE_REF reference into the
+// termination message of the container. Branches take precedence over tags,
+// and annotated tags resolve to the commit they point to.
+const gitResolveScript = gitSetupScript + `
+ref="${GIT_CLONE_REF:-HEAD}"
+
+if echo "$ref" | grep -Eq '^[0-9a-f]{40}$' ; then
+    echo -n "$ref" > /dev/termination-log
+    exit 0
+fi
+
+set -x
+refs="$(git ls-remote "$GIT_CLONE_URL")"
+set +x
+
+for candidate in "refs/heads/$ref" "refs/tags/$ref^{}" "refs/tags/$ref" "$ref" ; do
+    revision="$(echo "$refs" | awk -v ref="$candidate" '$2 == ref { print $1 }')"
+    if [ -n "$revision" ] ; then
+        echo "$ref resolved to $revision"
+        echo -n "$revision" > /dev/termination-log
+        exit 0
+    fi
+done
+
+echo "Reference $ref not found in $GIT_CLONE_URL" >&2
+exit 1
+`
+
+// hasGitCode returns true if the code is cloned from git.
+func (wp *Wordpress) hasGitCode() bool {
+	return wp.Spec.CodeVolumeSpec != nil && wp.Spec.CodeVolumeSpec.GitDir != nil
+}
+
+// GitResolveInterval returns the interval at which the git reference of the
+// code is resolved to a commit, or 0 if it's not resolved.
+func (wp *Wordpress) GitResolveInterval() time.Duration {
+	if !wp.hasGitCode() {
+		return 0
+	}
+
+	if wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval != nil {
+		return wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval.Duration
+	}
+
+	return options.GitResolveInterval
+}
+
+// CodeRevision returns the commit cloned by the site's pods, or an empty
+// string if the pods clone the git reference as it is when they start.
+func (wp *Wordpress) CodeRevision() string {
+	if wp.GitResolveInterval() <= 0 || wp.Status.Code == nil {
+		return ""
+	}
+
+	// the revision was resolved for another reference
+	if wp.Status.Code.Reference != wp.Spec.CodeVolumeSpec.GitDir.GitRef {
+		return ""
+	}
+
+	return wp.Status.Code.Revision
+}
+
+// LastGitPush returns the time of the last push to the git reference of the
+// code received by the webhook receiver, or the zero time if none was received.
+func (wp *Wordpress) LastGitPush() time.Time {
+	t, err := time.Parse(time.RFC3339Nano, wp.Annotations[GitPushAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// GitResolvePodTemplateSpec generates the pod template spec of the jobs
+// resolving the git reference of the code.
+func (wp *Wordpress) GitResolvePodTemplateSpec() (out corev1.PodTemplateSpec) {
+	out = corev1.PodTemplateSpec{}
+
+	if wp.Spec.PodMetadata != nil {
+		wp.Spec.PodMetadata.DeepCopyInto(&out.ObjectMeta)
+	}
+
+	out.ObjectMeta.Labels = labels.Merge(out.ObjectMeta.Labels, wp.JobPodLabels())
+
+	out.Spec.ImagePullSecrets = wp.Spec.ImagePullSecrets
+	if len(wp.Spec.ServiceAccountName) > 0 {
+		out.Spec.ServiceAccountName = wp.Spec.ServiceAccountName
+	}
+
+	out.Spec.RestartPolicy = corev1.RestartPolicyNever
+
+	out.Spec.Containers = []corev1.Container{
+		{
+			Name:                     "git",
+			Args:                     []string{"/bin/bash", "-c", gitResolveScript},
+			Image:                    options.GitCloneImage,
+			Env:                      wp.gitCloneEnv(),
+			EnvFrom:                  wp.Spec.CodeVolumeSpec.GitDir.EnvFrom,
+			SecurityContext:          wp.securityContext(),
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+	}
+
+	if len(wp.Spec.NodeSelector) > 0 {
+		out.Spec.NodeSelector = wp.Spec.NodeSelector
+	}
+
+	if len(wp.Spec.Tolerations) > 0 {
+		out.Spec.Tolerations = wp.Spec.Tolerations
+	}
+
+	out.Spec.Affinity = wp.Spec.Affinity
+
+	return out
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+var _ = Describe("Git code revision", func() {
+	var wp *Wordpress
+
+	revision := "0123456789abcdef0123456789abcdef01234567"
+
+	BeforeEach(func() {
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				CodeVolumeSpec: &wordpressv1alpha1.CodeVolumeSpec{
+					GitDir: &wordpressv1alpha1.GitVolumeSource{
+						Repository: "https://github.com/bitpoke/stack-example-wordpress.git",
+						GitRef:     "main",
+					},
+				},
+			},
+			Status: wordpressv1alpha1.WordpressStatus{
+				Code: &wordpressv1alpha1.CodeStatus{Reference: "main", Revision: revision},
+			},
+		})
+		wp.SetDefaults()
+	})
+
+	gitEnv := func(pod corev1.PodTemplateSpec) []corev1.EnvVar {
+		for _, c := range pod.Spec.InitContainers {
+			if c.Name == "git" {
+				return c.Env
+			}
+		}
+
+		return nil
+	}
+
+	It("should resolve the reference at the default interval", func() {
+		Expect(wp.GitResolveInterval()).To(Equal(options.GitResolveInterval))
+
+		wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval = &metav1.Duration{Duration: time.Hour}
+		Expect(wp.GitResolveInterval()).To(Equal(time.Hour))
+
+		wp.Spec.CodeVolumeSpec = nil
+		Expect(wp.GitResolveInterval()).To(BeZero())
+	})
+
+	It("should clone the resolved revision on all pods", func() {
+		pod := wp.WebPodTemplateSpec()
+		Expect(pod.Annotations).To(HaveKeyWithValue(CodeRevisionAnnotation, revision))
+		Expect(gitEnv(pod)).To(ContainElement(corev1.EnvVar{Name: "GIT_CLONE_REVISION", Value: revision}))
+
+		Expect(gitEnv(wp.JobPodTemplateSpec())).To(ContainElement(corev1.EnvVar{Name: "GIT_CLONE_REVISION", Value: revision}))
+	})
+
+	It("should clone the reference when it's not resolved", func() {
+		wp.Spec.CodeVolumeSpec.GitDir.GitRef = "release"
+		Expect(wp.CodeRevision()).To(BeEmpty())
+
+		wp.Spec.CodeVolumeSpec.GitDir.GitRef = "main"
+		wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval = &metav1.Duration{}
+		Expect(wp.CodeRevision()).To(BeEmpty())
+
+		pod := wp.WebPodTemplateSpec()
+		Expect(pod.Annotations).NotTo(HaveKey(CodeRevisionAnnotation))
+		for _, env := range gitEnv(pod) {
+			Expect(env.Name).NotTo(Equal("GIT_CLONE_REVISION"))
+		}
+	})
+
+	It("should restart the pods on pushes when the reference is not resolved", func() {
+		wp.Annotations = map[string]string{GitPushAnnotation: "2026-10-18T10:00:00Z"}
+		Expect(wp.LastGitPush()).To(Equal(time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)))
+		Expect(wp.WebPodTemplateSpec().Annotations).NotTo(HaveKey(GitPushAnnotation))
+
+		wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval = &metav1.Duration{}
+		Expect(wp.WebPodTemplateSpec().Annotations).To(HaveKeyWithValue(GitPushAnnotation, "2026-10-18T10:00:00Z"))
+	})
+
+	It("should generate the resolve job pod", func() {
+		pod := wp.GitResolvePodTemplateSpec()
+
+		Expect(pod.Labels).To(Equal(map[string]string(wp.JobPodLabels())))
+		Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		Expect(pod.Spec.Containers[0].Image).To(Equal(options.GitCloneImage))
+		Expect(pod.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "GIT_CLONE_REF", Value: "main"}))
+	})
+})
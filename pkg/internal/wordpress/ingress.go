@@ -0,0 +1,128 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"strings"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+// IngressGroupSpec returns the spec.ingress.groups entry with the given name,
+// or nil if there's none.
+func (wp *Wordpress) IngressGroupSpec(name string) *wordpressv1alpha1.IngressGroup {
+	if wp.Spec.Ingress == nil || name == "" {
+		return nil
+	}
+
+	for i := range wp.Spec.Ingress.Groups {
+		if wp.Spec.Ingress.Groups[i].Name == name {
+			return &wp.Spec.Ingress.Groups[i]
+		}
+	}
+
+	return nil
+}
+
+// RouteIngressGroup returns the ingress group of a route, or an empty string
+// for the routes exposed by the site's Ingress, including the ones referencing
+// unknown groups.
+func (wp *Wordpress) RouteIngressGroup(route wordpressv1alpha1.RouteSpec) string {
+	if wp.IngressGroupSpec(route.IngressGroup) == nil {
+		return ""
+	}
+
+	return route.IngressGroup
+}
+
+// IngressName returns the name of the Ingress of an ingress group of the site.
+func (wp *Wordpress) IngressName(group string) string {
+	if group == "" {
+		return wp.ComponentName(WordpressIngress)
+	}
+
+	return wp.ComponentName(WordpressIngress) + "-" + group
+}
+
+// IngressGroups returns the ingress groups of the site which expose active
+// routes, in order. The site's Ingress is the empty string group.
+func (wp *Wordpress) IngressGroups() []string {
+	groups := []string{}
+	seen := map[string]bool{}
+
+	for _, route := range wp.ActiveRoutes() {
+		group := wp.RouteIngressGroup(route)
+		if seen[group] || wp.IsRedirected(route.Domain) {
+			continue
+		}
+
+		seen[group] = true
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// DomainIngressGroup returns the ingress group of the first route of a domain.
+func (wp *Wordpress) DomainIngressGroup(domain string) string {
+	for _, route := range wp.ActiveRoutes() {
+		if strings.EqualFold(route.Domain, domain) {
+			return wp.RouteIngressGroup(route)
+		}
+	}
+
+	return ""
+}
+
+// IngressClassName returns the class of the Ingress of an ingress group, or
+// nil to use the cluster's default class.
+func (wp *Wordpress) IngressClassName(group string) *string {
+	className := options.IngressClass
+
+	if wp.Spec.Ingress != nil && wp.Spec.Ingress.IngressClassName != "" {
+		className = wp.Spec.Ingress.IngressClassName
+	}
+
+	if spec := wp.IngressGroupSpec(group); spec != nil && spec.IngressClassName != "" {
+		className = spec.IngressClassName
+	}
+
+	if className == "" {
+		return nil
+	}
+
+	return &className
+}
+
+// IngressAnnotations returns the annotations of the Ingress of an ingress
+// group, which are the group's annotations merged over the site's.
+func (wp *Wordpress) IngressAnnotations(group string) map[string]string {
+	annotations := map[string]string{}
+
+	for k, v := range wp.Spec.IngressAnnotations {
+		annotations[k] = v
+	}
+
+	if spec := wp.IngressGroupSpec(group); spec != nil {
+		for k, v := range spec.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	return annotations
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+var _ = Describe("Wordpress ingress groups", func() {
+	var (
+		wp           *Wordpress
+		ingressClass string
+	)
+
+	BeforeEach(func() {
+		ingressClass = options.IngressClass
+		options.IngressClass = "nginx"
+
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "test.com"},
+					{Domain: "admin.test.com", IngressGroup: "internal"},
+					{Domain: "test.com", Path: "/wp-admin", IngressGroup: "internal"},
+					{Domain: "old.test.com", IngressGroup: "unknown"},
+				},
+				TLSSecretRef:       "test-tls",
+				IngressAnnotations: map[string]string{"a": "site", "b": "site"},
+				Ingress: &wordpressv1alpha1.IngressSpec{
+					Groups: []wordpressv1alpha1.IngressGroup{
+						{
+							Name:             "internal",
+							IngressClassName: "nginx-internal",
+							Annotations:      map[string]string{"b": "internal"},
+							TLSSecretRef:     "internal-tls",
+						},
+					},
+				},
+			},
+		})
+	})
+
+	AfterEach(func() {
+		options.IngressClass = ingressClass
+	})
+
+	It("should group the routes by their ingress group", func() {
+		Expect(wp.IngressGroups()).To(Equal([]string{"", "internal"}))
+		Expect(wp.IngressName("")).To(Equal("test"))
+		Expect(wp.IngressName("internal")).To(Equal("test-internal"))
+		Expect(wp.DomainIngressGroup("Admin.test.com")).To(Equal("internal"))
+		Expect(wp.DomainIngressGroup("old.test.com")).To(Equal(""))
+	})
+
+	It("should set the class and the annotations of the group's Ingress", func() {
+		Expect(*wp.IngressClassName("")).To(Equal("nginx"))
+		Expect(*wp.IngressClassName("internal")).To(Equal("nginx-internal"))
+		Expect(wp.IngressAnnotations("")).To(Equal(map[string]string{"a": "site", "b": "site"}))
+		Expect(wp.IngressAnnotations("internal")).To(Equal(map[string]string{"a": "site", "b": "internal"}))
+
+		wp.Spec.Ingress.IngressClassName = "public"
+		Expect(*wp.IngressClassName("")).To(Equal("public"))
+
+		options.IngressClass = ""
+		wp.Spec.Ingress.IngressClassName = ""
+		Expect(wp.IngressClassName("")).To(BeNil())
+	})
+
+	It("should use the TLS secret of the group for its routes", func() {
+		Expect(wp.TLSGroups()).To(Equal([]TLSGroup{
+			{SecretName: "test-tls", Domains: []string{"test.com", "old.test.com"}},
+			{SecretName: "internal-tls", Domains: []string{"admin.test.com"}},
+		}))
+	})
+})
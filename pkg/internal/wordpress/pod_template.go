@@ -43,7 +43,9 @@ const (
 	prepareVolumesImage = "gcr.io/google-containers/busybox@sha256:545e6a6310a27636260920bc07b994a299b6708a1b26910cfefd335fdfb60d2b"
 )
 
-const gitCloneScript = `#!/bin/bash
+// gitSetupScript configures the ssh credentials used by git and checks that
+// a repository is set.
+const gitSetupScript = `#!/bin/bash
 set -e
 set -o pipefail
 
@@ -62,13 +64,21 @@ if [ -z "$GIT_CLONE_URL" ] ; then
     echo "No \$GIT_CLONE_URL specified" >&2
     exit 1
 fi
+`
 
+const gitCloneScript = gitSetupScript + `
 find "$SRC_DIR" -maxdepth 1 -mindepth 1 -print0 | xargs -0 /bin/rm -rf
 
 set -x
 git clone "$GIT_CLONE_URL" "$SRC_DIR"
 cd "$SRC_DIR"
-git checkout -B "$GIT_CLONE_REF" "origin/$GIT_CLONE_REF"
+if [ -n "$GIT_CLONE_REVISION" ] && [ -n "$GIT_CLONE_REF" ] ; then
+    git checkout -B "$GIT_CLONE_REF" "$GIT_CLONE_REVISION"
+elif [ -n "$GIT_CLONE_REVISION" ] ; then
+    git checkout --detach "$GIT_CLONE_REVISION"
+else
+    git checkout -B "$GIT_CLONE_REF" "origin/$GIT_CLONE_REF"
+fi
 `
 
 const prepareVolumesScriptTpl = `#!/bin/sh
@@ -389,11 +399,19 @@ func (wp *Wordpress) securityContext() *corev1.SecurityContext {
 }
 
 func (wp *Wordpress) gitCloneContainer() corev1.Container {
+	env := wp.gitCloneEnv()
+	if revision := wp.CodeRevision(); revision != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "GIT_CLONE_REVISION",
+			Value: revision,
+		})
+	}
+
 	return corev1.Container{
 		Name:    "git",
 		Args:    []string{"/bin/bash", "-c", gitCloneScript},
 		Image:   options.GitCloneImage,
-		Env:     wp.gitCloneEnv(),
+		Env:     env,
 		EnvFrom: wp.Spec.CodeVolumeSpec.GitDir.EnvFrom,
 		VolumeMounts: []corev1.VolumeMount{
 			{
@@ -590,6 +608,18 @@ func (wp *Wordpress) WebPodTemplateSpec() (out corev1.PodTemplateSpec) {
 
 	out.ObjectMeta.Labels = labels.Merge(out.ObjectMeta.Labels, wp.WebPodLabels())
 
+	if revision := wp.CodeRevision(); revision != "" {
+		out.ObjectMeta.Annotations = labels.Merge(out.ObjectMeta.Annotations, map[string]string{
+			CodeRevisionAnnotation: revision,
+		})
+	} else if push := wp.Annotations[GitPushAnnotation]; push != "" && wp.hasGitCode() {
+		// the pods clone the git reference when they start, so they get
+		// restarted on pushes
+		out.ObjectMeta.Annotations = labels.Merge(out.ObjectMeta.Annotations, map[string]string{
+			GitPushAnnotation: push,
+		})
+	}
+
 	out.Spec.ImagePullSecrets = wp.Spec.ImagePullSecrets
 	if len(wp.Spec.ServiceAccountName) > 0 {
 		out.Spec.ServiceAccountName = wp.Spec.ServiceAccountName
@@ -710,6 +740,33 @@ func (wp *Wordpress) JobPodTemplateSpec(cmd ...string) (out corev1.PodTemplateSp
 	return out
 }
 
+// CronJobPodTemplateSpec generates the pod template spec of the wp-cron
+// CronJob, which runs the due wp-cron events with wp-cli.
+func (wp *Wordpress) CronJobPodTemplateSpec() corev1.PodTemplateSpec {
+	out := wp.JobPodTemplateSpec("wp", "cron", "event", "run", "--due-now")
+	out.ObjectMeta.Labels = labels.Merge(out.ObjectMeta.Labels, wp.ComponentLabels(WordpressCron))
+
+	// the sidecars are dropped, otherwise they would keep the jobs running
+	container := out.Spec.Containers[0]
+	if wp.Spec.Cron != nil {
+		container.Resources = wp.Spec.Cron.Resources
+	}
+
+	out.Spec.Containers = []corev1.Container{container}
+
+	return out
+}
+
+// MediaDir returns the path at which media files are mounted within the
+// wordpress containers or an empty string if media is not stored on a volume.
+func (wp *Wordpress) MediaDir() string {
+	if !wp.hasMediaMounts() {
+		return ""
+	}
+
+	return wp.Spec.MediaVolumeSpec.MountPath
+}
+
 func (wp *Wordpress) hasMediaMounts() bool {
 	if wp.Spec.MediaVolumeSpec == nil {
 		return false
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -260,6 +261,20 @@ var _ = Describe("Web pod spec", func() {
 		Expect(*spec.Spec.Containers[0].LivenessProbe).To(Equal(probe))
 	})
 
+	It("should run the due wp-cron events in the wp-cron job", func() {
+		wp.Spec.Sidecars = []corev1.Container{{Name: "proxy", Image: "proxy"}}
+		wp.Spec.Cron.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		}
+
+		spec := wp.CronJobPodTemplateSpec()
+
+		Expect(spec.Labels).To(HaveKeyWithValue("app.kubernetes.io/component", "cron"))
+		Expect(spec.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		Expect(spec.Spec.Containers).To(HaveLen(1))
+		Expect(spec.Spec.Containers[0].Args).To(Equal([]string{"wp", "cron", "event", "run", "--due-now"}))
+		Expect(spec.Spec.Containers[0].Resources).To(Equal(wp.Spec.Cron.Resources))
+	})
 })
 
 // nolint: unparam
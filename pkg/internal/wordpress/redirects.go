@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Redirect is a redirect of the requests for a domain and path prefix, as
+// rendered into the routing layer.
+type Redirect struct {
+	Host string
+	Path string
+	// Target is the URL or the absolute path the requests are redirected to
+	Target *url.URL
+	// KeepPath is set if the path of the requests is appended to the target
+	KeepPath   bool
+	StatusCode int
+}
+
+// RedirectName returns the name of the objects rendering a redirect of the
+// site, eg. ingresses.
+func (wp *Wordpress) RedirectName(r Redirect) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(r.Host + r.Path))
+
+	return fmt.Sprintf("%s-%08x", wp.ComponentName(WordpressRedirect), h.Sum32())
+}
+
+// IsRedirected returns true if all the requests for the domain are redirected
+// to the canonical host.
+func (wp *Wordpress) IsRedirected(domain string) bool {
+	return wp.Spec.CanonicalHost != "" && !strings.EqualFold(domain, wp.Spec.CanonicalHost)
+}
+
+// Redirects returns the redirects of the site for the domains of the active
+// routes, including the ones to the canonical host. Redirects with invalid
+// targets are skipped.
+func (wp *Wordpress) Redirects() []Redirect {
+	redirects := []Redirect{}
+	active := map[string]bool{}
+
+	for _, domain := range wp.ActiveDomains() {
+		active[domain] = true
+	}
+
+	for _, spec := range wp.Spec.Redirects {
+		host := strings.ToLower(spec.Host)
+
+		target, err := url.Parse(spec.Target)
+		if !active[host] || err != nil {
+			continue
+		}
+
+		code := int(spec.StatusCode)
+		if code == 0 {
+			code = http.StatusMovedPermanently
+		}
+
+		redirects = append(redirects, Redirect{
+			Host:       host,
+			Path:       path.Clean("/" + spec.Path),
+			Target:     target,
+			StatusCode: code,
+		})
+	}
+
+	if wp.Spec.CanonicalHost == "" {
+		return redirects
+	}
+
+	canonical := &url.URL{Scheme: "http", Host: strings.ToLower(wp.Spec.CanonicalHost)}
+	if wp.ServesHTTPS() {
+		canonical.Scheme = "https"
+	}
+
+	for _, domain := range wp.ActiveDomains() {
+		if wp.IsRedirected(domain) {
+			redirects = append(redirects, Redirect{
+				Host:       domain,
+				Path:       "/",
+				Target:     canonical,
+				KeepPath:   true,
+				StatusCode: http.StatusMovedPermanently,
+			})
+		}
+	}
+
+	return redirects
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+var _ = Describe("Wordpress redirects", func() {
+	var wp *Wordpress
+
+	BeforeEach(func() {
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "test.com"},
+					{Domain: "www.test.com"},
+				},
+				Redirects: []wordpressv1alpha1.RedirectSpec{
+					{Host: "Test.com", Path: "/old/", Target: "/new"},
+					{Host: "test.com", Path: "/shop", Target: "https://shop.test.com", StatusCode: 302},
+					{Host: "unknown.com", Path: "/", Target: "https://test.com"},
+				},
+			},
+		})
+	})
+
+	It("should return the redirects of the active domains", func() {
+		Expect(wp.Redirects()).To(Equal([]Redirect{
+			{Host: "test.com", Path: "/old", Target: &url.URL{Path: "/new"}, StatusCode: 301},
+			{Host: "test.com", Path: "/shop", Target: &url.URL{Scheme: "https", Host: "shop.test.com"}, StatusCode: 302},
+		}))
+		Expect(wp.IsRedirected("www.test.com")).To(BeFalse())
+	})
+
+	It("should redirect the other domains to the canonical host", func() {
+		wp.Spec.Redirects = nil
+		wp.Spec.CanonicalHost = "test.com"
+		wp.Spec.TLSSecretRef = "test-tls"
+
+		Expect(wp.IsRedirected("test.com")).To(BeFalse())
+		Expect(wp.IsRedirected("www.test.com")).To(BeTrue())
+		Expect(wp.Redirects()).To(Equal([]Redirect{
+			{Host: "www.test.com", Path: "/", Target: &url.URL{Scheme: "https", Host: "test.com"}, KeepPath: true, StatusCode: 301},
+		}))
+	})
+
+	It("should name the redirects after their host and path", func() {
+		redirects := wp.Redirects()
+
+		Expect(wp.RedirectName(redirects[0])).To(HavePrefix("test-redirect-"))
+		Expect(wp.RedirectName(redirects[0])).NotTo(Equal(wp.RedirectName(redirects[1])))
+	})
+})
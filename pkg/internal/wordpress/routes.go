@@ -0,0 +1,144 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path"
+	"strings"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+// RouteKey returns the normalized domain and path pair of a route, used to
+// detect routes which are served by the same ingress rule.
+func RouteKey(route wordpressv1alpha1.RouteSpec) string {
+	return strings.ToLower(route.Domain) + path.Clean("/"+route.Path)
+}
+
+// RouteKeys returns the keys of all the routes claimed by the site.
+func (wp *Wordpress) RouteKeys() []string {
+	keys := make([]string, len(wp.Spec.Routes))
+	for i := range wp.Spec.Routes {
+		keys[i] = RouteKey(wp.Spec.Routes[i])
+	}
+
+	return keys
+}
+
+// ActiveRoutes returns the routes for which ingress rules get created, which
+// are all the routes not claimed first by other sites.
+func (wp *Wordpress) ActiveRoutes() []wordpressv1alpha1.RouteSpec {
+	if len(wp.Status.ConflictingRoutes) == 0 {
+		return wp.Spec.Routes
+	}
+
+	conflicting := map[string]bool{}
+	for _, route := range wp.Status.ConflictingRoutes {
+		conflicting[RouteKey(route)] = true
+	}
+
+	routes := []wordpressv1alpha1.RouteSpec{}
+
+	for _, route := range wp.Spec.Routes {
+		if !conflicting[RouteKey(route)] {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
+}
+
+// ClaimsRoutesBefore returns true if the routes shared with other are owned by
+// the site. Routes belong to the oldest site claiming them, ties being broken
+// by namespace and name. Sites which are being deleted release their routes.
+func (wp *Wordpress) ClaimsRoutesBefore(other *Wordpress) bool {
+	if wp.DeletionTimestamp != nil || (wp.Namespace == other.Namespace && wp.Name == other.Name) {
+		return false
+	}
+
+	if !wp.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return wp.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+
+	if wp.Namespace != other.Namespace {
+		return wp.Namespace < other.Namespace
+	}
+
+	return wp.Name < other.Name
+}
+
+// ActiveDomains returns the lower cased domains of the active routes, in
+// order and without duplicates.
+func (wp *Wordpress) ActiveDomains() []string {
+	domains := []string{}
+	seen := map[string]bool{}
+
+	for _, route := range wp.ActiveRoutes() {
+		domain := strings.ToLower(route.Domain)
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// RoutingMode returns the way the routes of the site are exposed.
+func (wp *Wordpress) RoutingMode() wordpressv1alpha1.RoutingMode {
+	if wp.Spec.Routing != nil && wp.Spec.Routing.Mode != "" {
+		return wp.Spec.Routing.Mode
+	}
+
+	return wordpressv1alpha1.RoutingMode(options.RoutingMode)
+}
+
+// Gateway returns the Gateway the HTTPRoutes of the site are attached to, in
+// gateway mode, or nil if none is set.
+func (wp *Wordpress) Gateway() *wordpressv1alpha1.GatewayReference {
+	gw := &wordpressv1alpha1.GatewayReference{}
+
+	switch {
+	case wp.Spec.Routing != nil && wp.Spec.Routing.Gateway != nil:
+		wp.Spec.Routing.Gateway.DeepCopyInto(gw)
+	case options.Gateway != "":
+		if i := strings.Index(options.Gateway, "/"); i >= 0 {
+			gw.Namespace, gw.Name = options.Gateway[:i], options.Gateway[i+1:]
+		} else {
+			gw.Name = options.Gateway
+		}
+	default:
+		return nil
+	}
+
+	if gw.Namespace == "" {
+		gw.Namespace = wp.Namespace
+	}
+
+	return gw
+}
+
+// HTTPRouteName returns the name of the HTTPRoute for a domain of the site.
+func (wp *Wordpress) HTTPRouteName(domain string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strings.ToLower(domain)))
+
+	return fmt.Sprintf("%s-%08x", wp.ComponentName(WordpressHTTPRoute), h.Sum32())
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+var _ = Describe("Wordpress routes", func() {
+	var (
+		wp, other *Wordpress
+		created   metav1.Time
+	)
+
+	BeforeEach(func() {
+		created = metav1.NewTime(time.Now().Truncate(time.Second))
+
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", CreationTimestamp: created},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "test.com"},
+					{Domain: "test.com", Path: "/blog/"},
+					{Domain: "test.org", Path: "/"},
+				},
+			},
+		})
+		other = New(wp.Unwrap().DeepCopy())
+		other.Name = "other"
+	})
+
+	It("should normalize route keys", func() {
+		Expect(wp.RouteKeys()).To(Equal([]string{"test.com/", "test.com/blog", "test.org/"}))
+		Expect(RouteKey(wordpressv1alpha1.RouteSpec{Domain: "Test.COM", Path: "blog"})).To(Equal("test.com/blog"))
+	})
+
+	It("should exclude conflicting routes from the active ones", func() {
+		Expect(wp.ActiveRoutes()).To(Equal(wp.Spec.Routes))
+
+		wp.Status.ConflictingRoutes = []wordpressv1alpha1.RouteSpec{{Domain: "test.com", Path: "/blog"}}
+		Expect(wp.ActiveRoutes()).To(Equal([]wordpressv1alpha1.RouteSpec{
+			{Domain: "test.com"},
+			{Domain: "test.org", Path: "/"},
+		}))
+	})
+
+	It("should give routes to the oldest site", func() {
+		other.CreationTimestamp = metav1.NewTime(created.Add(time.Second))
+
+		Expect(wp.ClaimsRoutesBefore(other)).To(BeTrue())
+		Expect(other.ClaimsRoutesBefore(wp)).To(BeFalse())
+	})
+
+	It("should break ties by namespace and name", func() {
+		Expect(other.ClaimsRoutesBefore(wp)).To(BeTrue())
+		Expect(wp.ClaimsRoutesBefore(other)).To(BeFalse())
+
+		other.Namespace = "other"
+		Expect(wp.ClaimsRoutesBefore(other)).To(BeTrue())
+	})
+
+	It("should release routes of sites being deleted", func() {
+		other.DeletionTimestamp = &created
+
+		Expect(other.ClaimsRoutesBefore(wp)).To(BeFalse())
+		Expect(wp.ClaimsRoutesBefore(wp)).To(BeFalse())
+	})
+
+	It("should list the active domains once", func() {
+		wp.Spec.Routes = append(wp.Spec.Routes, wordpressv1alpha1.RouteSpec{Domain: "Test.org", Path: "/shop"})
+		Expect(wp.ActiveDomains()).To(Equal([]string{"test.com", "test.org"}))
+	})
+
+	It("should resolve the gateway of the site", func() {
+		defer func(gateway string) { options.Gateway = gateway }(options.Gateway)
+
+		options.Gateway = ""
+		Expect(wp.Gateway()).To(BeNil())
+
+		options.Gateway = "infra/public"
+		Expect(wp.Gateway()).To(Equal(&wordpressv1alpha1.GatewayReference{Name: "public", Namespace: "infra"}))
+
+		wp.Spec.Routing = &wordpressv1alpha1.RoutingSpec{
+			Gateway: &wordpressv1alpha1.GatewayReference{Name: "internal", SectionName: "https"},
+		}
+		Expect(wp.Gateway()).To(Equal(&wordpressv1alpha1.GatewayReference{Name: "internal", Namespace: "default", SectionName: "https"}))
+	})
+
+	It("should name HTTPRoutes after the domain", func() {
+		Expect(wp.HTTPRouteName("test.com")).To(HavePrefix("test-"))
+		Expect(wp.HTTPRouteName("test.com")).To(Equal(wp.HTTPRouteName("TEST.com")))
+		Expect(wp.HTTPRouteName("test.com")).NotTo(Equal(wp.HTTPRouteName("test.org")))
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+// TLSGroup is a TLS secret along with the domains it holds the certificate for.
+type TLSGroup struct {
+	SecretName string
+	Domains    []string
+	// Managed is true if the certificate is issued with cert-manager, which
+	// is the case only for the default secret of the site.
+	Managed bool
+}
+
+// UsesCertManager returns true if the TLS certificates of the site are issued
+// with cert-manager.
+func (wp *Wordpress) UsesCertManager() bool {
+	return wp.Spec.CertManager != nil
+}
+
+// RouteTLSSecret returns the name of the secret holding the TLS certificate
+// of a route, or an empty string if the route is served only over http.
+func (wp *Wordpress) RouteTLSSecret(route wordpressv1alpha1.RouteSpec) string {
+	secret, _ := wp.routeTLSSecret(route)
+
+	return secret
+}
+
+// routeTLSSecret returns the TLS secret of a route and whether it is the
+// default secret, whose certificate is issued with cert-manager.
+func (wp *Wordpress) routeTLSSecret(route wordpressv1alpha1.RouteSpec) (string, bool) {
+	group := wp.IngressGroupSpec(route.IngressGroup)
+
+	switch {
+	case len(route.TLSSecretRef) > 0:
+		return string(route.TLSSecretRef), false
+	case group != nil && len(group.TLSSecretRef) > 0:
+		return string(group.TLSSecretRef), false
+	case len(wp.Spec.TLSSecretRef) > 0:
+		return string(wp.Spec.TLSSecretRef), false
+	case wp.UsesCertManager():
+		return wp.ComponentName(WordpressTLSSecret), true
+	}
+
+	return "", false
+}
+
+// TLSGroups returns the TLS secrets of the active routes, in order, along
+// with the domains they hold the certificates for. Domains with multiple
+// routes use the secret of their first route. The secrets supplied by the
+// user are never managed, even with cert-manager.
+func (wp *Wordpress) TLSGroups() []TLSGroup {
+	groups := []TLSGroup{}
+	index := map[string]int{}
+	seen := map[string]bool{}
+
+	for _, route := range wp.ActiveRoutes() {
+		domain := strings.ToLower(route.Domain)
+		if seen[domain] {
+			continue
+		}
+
+		seen[domain] = true
+
+		secret, managed := wp.routeTLSSecret(route)
+		if secret == "" {
+			continue
+		}
+
+		i, ok := index[secret]
+		if !ok {
+			i = len(groups)
+			index[secret] = i
+			groups = append(groups, TLSGroup{SecretName: secret, Managed: managed})
+		}
+
+		groups[i].Domains = append(groups[i].Domains, domain)
+	}
+
+	return groups
+}
+
+// ServesHTTPS returns true if the site is served over https on its main
+// domain. With cert-manager, that's once the certificates are issued.
+func (wp *Wordpress) ServesHTTPS() bool {
+	if len(wp.Spec.Routes) == 0 {
+		return len(wp.Spec.TLSSecretRef) > 0
+	}
+
+	if wp.RouteTLSSecret(wp.Spec.Routes[0]) == "" {
+		return false
+	}
+
+	if wp.UsesCertManager() {
+		cond := wp.GetCondition(wordpressv1alpha1.TLSReadyCondition)
+
+		return cond != nil && cond.Status == corev1.ConditionTrue
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+)
+
+var _ = Describe("Wordpress TLS", func() {
+	var wp *Wordpress
+
+	BeforeEach(func() {
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "test.com"},
+					{Domain: "test.com", Path: "/blog", TLSSecretRef: "blog-tls"},
+					{Domain: "www.test.com"},
+					{Domain: "shop.test.com", TLSSecretRef: "shop-tls"},
+				},
+			},
+		})
+	})
+
+	It("should serve only the routes with a TLS secret over https", func() {
+		Expect(wp.TLSGroups()).To(Equal([]TLSGroup{{SecretName: "shop-tls", Domains: []string{"shop.test.com"}}}))
+		Expect(wp.ServesHTTPS()).To(BeFalse())
+		Expect(wp.HomeURL()).To(Equal("http://test.com"))
+	})
+
+	It("should group the domains by TLS secret", func() {
+		wp.Spec.TLSSecretRef = "test-tls"
+
+		Expect(wp.TLSGroups()).To(Equal([]TLSGroup{
+			{SecretName: "test-tls", Domains: []string{"test.com", "www.test.com"}},
+			{SecretName: "shop-tls", Domains: []string{"shop.test.com"}},
+		}))
+		Expect(wp.HomeURL()).To(Equal("https://test.com"))
+	})
+
+	It("should switch to https once the cert-manager certificates are issued", func() {
+		wp.Spec.CertManager = &wordpressv1alpha1.CertManagerSpec{
+			IssuerRef: wordpressv1alpha1.CertManagerIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		}
+
+		Expect(wp.TLSGroups()).To(Equal([]TLSGroup{
+			{SecretName: "test-tls", Domains: []string{"test.com", "www.test.com"}, Managed: true},
+			{SecretName: "shop-tls", Domains: []string{"shop.test.com"}},
+		}))
+		Expect(wp.HomeURL()).To(Equal("http://test.com"))
+
+		wp.SetCondition(wordpressv1alpha1.TLSReadyCondition, corev1.ConditionFalse, wordpressv1alpha1.CertificatesPendingReason, "")
+		Expect(wp.HomeURL()).To(Equal("http://test.com"))
+
+		wp.SetCondition(wordpressv1alpha1.TLSReadyCondition, corev1.ConditionTrue, wordpressv1alpha1.CertificatesReadyReason, "")
+		Expect(wp.HomeURL()).To(Equal("https://test.com"))
+	})
+
+	It("should not manage the TLS secrets set by the user", func() {
+		wp.Spec.TLSSecretRef = "test-tls"
+		wp.Spec.CertManager = &wordpressv1alpha1.CertManagerSpec{
+			IssuerRef: wordpressv1alpha1.CertManagerIssuerReference{Name: "letsencrypt"},
+		}
+
+		for _, group := range wp.TLSGroups() {
+			Expect(group.Managed).To(BeFalse(), group.SecretName)
+		}
+	})
+})
@@ -0,0 +1,429 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+const tooManySourcesMsg = "may not specify more than 1 volume source"
+
+// minGitResolveInterval is the minimum interval at which git references are
+// resolved, other than 0 which disables the resolution.
+const minGitResolveInterval = time.Minute
+
+var supportedPullPolicies = sets.NewString(
+	string(corev1.PullAlways),
+	string(corev1.PullIfNotPresent),
+	string(corev1.PullNever),
+)
+
+// Validate checks the Wordpress spec for errors which would otherwise surface
+// only when syncing the site's resources.
+func (wp *Wordpress) Validate() field.ErrorList {
+	specPath := field.NewPath("spec")
+
+	allErrs := validateRoutes(wp.Spec.Routes, specPath.Child("routes"))
+
+	for i, domain := range wp.Spec.Domains {
+		allErrs = append(allErrs, validateDomain(string(domain), specPath.Child("domains").Index(i))...)
+	}
+
+	if p := wp.Spec.ImagePullPolicy; p != "" && !supportedPullPolicies.Has(string(p)) {
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("imagePullPolicy"), p, supportedPullPolicies.List()))
+	}
+
+	if p := wp.Spec.WordpressPathPrefix; p != "" && !strings.HasPrefix(p, "/") {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("wordpressPathPrefix"), p, "must be an absolute path"))
+	}
+
+	if wp.Spec.CodeVolumeSpec != nil {
+		allErrs = append(allErrs, validateCodeVolumeSpec(wp.Spec.CodeVolumeSpec, specPath.Child("code"))...)
+	}
+
+	if wp.Spec.MediaVolumeSpec != nil {
+		allErrs = append(allErrs, validateMediaVolumeSpec(wp.Spec.MediaVolumeSpec, specPath.Child("media"))...)
+	}
+
+	if wp.Spec.Cron != nil {
+		allErrs = append(allErrs, wp.validateCronSpec(specPath.Child("cron"))...)
+	}
+
+	if pdb := wp.Spec.DisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("disruptionBudget", "maxUnavailable"), "may not be set along with minAvailable"))
+	}
+
+	if as := wp.Spec.Autoscaling; as != nil && as.MinReplicas != nil && *as.MinReplicas > as.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("autoscaling", "maxReplicas"), as.MaxReplicas, "must be greater than or equal to minReplicas"))
+	}
+
+	if wp.Spec.Monitoring != nil {
+		allErrs = append(allErrs, validateMonitoringSpec(wp.Spec.Monitoring, specPath.Child("monitoring"))...)
+	}
+
+	if wp.Spec.Service != nil {
+		allErrs = append(allErrs, validateServiceSpec(wp.Spec.Service, specPath.Child("service"))...)
+	}
+
+	allErrs = append(allErrs, wp.validateIngressGroups(specPath)...)
+	allErrs = append(allErrs, wp.validateRedirects(specPath)...)
+
+	if wp.RoutingMode() == wordpressv1alpha1.RoutingModeGateway && wp.Gateway() == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("routing", "gateway"), "must be set in gateway mode, as the operator has no default gateway"))
+	}
+
+	return allErrs
+}
+
+// ValidateUpdate validates the Wordpress spec and checks that no immutable
+// fields were changed compared to old.
+func (wp *Wordpress) ValidateUpdate(old *Wordpress) field.ErrorList {
+	allErrs := wp.Validate()
+
+	specPath := field.NewPath("spec")
+
+	if wp.Spec.CodeVolumeSpec != nil && old.Spec.CodeVolumeSpec != nil &&
+		wp.Spec.CodeVolumeSpec.PersistentVolumeClaim != nil && old.Spec.CodeVolumeSpec.PersistentVolumeClaim != nil {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(
+			wp.Spec.CodeVolumeSpec.PersistentVolumeClaim, old.Spec.CodeVolumeSpec.PersistentVolumeClaim,
+			specPath.Child("code", "persistentVolumeClaim"))...)
+	}
+
+	if wp.Spec.MediaVolumeSpec != nil && old.Spec.MediaVolumeSpec != nil &&
+		wp.Spec.MediaVolumeSpec.PersistentVolumeClaim != nil && old.Spec.MediaVolumeSpec.PersistentVolumeClaim != nil {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(
+			wp.Spec.MediaVolumeSpec.PersistentVolumeClaim, old.Spec.MediaVolumeSpec.PersistentVolumeClaim,
+			specPath.Child("media", "persistentVolumeClaim"))...)
+	}
+
+	return allErrs
+}
+
+func validateRoutes(routes []wordpressv1alpha1.RouteSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[string]bool{}
+
+	for i, route := range routes {
+		idxPath := fldPath.Index(i)
+
+		allErrs = append(allErrs, validateDomain(route.Domain, idxPath.Child("domain"))...)
+
+		if route.Path != "" {
+			if u, err := url.Parse(route.Path); err != nil || u.Path != route.Path || !strings.HasPrefix(route.Path, "/") {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("path"), route.Path, "must be an absolute path, without query or fragment"))
+			}
+		}
+
+		key := RouteKey(route)
+		if seen[key] {
+			allErrs = append(allErrs, field.Duplicate(idxPath, key))
+		}
+
+		seen[key] = true
+	}
+
+	return allErrs
+}
+
+func validateMonitoringSpec(spec *wordpressv1alpha1.MonitoringSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.Interval != nil && spec.Interval.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), spec.Interval.Duration.String(), "must be positive"))
+	}
+
+	if spec.ScrapeTimeout != nil && spec.ScrapeTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scrapeTimeout"), spec.ScrapeTimeout.Duration.String(), "must be positive"))
+	}
+
+	if spec.Interval != nil && spec.ScrapeTimeout != nil && spec.ScrapeTimeout.Duration > spec.Interval.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scrapeTimeout"), spec.ScrapeTimeout.Duration.String(), "must not be greater than the interval"))
+	}
+
+	return allErrs
+}
+
+func validateServiceSpec(spec *wordpressv1alpha1.ServiceSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.SessionAffinityConfig != nil && spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sessionAffinityConfig"), "may only be set with ClientIP session affinity"))
+	}
+
+	names := map[string]bool{"http": true, "prometheus": true}
+	ports := map[int32]bool{80: true, MetricsExporterPort: true}
+
+	for i, port := range spec.ExtraPorts {
+		idxPath := fldPath.Child("extraPorts").Index(i)
+
+		if port.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsValidPortName(port.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), port.Name, msg))
+			}
+
+			if names[port.Name] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), port.Name))
+			}
+		}
+
+		for _, msg := range validation.IsValidPortNum(int(port.Port)) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("port"), port.Port, msg))
+		}
+
+		if ports[port.Port] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("port"), port.Port))
+		}
+
+		names[port.Name] = true
+		ports[port.Port] = true
+	}
+
+	return allErrs
+}
+
+func (wp *Wordpress) validateIngressGroups(specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	groups := map[string]bool{}
+
+	if wp.Spec.Ingress != nil {
+		for i, group := range wp.Spec.Ingress.Groups {
+			idxPath := specPath.Child("ingress", "groups").Index(i)
+
+			for _, msg := range validation.IsDNS1123Label(group.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), group.Name, msg))
+			}
+
+			if groups[group.Name] {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), group.Name))
+			}
+
+			groups[group.Name] = true
+		}
+	}
+
+	for i, route := range wp.Spec.Routes {
+		if route.IngressGroup != "" && !groups[route.IngressGroup] {
+			allErrs = append(allErrs, field.NotFound(specPath.Child("routes").Index(i).Child("ingressGroup"), route.IngressGroup))
+		}
+	}
+
+	return allErrs
+}
+
+func (wp *Wordpress) validateRedirects(specPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	domains := map[string]bool{}
+	routes := map[string]bool{}
+	seen := map[string]bool{}
+
+	for _, route := range wp.Spec.Routes {
+		domains[strings.ToLower(route.Domain)] = true
+		routes[RouteKey(route)] = true
+	}
+
+	if host := wp.Spec.CanonicalHost; host != "" && (len(wp.Spec.Routes) == 0 || !strings.EqualFold(host, wp.Spec.Routes[0].Domain)) {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("canonicalHost"), host, "must be the domain of the first route"))
+	}
+
+	gateway := wp.RoutingMode() == wordpressv1alpha1.RoutingModeGateway
+
+	for i, redirect := range wp.Spec.Redirects {
+		idxPath := specPath.Child("redirects").Index(i)
+		key := RouteKey(wordpressv1alpha1.RouteSpec{Domain: redirect.Host, Path: redirect.Path})
+
+		if !domains[strings.ToLower(redirect.Host)] {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("host"), redirect.Host, "must be the domain of one of the routes"))
+		}
+
+		if redirect.Path != "" && !strings.HasPrefix(redirect.Path, "/") {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("path"), redirect.Path, "must be an absolute path"))
+		}
+
+		switch {
+		case routes[key]:
+			allErrs = append(allErrs, field.Invalid(idxPath, key, "must not redirect a route of the site"))
+		case seen[key]:
+			allErrs = append(allErrs, field.Duplicate(idxPath, key))
+		case path.Clean("/"+redirect.Path) == "/" && wp.IsRedirected(redirect.Host):
+			allErrs = append(allErrs, field.Invalid(idxPath, key, "is redirected to the canonical host"))
+		}
+
+		seen[key] = true
+
+		if !isRedirectTarget(redirect.Target) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("target"), redirect.Target,
+				"must be an absolute http or https URL or an absolute path, without query or fragment"))
+		}
+
+		if gateway && redirect.StatusCode != 0 && redirect.StatusCode != 301 && redirect.StatusCode != 302 {
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("statusCode"), redirect.StatusCode, []string{"301", "302"}))
+		}
+	}
+
+	return allErrs
+}
+
+// isRedirectTarget returns true for absolute http or https URLs and absolute
+// paths, without query or fragment.
+func isRedirectTarget(target string) bool {
+	u, err := url.Parse(target)
+	if err != nil || u.RawQuery != "" || u.Fragment != "" {
+		return false
+	}
+
+	if u.Scheme == "" && u.Host == "" {
+		return strings.HasPrefix(u.Path, "/")
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validateDomain(domain string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	validate := validation.IsDNS1123Subdomain
+	if strings.HasPrefix(domain, "*.") {
+		validate = validation.IsWildcardDNS1123Subdomain
+	}
+
+	for _, msg := range validate(domain) {
+		allErrs = append(allErrs, field.Invalid(fldPath, domain, msg))
+	}
+
+	return allErrs
+}
+
+func (wp *Wordpress) validateCronSpec(fldPath *field.Path) field.ErrorList {
+	spec := wp.Spec.Cron
+	allErrs := field.ErrorList{}
+
+	if spec.Interval != nil && spec.Interval.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), spec.Interval.Duration.String(), "must be at least 1s"))
+	}
+
+	if spec.Timeout != nil && spec.Timeout.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), spec.Timeout.Duration.String(), "must be at least 1s"))
+	}
+
+	if spec.Schedule != "" {
+		if _, err := cron.ParseStandard(spec.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), spec.Schedule, err.Error()))
+		}
+	}
+
+	if spec.HTTP != nil {
+		allErrs = append(allErrs, wp.validateCronHTTPSpec(spec.HTTP, fldPath.Child("http"))...)
+	}
+
+	return allErrs
+}
+
+func (wp *Wordpress) validateCronHTTPSpec(spec *wordpressv1alpha1.CronHTTPSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.URL != "" {
+		u, err := url.Parse(spec.URL)
+
+		switch {
+		case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), spec.URL, "must be an absolute http or https URL"))
+		case !options.WPCronAllowAnyURL && !wp.IsSiteHost(u.Host):
+			// the response code is recorded in status, so any URL would let
+			// the site probe hosts reachable by the operator
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), spec.URL,
+				"must be on one of the site's route domains or on its service"))
+		}
+	}
+
+	if len(spec.CABundle) > 0 && !x509.NewCertPool().AppendCertsFromPEM(spec.CABundle) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("caBundle"), "", "must contain PEM encoded certificates"))
+	}
+
+	return allErrs
+}
+
+func validateCodeVolumeSpec(spec *wordpressv1alpha1.CodeVolumeSpec, fldPath *field.Path) field.ErrorList {
+	sources := []bool{
+		spec.GitDir != nil,
+		spec.PersistentVolumeClaim != nil,
+		spec.HostPath != nil,
+		spec.EmptyDir != nil,
+	}
+	names := []string{"git", "persistentVolumeClaim", "hostPath", "emptyDir"}
+
+	allErrs := validateVolumeSources(sources, names, fldPath)
+
+	// every resolution runs a job, so it shouldn't run too often
+	if spec.GitDir != nil && spec.GitDir.ResolveInterval != nil {
+		interval := spec.GitDir.ResolveInterval.Duration
+		if interval != 0 && interval < minGitResolveInterval {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("git", "resolveInterval"), interval.String(),
+				fmt.Sprintf("must be 0 or at least %s", minGitResolveInterval)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateMediaVolumeSpec(spec *wordpressv1alpha1.MediaVolumeSpec, fldPath *field.Path) field.ErrorList {
+	sources := []bool{
+		spec.S3VolumeSource != nil,
+		spec.GCSVolumeSource != nil,
+		spec.PersistentVolumeClaim != nil,
+		spec.HostPath != nil,
+		spec.EmptyDir != nil,
+	}
+	names := []string{"s3", "gcs", "persistentVolumeClaim", "hostPath", "emptyDir"}
+
+	return validateVolumeSources(sources, names, fldPath)
+}
+
+// validateVolumeSources reports every volume source set after the first one.
+func validateVolumeSources(sources []bool, names []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	numSources := 0
+
+	for i, set := range sources {
+		if !set {
+			continue
+		}
+
+		numSources++
+		if numSources > 1 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(names[i]), tooManySourcesMsg))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,309 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+)
+
+var _ = Describe("Wordpress validation", func() {
+	var wp *Wordpress
+
+	BeforeEach(func() {
+		wp = New(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{
+					{Domain: "test.com"},
+					{Domain: "test.com", Path: "/blog"},
+					{Domain: "*.test.org"},
+				},
+			},
+		})
+		wp.SetDefaults()
+	})
+
+	It("should accept a valid spec", func() {
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	DescribeTable("should reject invalid routes",
+		func(route wordpressv1alpha1.RouteSpec, errType field.ErrorType) {
+			wp.Spec.Routes = append(wp.Spec.Routes, route)
+
+			errs := wp.Validate()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(errType))
+		},
+		Entry("with upper case domain", wordpressv1alpha1.RouteSpec{Domain: "Test.net"}, field.ErrorTypeInvalid),
+		Entry("with an url as domain", wordpressv1alpha1.RouteSpec{Domain: "http://test.com"}, field.ErrorTypeInvalid),
+		Entry("with relative path", wordpressv1alpha1.RouteSpec{Domain: "test.net", Path: "blog"}, field.ErrorTypeInvalid),
+		Entry("with query in path", wordpressv1alpha1.RouteSpec{Domain: "test.net", Path: "/?p=1"}, field.ErrorTypeInvalid),
+		Entry("with duplicate domain", wordpressv1alpha1.RouteSpec{Domain: "test.com", Path: "/"}, field.ErrorTypeDuplicate),
+		Entry("with duplicate path", wordpressv1alpha1.RouteSpec{Domain: "test.com", Path: "/blog/"}, field.ErrorTypeDuplicate),
+	)
+
+	It("should reject unsupported image pull policies", func() {
+		wp.Spec.ImagePullPolicy = "Allways"
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		Expect(errs[0].Field).To(Equal("spec.imagePullPolicy"))
+	})
+
+	It("should reject multiple code volume sources", func() {
+		wp.Spec.CodeVolumeSpec = &wordpressv1alpha1.CodeVolumeSpec{
+			GitDir:                &wordpressv1alpha1.GitVolumeSource{Repository: "https://github.com/bitpoke/stack-example-wordpress.git"},
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(errs[0].Field).To(Equal("spec.code.persistentVolumeClaim"))
+	})
+
+	It("should reject multiple media volume sources", func() {
+		wp.Spec.MediaVolumeSpec = &wordpressv1alpha1.MediaVolumeSpec{
+			S3VolumeSource:  &wordpressv1alpha1.S3VolumeSource{Bucket: "test"},
+			GCSVolumeSource: &wordpressv1alpha1.GCSVolumeSource{Bucket: "test"},
+			EmptyDir:        &corev1.EmptyDirVolumeSource{},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.media.gcs"))
+		Expect(errs[1].Field).To(Equal("spec.media.emptyDir"))
+	})
+
+	It("should reject changes to the media persistent volume claim", func() {
+		wp.Spec.MediaVolumeSpec = &wordpressv1alpha1.MediaVolumeSpec{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+		old := New(wp.Unwrap().DeepCopy())
+
+		Expect(wp.ValidateUpdate(old)).To(BeEmpty())
+
+		wp.Spec.MediaVolumeSpec.PersistentVolumeClaim.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("2Gi")
+
+		errs := wp.ValidateUpdate(old)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.media.persistentVolumeClaim"))
+	})
+
+	It("should reject invalid wp-cron schedules, intervals and timeouts", func() {
+		wp.Spec.Cron.Schedule = "every minute"
+		wp.Spec.Cron.Interval = &metav1.Duration{Duration: 100 * time.Millisecond}
+		wp.Spec.Cron.Timeout = &metav1.Duration{}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("spec.cron.interval"))
+		Expect(errs[1].Field).To(Equal("spec.cron.timeout"))
+		Expect(errs[2].Field).To(Equal("spec.cron.schedule"))
+	})
+
+	It("should reject invalid wp-cron request URLs and CA bundles", func() {
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{
+			URL:      "/wp-cron.php",
+			CABundle: []byte("not a certificate"),
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.cron.http.url"))
+		Expect(errs[1].Field).To(Equal("spec.cron.http.caBundle"))
+
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{URL: "https://test.com/blog/wp-cron.php"}
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should reject wp-cron request URLs outside the site, unless any URL is allowed", func() {
+		allowAnyURL := options.WPCronAllowAnyURL
+		defer func() { options.WPCronAllowAnyURL = allowAnyURL }()
+
+		wp.Spec.Cron.HTTP = &wordpressv1alpha1.CronHTTPSpec{URL: "http://metadata.internal/"}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.cron.http.url"))
+
+		wp.Spec.Cron.HTTP.URL = fmt.Sprintf("http://%s.%s.svc/wp-cron.php", wp.Name, wp.Namespace)
+		Expect(wp.Validate()).To(BeEmpty())
+
+		wp.Spec.Cron.HTTP.URL = "https://www.test.org/wp-cron.php"
+		Expect(wp.Validate()).To(BeEmpty())
+
+		wp.Spec.Cron.HTTP.URL = "https://www.staging.test.org/wp-cron.php"
+		Expect(wp.Validate()).To(HaveLen(1))
+
+		options.WPCronAllowAnyURL = true
+		wp.Spec.Cron.HTTP.URL = "http://metadata.internal/"
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should require a gateway in gateway routing mode", func() {
+		wp.Spec.Routing = &wordpressv1alpha1.RoutingSpec{Mode: wordpressv1alpha1.RoutingModeGateway}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.routing.gateway"))
+
+		wp.Spec.Routing.Gateway = &wordpressv1alpha1.GatewayReference{Name: "public"}
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should validate redirects against the routes", func() {
+		wp.Spec.CanonicalHost = "test.org"
+		wp.Spec.Redirects = []wordpressv1alpha1.RedirectSpec{
+			{Host: "test.net", Path: "/shop", Target: "https://test.com"},
+			{Host: "test.com", Path: "/blog/", Target: "/news"},
+			{Host: "test.com", Path: "/old", Target: "news?p=1"},
+			{Host: "test.com", Path: "/old", Target: "/news"},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(5))
+		Expect(errs[0].Field).To(Equal("spec.canonicalHost"))
+		Expect(errs[1].Field).To(Equal("spec.redirects[0].host"))
+		Expect(errs[2].Field).To(Equal("spec.redirects[1]"))
+		Expect(errs[3].Field).To(Equal("spec.redirects[2].target"))
+		Expect(errs[4].Type).To(Equal(field.ErrorTypeDuplicate))
+
+		wp.Spec.CanonicalHost = "test.com"
+		wp.Spec.Redirects = []wordpressv1alpha1.RedirectSpec{
+			{Host: "test.com", Path: "/old", Target: "https://test.org/new", StatusCode: 307},
+		}
+		Expect(wp.Validate()).To(BeEmpty())
+
+		wp.Spec.Routing = &wordpressv1alpha1.RoutingSpec{
+			Mode:    wordpressv1alpha1.RoutingModeGateway,
+			Gateway: &wordpressv1alpha1.GatewayReference{Name: "public"},
+		}
+
+		errs = wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+	})
+
+	It("should reject routes referencing unknown ingress groups", func() {
+		wp.Spec.Routes[1].IngressGroup = "internal"
+		wp.Spec.Ingress = &wordpressv1alpha1.IngressSpec{
+			Groups: []wordpressv1alpha1.IngressGroup{{Name: "Internal"}, {Name: "Internal"}},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("spec.ingress.groups[0].name"))
+		Expect(errs[2].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(errs[3].Type).To(Equal(field.ErrorTypeNotFound))
+
+		wp.Spec.Ingress.Groups = []wordpressv1alpha1.IngressGroup{{Name: "internal"}}
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should reject extra service ports which clash with the site's ports", func() {
+		wp.Spec.Service = &wordpressv1alpha1.ServiceSpec{
+			SessionAffinityConfig: &corev1.SessionAffinityConfig{},
+			ExtraPorts: []corev1.ServicePort{
+				{Name: "http", Port: 8080},
+				{Port: 9145},
+			},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("spec.service.sessionAffinityConfig"))
+		Expect(errs[1].Field).To(Equal("spec.service.extraPorts[0].name"))
+		Expect(errs[2].Field).To(Equal("spec.service.extraPorts[1].name"))
+		Expect(errs[3].Field).To(Equal("spec.service.extraPorts[1].port"))
+
+		wp.Spec.Service = &wordpressv1alpha1.ServiceSpec{
+			ExtraPorts: []corev1.ServicePort{{Name: "debug", Port: 2345}},
+		}
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should validate the disruption budget and the autoscaling limits", func() {
+		minAvailable := intstr.FromInt(1)
+		maxUnavailable := intstr.FromString("50%")
+		minReplicas := int32(3)
+
+		wp.Spec.DisruptionBudget = &wordpressv1alpha1.DisruptionBudgetSpec{MinAvailable: &minAvailable, MaxUnavailable: &maxUnavailable}
+		wp.Spec.Autoscaling = &wordpressv1alpha1.AutoscalingSpec{MinReplicas: &minReplicas, MaxReplicas: 2}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.disruptionBudget.maxUnavailable"))
+		Expect(errs[1].Field).To(Equal("spec.autoscaling.maxReplicas"))
+
+		wp.Spec.DisruptionBudget.MinAvailable = nil
+		wp.Spec.Autoscaling.MaxReplicas = 10
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should reject scrape timeouts greater than the interval", func() {
+		wp.Spec.Monitoring = &wordpressv1alpha1.MonitoringSpec{
+			Interval:      &metav1.Duration{Duration: 15 * time.Second},
+			ScrapeTimeout: &metav1.Duration{Duration: 30 * time.Second},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.monitoring.scrapeTimeout"))
+
+		wp.Spec.Monitoring.ScrapeTimeout.Duration = 10 * time.Second
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+
+	It("should reject too short git resolve intervals", func() {
+		wp.Spec.CodeVolumeSpec = &wordpressv1alpha1.CodeVolumeSpec{
+			GitDir: &wordpressv1alpha1.GitVolumeSource{
+				Repository:      "https://github.com/bitpoke/stack-example-wordpress.git",
+				ResolveInterval: &metav1.Duration{Duration: 10 * time.Second},
+			},
+		}
+
+		errs := wp.Validate()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.code.git.resolveInterval"))
+
+		wp.Spec.CodeVolumeSpec.GitDir.ResolveInterval.Duration = 0
+		Expect(wp.Validate()).To(BeEmpty())
+	})
+})
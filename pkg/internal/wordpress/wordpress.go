@@ -18,14 +18,31 @@ package wordpress
 
 import (
 	"fmt"
+	"net"
 	"path"
+	"strings"
+	"time"
 
 	"github.com/cooleo/slugify"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
 )
 
+// DBUpgradeForLabel is the label set on database upgrade jobs, holding the
+// image version the upgrade is run for.
+const DBUpgradeForLabel = "wordpress.presslabs.org/upgrade-for"
+
+// RestoreAnnotation is set on sites which are being restored, holding the
+// name of the WordpressRestore. The web pods are stopped while it is set.
+const RestoreAnnotation = "wordpress.presslabs.org/restore"
+
+// ReplicasBeforeRestoreAnnotation is set on the web deployment of sites which
+// are being restored, holding the number of replicas to scale back to.
+const ReplicasBeforeRestoreAnnotation = "wordpress.presslabs.org/replicas-before-restore"
+
 // Wordpress embeds wordpressv1alpha1.Wordpress and adds utility functions.
 type Wordpress struct {
 	*wordpressv1alpha1.Wordpress
@@ -42,14 +59,34 @@ var (
 	WordpressSecret = component{name: "web", objNameFmt: "%s-wp"}
 	// WordpressDeployment component.
 	WordpressDeployment = component{name: "web", objNameFmt: "%s"}
+	// WordpressPDB component.
+	WordpressPDB = component{name: "web", objNameFmt: "%s"}
+	// WordpressHPA component.
+	WordpressHPA = component{name: "web", objNameFmt: "%s"}
+	// WordpressMonitor component, either a ServiceMonitor or a PodMonitor.
+	WordpressMonitor = component{name: "web", objNameFmt: "%s"}
+	// WordpressWebNetworkPolicy component.
+	WordpressWebNetworkPolicy = component{name: "web", objNameFmt: "%s-web"}
+	// WordpressJobNetworkPolicy component.
+	WordpressJobNetworkPolicy = component{name: "wp-cli", objNameFmt: "%s-wp-cli"}
 	// WordpressCron component.
 	WordpressCron = component{name: "cron", objNameFmt: "%s-wp-cron"}
+	// WordpressGitResolve component, the job resolving the git reference.
+	WordpressGitResolve = component{name: "git-resolve", objNameFmt: "%s-git-resolve"}
 	// WordpressDBUpgrade component.
 	WordpressDBUpgrade = component{name: "upgrade", objNameFmt: "%s-upgrade"}
 	// WordpressService component.
 	WordpressService = component{name: "web", objNameFmt: "%s"}
 	// WordpressIngress component.
 	WordpressIngress = component{name: "web", objNameFmt: "%s"}
+	// WordpressHTTPRoute component, named after the domain as well.
+	WordpressHTTPRoute = component{name: "web", objNameFmt: "%s"}
+	// WordpressRedirect component, named after the redirected domain and path.
+	WordpressRedirect = component{name: "redirect", objNameFmt: "%s-redirect"}
+	// WordpressTLSSecret component, issued by cert-manager.
+	WordpressTLSSecret = component{name: "web", objNameFmt: "%s-tls"}
+	// WordpressCertificate component, named after its secret.
+	WordpressCertificate = component{name: "web"}
 	// WordpressCodePVC component.
 	WordpressCodePVC = component{name: "code", objNameFmt: "%s-code"}
 	// WordpressMediaPVC component.
@@ -88,7 +125,7 @@ func (wp *Wordpress) ComponentLabels(component component) labels.Set {
 	l["app.kubernetes.io/component"] = component.name
 
 	if component == WordpressDBUpgrade {
-		l["wordpress.presslabs.org/upgrade-for"] = wp.ImageVersion()
+		l[DBUpgradeForLabel] = wp.ImageVersion()
 	}
 
 	return l
@@ -130,6 +167,45 @@ func (wp *Wordpress) JobPodLabels() labels.Set {
 	return l
 }
 
+// JobPodSelector selects the pods of the site's jobs, the wp-cli ones along
+// with the wp-cron ones, which are labeled as the cron component.
+func (wp *Wordpress) JobPodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchLabels: wp.Labels(),
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "app.kubernetes.io/component",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"wp-cli", WordpressCron.name},
+			},
+		},
+	}
+}
+
+// IsBeingRestored returns true if the site is being restored from a backup.
+func (wp *Wordpress) IsBeingRestored() bool {
+	return wp.Annotations[RestoreAnnotation] != ""
+}
+
+// CronInterval returns the interval at which wp-cron.php is requested, in
+// http mode.
+func (wp *Wordpress) CronInterval() time.Duration {
+	if wp.Spec.Cron != nil && wp.Spec.Cron.Interval != nil {
+		return wp.Spec.Cron.Interval.Duration
+	}
+
+	return options.WPCronInterval
+}
+
+// CronTimeout returns the timeout of the wp-cron.php requests, in http mode.
+func (wp *Wordpress) CronTimeout() time.Duration {
+	if wp.Spec.Cron != nil && wp.Spec.Cron.Timeout != nil {
+		return wp.Spec.Cron.Timeout.Duration
+	}
+
+	return options.WPCronTimeout
+}
+
 // MainDomain returns the site main domain or a local domain <cluster-name>.<namespace>.svc.cluster.local.
 func (wp *Wordpress) MainDomain() string {
 	if len(wp.Spec.Routes) > 0 {
@@ -140,10 +216,50 @@ func (wp *Wordpress) MainDomain() string {
 	return fmt.Sprintf("%s.%s.svc", wp.ComponentName(WordpressService), wp.Namespace)
 }
 
+// IsSiteHost returns whether the host, with or without a port, is one of the
+// site's route domains or a name of its service. Wildcard domains match a
+// single label, like they do in routes.
+func (wp *Wordpress) IsSiteHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.ToLower(host)
+
+	for _, route := range wp.Spec.Routes {
+		domain := strings.ToLower(route.Domain)
+		if domain == host {
+			return true
+		}
+
+		if strings.HasPrefix(domain, "*.") && strings.HasSuffix(host, domain[1:]) {
+			if label := strings.TrimSuffix(host, domain[1:]); label != "" && !strings.Contains(label, ".") {
+				return true
+			}
+		}
+	}
+
+	svc := wp.ComponentName(WordpressService)
+	names := []string{
+		svc,
+		fmt.Sprintf("%s.%s", svc, wp.Namespace),
+		fmt.Sprintf("%s.%s.svc", svc, wp.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", svc, wp.Namespace),
+	}
+
+	for _, name := range names {
+		if name == host {
+			return true
+		}
+	}
+
+	return false
+}
+
 // HomeURL returns the WP_HOMEURL (e.g. http://example.com/)
 func (wp *Wordpress) HomeURL(subPaths ...string) string {
 	scheme := "http"
-	if len(wp.Spec.TLSSecretRef) > 0 {
+	if wp.ServesHTTPS() {
 		scheme = "https"
 	}
 
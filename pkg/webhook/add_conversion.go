@@ -0,0 +1,37 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// conversionPath is the path under which the conversion webhook is served.
+const conversionPath = "/convert"
+
+func init() {
+	AddToManagerFuncs = append(AddToManagerFuncs, addConversion)
+}
+
+// addConversion registers the conversion webhook, which converts between all
+// the API versions registered within the Manager's scheme.
+func addConversion(m manager.Manager) error {
+	m.GetWebhookServer().Register(conversionPath, &conversion.Webhook{})
+
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certs manages the certificates used to serve the operator's
+// admission webhooks.
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	// CACertKey is the secret key holding the PEM encoded CA certificate.
+	CACertKey = "ca.crt"
+	// CAKeyKey is the secret key holding the PEM encoded CA private key.
+	CAKeyKey = "ca.key"
+
+	rsaKeySize = 2048
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 365 * 24 * time.Hour
+
+	// certificates are renewed when they expire in less than renewBefore.
+	renewBefore = 30 * 24 * time.Hour
+)
+
+var errInvalidCert = errors.New("invalid certificate")
+
+// keyPair is a PEM encoded certificate and private key.
+type keyPair struct {
+	Cert []byte
+	Key  []byte
+}
+
+func newCA(commonName string) (*keyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := certTemplate(commonName, caValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.IsCA = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return encode(der, key), nil
+}
+
+// newServingCert creates a serving certificate for dnsNames, signed by ca.
+func newServingCert(ca *keyPair, dnsNames []string) (*keyPair, error) {
+	caCert, caKey, err := ca.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := certTemplate(dnsNames[0], certValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.DNSNames = dnsNames
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return encode(der, key), nil
+}
+
+func certTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		BasicConstraintsValid: true,
+	}, nil
+}
+
+func encode(der []byte, key *rsa.PrivateKey) *keyPair {
+	return &keyPair{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+	}
+}
+
+func (kp *keyPair) decode() (*x509.Certificate, *rsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(kp.Cert)
+	keyBlock, _ := pem.Decode(kp.Key)
+
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errInvalidCert
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+// validCA checks that the CA can be used to sign new certificates.
+func validCA(ca *keyPair, now time.Time) error {
+	cert, _, err := ca.decode()
+	if err != nil {
+		return err
+	}
+
+	if !cert.IsCA {
+		return fmt.Errorf("%w: not a CA certificate", errInvalidCert)
+	}
+
+	return checkExpiry(cert, now)
+}
+
+// validServingCert checks that the serving certificate is signed by the CA,
+// matches its private key, covers all dnsNames and doesn't expire soon.
+func validServingCert(ca, kp *keyPair, dnsNames []string, now time.Time) error {
+	caCert, _, err := ca.decode()
+	if err != nil {
+		return err
+	}
+
+	cert, key, err := kp.decode()
+	if err != nil {
+		return err
+	}
+
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		return fmt.Errorf("%w: the private key does not match the certificate", errInvalidCert)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	for _, name := range dnsNames {
+		_, err = cert.Verify(x509.VerifyOptions{
+			DNSName:     name,
+			Roots:       pool,
+			CurrentTime: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return checkExpiry(cert, now)
+}
+
+func checkExpiry(cert *x509.Certificate, now time.Time) error {
+	if now.Add(renewBefore).After(cert.NotAfter) {
+		return fmt.Errorf("%w: certificate %q expires at %s", errInvalidCert, cert.Subject.CommonName, cert.NotAfter)
+	}
+
+	return nil
+}
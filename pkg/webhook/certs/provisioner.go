@@ -0,0 +1,312 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const defaultResyncInterval = time.Minute
+
+// Provisioner generates a self-signed CA and a serving certificate for the
+// webhook server, stores them in a secret shared by all the operator
+// replicas, writes them into the webhook server's certificate directory and
+// injects the CA into the webhook configurations and the conversion webhooks
+// of the custom resource definitions.
+type Provisioner struct {
+	// Client is used to read and write the certificates secret and the webhook
+	// configurations. It should not be backed by the manager's cache, as
+	// Provision is called before the cache gets started.
+	Client client.Client
+	Log    logr.Logger
+
+	// Secret is the secret which stores the CA and the serving certificate.
+	Secret types.NamespacedName
+	// DNSNames are the names under which the webhook server is reachable.
+	DNSNames []string
+	// CertDir is the directory from which the webhook server reads the serving certificate.
+	CertDir string
+
+	// MutatingWebhookConfigurations are the names of the
+	// MutatingWebhookConfigurations to inject the CA into.
+	MutatingWebhookConfigurations []string
+	// ValidatingWebhookConfigurations are the names of the
+	// ValidatingWebhookConfigurations to inject the CA into.
+	ValidatingWebhookConfigurations []string
+
+	// CustomResourceDefinitions are the names of the
+	// CustomResourceDefinitions converted by the webhook server. Their
+	// conversion strategy is set to Webhook, served by ConversionService.
+	CustomResourceDefinitions []string
+	// ConversionService is the service serving the conversion webhook.
+	ConversionService *apiextensionsv1.ServiceReference
+
+	// ResyncInterval is the interval at which certificates are checked for
+	// renewal and the CA is re-injected. Defaults to one minute.
+	ResyncInterval time.Duration
+}
+
+// Provision makes sure that valid certificates exist and are in place.
+func (p *Provisioner) Provision(ctx context.Context) error {
+	var ca, serving *keyPair
+
+	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}, func() (err error) {
+		ca, serving, err = p.ensureSecret(ctx)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = p.writeCertDir(serving); err != nil {
+		return err
+	}
+
+	if err = p.injectCABundle(ctx, ca.Cert); err != nil {
+		return err
+	}
+
+	return p.setupConversion(ctx, ca.Cert)
+}
+
+// Start periodically re-provisions the certificates, until the context is done.
+func (p *Provisioner) Start(ctx context.Context) error {
+	interval := p.ResyncInterval
+	if interval == 0 {
+		interval = defaultResyncInterval
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := p.Provision(ctx); err != nil {
+			p.Log.Error(err, "failed to provision webhook certificates")
+		}
+	}, interval)
+
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Every
+// replica runs a webhook server, so certificates need to be in place on each
+// of them.
+func (p *Provisioner) NeedLeaderElection() bool {
+	return false
+}
+
+// ensureSecret reads the certificates from the secret, renewing them if
+// they are missing, invalid or about to expire.
+func (p *Provisioner) ensureSecret(ctx context.Context) (*keyPair, *keyPair, error) {
+	secret := &corev1.Secret{}
+	exists := true
+
+	if err := p.Client.Get(ctx, p.Secret, secret); errors.IsNotFound(err) {
+		exists = false
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	changed := false
+
+	ca := &keyPair{Cert: secret.Data[CACertKey], Key: secret.Data[CAKeyKey]}
+	serving := &keyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+
+	var err error
+
+	if err = validCA(ca, now); err != nil {
+		p.Log.Info("generating a new webhook CA", "reason", err.Error())
+
+		if ca, err = newCA(p.Secret.Name + "-ca"); err != nil {
+			return nil, nil, err
+		}
+
+		changed = true
+	}
+
+	if err = validServingCert(ca, serving, p.DNSNames, now); err != nil {
+		p.Log.Info("generating a new webhook serving certificate", "reason", err.Error())
+
+		if serving, err = newServingCert(ca, p.DNSNames); err != nil {
+			return nil, nil, err
+		}
+
+		changed = true
+	}
+
+	if !changed {
+		return ca, serving, nil
+	}
+
+	secret.Name = p.Secret.Name
+	secret.Namespace = p.Secret.Namespace
+	secret.Type = corev1.SecretTypeTLS
+	secret.Data = map[string][]byte{
+		CACertKey:               ca.Cert,
+		CAKeyKey:                ca.Key,
+		corev1.TLSCertKey:       serving.Cert,
+		corev1.TLSPrivateKeyKey: serving.Key,
+	}
+
+	if exists {
+		err = p.Client.Update(ctx, secret)
+	} else {
+		err = p.Client.Create(ctx, secret)
+	}
+
+	return ca, serving, err
+}
+
+// writeCertDir writes the serving certificate into the certificate directory.
+// Files are replaced atomically, as the webhook server watches them for changes.
+func (p *Provisioner) writeCertDir(serving *keyPair) error {
+	if err := os.MkdirAll(p.CertDir, 0o700); err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		corev1.TLSCertKey:       serving.Cert,
+		corev1.TLSPrivateKeyKey: serving.Key,
+	}
+
+	for name, data := range files {
+		path := filepath.Join(p.CertDir, name)
+
+		if current, err := ioutil.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+
+		tmp := path + ".tmp"
+		if err := ioutil.WriteFile(tmp, data, 0o600); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Provisioner) injectCABundle(ctx context.Context, caBundle []byte) error {
+	for _, name := range p.MutatingWebhookConfigurations {
+		cfg := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := p.Client.Get(ctx, types.NamespacedName{Name: name}, cfg); err != nil {
+			return err
+		}
+
+		changed := false
+
+		for i := range cfg.Webhooks {
+			if !bytes.Equal(cfg.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				cfg.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := p.Client.Update(ctx, cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range p.ValidatingWebhookConfigurations {
+		cfg := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := p.Client.Get(ctx, types.NamespacedName{Name: name}, cfg); err != nil {
+			return err
+		}
+
+		changed := false
+
+		for i := range cfg.Webhooks {
+			if !bytes.Equal(cfg.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				cfg.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+
+		if changed {
+			if err := p.Client.Update(ctx, cfg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setupConversion points the conversion of the custom resource definitions to
+// the ConversionService and serves all their versions, as the versions other
+// than the storage one are shipped unserved until conversion is in place.
+func (p *Provisioner) setupConversion(ctx context.Context, caBundle []byte) error {
+	conversion := &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service:  p.ConversionService,
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+
+	for _, name := range p.CustomResourceDefinitions {
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := p.Client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return err
+		}
+
+		changed := !equality.Semantic.DeepEqual(crd.Spec.Conversion, conversion)
+		crd.Spec.Conversion = conversion.DeepCopy()
+
+		for i := range crd.Spec.Versions {
+			if !crd.Spec.Versions[i].Served {
+				crd.Spec.Versions[i].Served = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err := p.Client.Update(ctx, crd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Webhook certificates provisioner", func() {
+	var (
+		p      *Provisioner
+		c      client.Client
+		secret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		dir, err := ioutil.TempDir("", "webhook-certs")
+		Expect(err).NotTo(HaveOccurred())
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&admissionregistrationv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Webhooks:   []admissionregistrationv1.MutatingWebhook{{Name: "mtest.example.com"}},
+			},
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Webhooks:   []admissionregistrationv1.ValidatingWebhook{{Name: "vtest.example.com"}},
+			},
+			&apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "tests.example.com"},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{Name: "v1", Served: true, Storage: true},
+						{Name: "v2", Served: false},
+					},
+				},
+			},
+		).Build()
+
+		p = &Provisioner{
+			Client:                          c,
+			Log:                             logf.Log,
+			Secret:                          types.NamespacedName{Name: "webhook-certs", Namespace: "default"},
+			DNSNames:                        []string{"webhook.default.svc", "webhook.default.svc.cluster.local"},
+			CertDir:                         dir,
+			MutatingWebhookConfigurations:   []string{"test"},
+			ValidatingWebhookConfigurations: []string{"test"},
+			CustomResourceDefinitions:       []string{"tests.example.com"},
+			ConversionService: &apiextensionsv1.ServiceReference{
+				Name:      "webhook",
+				Namespace: "default",
+				Path:      pointer.StringPtr("/convert"),
+				Port:      pointer.Int32Ptr(443),
+			},
+		}
+		secret = &corev1.Secret{}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(p.CertDir)).To(Succeed())
+	})
+
+	It("should generate the certificates", func() {
+		Expect(p.Provision(context.TODO())).To(Succeed())
+
+		Expect(c.Get(context.TODO(), p.Secret, secret)).To(Succeed())
+		ca := &keyPair{Cert: secret.Data[CACertKey], Key: secret.Data[CAKeyKey]}
+		serving := &keyPair{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+		Expect(validCA(ca, time.Now())).To(Succeed())
+		Expect(validServingCert(ca, serving, p.DNSNames, time.Now())).To(Succeed())
+
+		data, err := ioutil.ReadFile(filepath.Join(p.CertDir, corev1.TLSCertKey))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(serving.Cert))
+
+		mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "test"}, mutating)).To(Succeed())
+		Expect(mutating.Webhooks[0].ClientConfig.CABundle).To(Equal(ca.Cert))
+
+		validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "test"}, validating)).To(Succeed())
+		Expect(validating.Webhooks[0].ClientConfig.CABundle).To(Equal(ca.Cert))
+
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: "tests.example.com"}, crd)).To(Succeed())
+		Expect(crd.Spec.Conversion.Strategy).To(Equal(apiextensionsv1.WebhookConverter))
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.Service).To(Equal(p.ConversionService))
+		Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(ca.Cert))
+		Expect(crd.Spec.Versions[0].Served).To(BeTrue())
+		Expect(crd.Spec.Versions[1].Served).To(BeTrue())
+	})
+
+	It("should keep valid certificates", func() {
+		Expect(p.Provision(context.TODO())).To(Succeed())
+		Expect(c.Get(context.TODO(), p.Secret, secret)).To(Succeed())
+		data := secret.DeepCopy().Data
+
+		Expect(p.Provision(context.TODO())).To(Succeed())
+		Expect(c.Get(context.TODO(), p.Secret, secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(data))
+	})
+
+	It("should renew the serving certificate when the dns names change, keeping the CA", func() {
+		Expect(p.Provision(context.TODO())).To(Succeed())
+		Expect(c.Get(context.TODO(), p.Secret, secret)).To(Succeed())
+		data := secret.DeepCopy().Data
+
+		p.DNSNames = append(p.DNSNames, "webhook.example.com")
+		Expect(p.Provision(context.TODO())).To(Succeed())
+		Expect(c.Get(context.TODO(), p.Secret, secret)).To(Succeed())
+		Expect(secret.Data[CACertKey]).To(Equal(data[CACertKey]))
+		Expect(secret.Data[corev1.TLSCertKey]).NotTo(Equal(data[corev1.TLSCertKey]))
+	})
+
+	It("should renew certificates which are about to expire", func() {
+		ca, err := newCA("test-ca")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(validCA(ca, time.Now())).To(Succeed())
+		Expect(validCA(ca, time.Now().Add(caValidity-renewBefore))).NotTo(Succeed())
+
+		serving, err := newServingCert(ca, p.DNSNames)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(validServingCert(ca, serving, p.DNSNames, time.Now())).To(Succeed())
+		Expect(validServingCert(ca, serving, p.DNSNames, time.Now().Add(certValidity-renewBefore))).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/bitpoke/wordpress-operator/pkg/cmd/options"
+	"github.com/bitpoke/wordpress-operator/pkg/webhook/certs"
+)
+
+// conversionCRDs are the custom resource definitions served by the conversion webhook.
+var conversionCRDs = []string{"wordpresses.wordpress.presslabs.org"}
+
+// AddToManagerFuncs is a list of functions to add all Webhooks to the Manager.
+var AddToManagerFuncs []func(manager.Manager) error
+
+// AddToManager adds all Webhooks to the Manager.
+func AddToManager(m manager.Manager) error {
+	for _, f := range AddToManagerFuncs {
+		if err := f(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;update;patch
+
+// SetupCertificates provisions the webhook server certificates and adds the
+// Provisioner to the Manager, to keep them up to date. It must be called
+// before starting the Manager, as the webhook server needs the certificates
+// in place in order to start.
+func SetupCertificates(ctx context.Context, m manager.Manager) error {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	if err := apiextensionsv1.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	c, err := client.New(m.GetConfig(), client.Options{Scheme: scheme, Mapper: m.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+
+	svc := fmt.Sprintf("%s.%s.svc", options.WebhookServiceName, options.Namespace)
+
+	p := &certs.Provisioner{
+		Client:                          c,
+		Log:                             logf.Log.WithName("webhook-certs"),
+		Secret:                          client.ObjectKey{Name: options.WebhookSecretName, Namespace: options.Namespace},
+		DNSNames:                        []string{svc, svc + ".cluster.local"},
+		CertDir:                         options.WebhookCertDir,
+		MutatingWebhookConfigurations:   []string{options.WebhookConfigurationName},
+		ValidatingWebhookConfigurations: []string{options.WebhookConfigurationName},
+		CustomResourceDefinitions:       conversionCRDs,
+		ConversionService: &apiextensionsv1.ServiceReference{
+			Name:      options.WebhookServiceName,
+			Namespace: options.Namespace,
+			Path:      pointer.StringPtr(conversionPath),
+			Port:      pointer.Int32Ptr(443),
+		},
+	}
+
+	if err = p.Provision(ctx); err != nil {
+		return err
+	}
+
+	return m.Add(p)
+}
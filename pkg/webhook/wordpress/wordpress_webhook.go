@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/wordpress"
+)
+
+const (
+	mutatePath   = "/mutate-wordpress-presslabs-org-v1alpha1-wordpress"
+	validatePath = "/validate-wordpress-presslabs-org-v1alpha1-wordpress"
+)
+
+// Add registers the Wordpress webhooks within the Manager's webhook server.
+func Add(mgr manager.Manager) error {
+	srv := mgr.GetWebhookServer()
+	srv.Register(mutatePath, &webhook.Admission{Handler: &defaulter{scheme: mgr.GetScheme()}})
+	srv.Register(validatePath, &webhook.Admission{Handler: &validator{client: mgr.GetClient()}})
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/mutate-wordpress-presslabs-org-v1alpha1-wordpress,mutating=true,failurePolicy=fail,sideEffects=None,groups=wordpress.presslabs.org,resources=wordpresses,verbs=create;update,versions=v1alpha1,name=mwordpress.presslabs.org,admissionReviewVersions=v1
+
+// defaulter sets the Wordpress defaults at admission time.
+type defaulter struct {
+	scheme  *runtime.Scheme
+	decoder *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &defaulter{}
+
+func (d *defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	wp := &wordpressv1alpha1.Wordpress{}
+	if err := d.decoder.Decode(req, wp); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	d.scheme.Default(wp)
+	wordpress.New(wp).SetDefaults()
+
+	marshaled, err := json.Marshal(wp)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+func (d *defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-wordpress-presslabs-org-v1alpha1-wordpress,mutating=false,failurePolicy=fail,sideEffects=None,groups=wordpress.presslabs.org,resources=wordpresses,verbs=create;update,versions=v1alpha1,name=vwordpress.presslabs.org,admissionReviewVersions=v1
+
+// validator rejects invalid Wordpress specs and routes already claimed by
+// other sites.
+type validator struct {
+	client  client.Client
+	decoder *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &validator{}
+
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	wp := wordpress.New(&wordpressv1alpha1.Wordpress{})
+	if err := v.decoder.Decode(req, wp.Unwrap()); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var errs field.ErrorList
+
+	old := wordpress.New(&wordpressv1alpha1.Wordpress{})
+
+	switch req.Operation {
+	case admissionv1.Create:
+		errs = wp.Validate()
+	case admissionv1.Update:
+		if err := v.decoder.DecodeRaw(req.OldObject, old.Unwrap()); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		// don't block metadata updates (eg. removing finalizers) of sites
+		// created before the validation was in place
+		if wp.DeletionTimestamp != nil || equality.Semantic.DeepEqual(wp.Spec, old.Spec) {
+			return admission.Allowed("")
+		}
+
+		errs = wp.ValidateUpdate(old)
+	default:
+		return admission.Allowed("")
+	}
+
+	claimErrs, err := v.validateRouteClaims(ctx, wp, old)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	errs = append(errs, claimErrs...)
+
+	if len(errs) > 0 {
+		return invalid(wp, errs)
+	}
+
+	return admission.Allowed("")
+}
+
+// validateRouteClaims rejects the routes added to the site which are already
+// claimed by other sites.
+func (v *validator) validateRouteClaims(ctx context.Context, wp, old *wordpress.Wordpress) (field.ErrorList, error) {
+	sites := &wordpressv1alpha1.WordpressList{}
+	if err := v.client.List(ctx, sites); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]string{}
+
+	for i := range sites.Items {
+		site := wordpress.New(&sites.Items[i])
+		if site.DeletionTimestamp != nil || (site.Namespace == wp.Namespace && site.Name == wp.Name) {
+			continue
+		}
+
+		for _, key := range site.RouteKeys() {
+			claims[key] = site.Namespace + "/" + site.Name
+		}
+	}
+
+	existing := map[string]bool{}
+	for _, key := range old.RouteKeys() {
+		existing[key] = true
+	}
+
+	allErrs := field.ErrorList{}
+	routesPath := field.NewPath("spec", "routes")
+
+	for i, key := range wp.RouteKeys() {
+		if owner, claimed := claims[key]; claimed && !existing[key] {
+			allErrs = append(allErrs, field.Forbidden(routesPath.Index(i), fmt.Sprintf("route %s is already claimed by %s", key, owner)))
+		}
+	}
+
+	return allErrs, nil
+}
+
+func (v *validator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+
+	return nil
+}
+
+// invalid returns a response which denies the request with the same status
+// the API server returns for invalid objects.
+func invalid(wp *wordpress.Wordpress, errs field.ErrorList) admission.Response {
+	status := apierrors.NewInvalid(wordpressv1alpha1.SchemeGroupVersion.WithKind("Wordpress").GroupKind(), wp.Name, errs).ErrStatus
+
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		},
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2021 Pressinfra SRL.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wordpress
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	wordpressv1alpha1 "github.com/bitpoke/wordpress-operator/pkg/apis/wordpress/v1alpha1"
+	"github.com/bitpoke/wordpress-operator/pkg/internal/testutil"
+)
+
+var _ = Describe("Wordpress webhook", func() {
+	var (
+		ctx     context.Context
+		c       client.Client
+		decoder *admission.Decoder
+		wp      *wordpressv1alpha1.Wordpress
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		ctx = context.Background()
+
+		c = testutil.NewFakeClient(&wordpressv1alpha1.Wordpress{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "claimed.com"}},
+			},
+		})
+
+		decoder, err = admission.NewDecoder(c.Scheme())
+		Expect(err).NotTo(HaveOccurred())
+
+		wp = &wordpressv1alpha1.Wordpress{
+			TypeMeta:   metav1.TypeMeta{APIVersion: wordpressv1alpha1.SchemeGroupVersion.String(), Kind: "Wordpress"},
+			ObjectMeta: metav1.ObjectMeta{Name: "site", Namespace: "default"},
+			Spec: wordpressv1alpha1.WordpressSpec{
+				Routes: []wordpressv1alpha1.RouteSpec{{Domain: "example.com"}},
+			},
+		}
+	})
+
+	raw := func(obj *wordpressv1alpha1.Wordpress) runtime.RawExtension {
+		data, err := json.Marshal(obj)
+		Expect(err).NotTo(HaveOccurred())
+
+		return runtime.RawExtension{Raw: data}
+	}
+
+	request := func(op admissionv1.Operation, obj, old *wordpressv1alpha1.Wordpress) admission.Request {
+		req := admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: op,
+				Name:      obj.Name,
+				Namespace: obj.Namespace,
+				Object:    raw(obj),
+			},
+		}
+
+		if old != nil {
+			req.OldObject = raw(old)
+		}
+
+		return req
+	}
+
+	patchPaths := func(resp admission.Response) []string {
+		paths := []string{}
+		for _, p := range resp.Patches {
+			paths = append(paths, p.Path)
+		}
+
+		return paths
+	}
+
+	Describe("defaulter", func() {
+		var d *defaulter
+
+		BeforeEach(func() {
+			d = &defaulter{scheme: c.Scheme()}
+			Expect(d.InjectDecoder(decoder)).To(Succeed())
+		})
+
+		It("sets the defaults of created sites", func() {
+			resp := d.Handle(ctx, request(admissionv1.Create, wp, nil))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(patchPaths(resp)).To(ContainElements("/spec/image", "/spec/imagePullPolicy"))
+		})
+
+		It("sets the defaults of updated sites", func() {
+			old := wp.DeepCopy()
+			wp.Spec.Image = "docker.io/bitpoke/wordpress-runtime:custom"
+
+			resp := d.Handle(ctx, request(admissionv1.Update, wp, old))
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(patchPaths(resp)).To(ContainElement("/spec/imagePullPolicy"))
+			Expect(patchPaths(resp)).NotTo(ContainElement("/spec/image"))
+		})
+
+		It("rejects objects which can't be decoded", func() {
+			req := request(admissionv1.Create, wp, nil)
+			req.Object = runtime.RawExtension{Raw: []byte("{")}
+
+			resp := d.Handle(ctx, req)
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+	})
+
+	Describe("validator", func() {
+		var v *validator
+
+		BeforeEach(func() {
+			v = &validator{client: c}
+			Expect(v.InjectDecoder(decoder)).To(Succeed())
+		})
+
+		It("allows valid sites to be created", func() {
+			resp := v.Handle(ctx, request(admissionv1.Create, wp, nil))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("denies the creation of invalid sites", func() {
+			wp.Spec.Routes = []wordpressv1alpha1.RouteSpec{{Domain: "not a domain"}}
+
+			resp := v.Handle(ctx, request(admissionv1.Create, wp, nil))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(BeEquivalentTo(http.StatusUnprocessableEntity))
+			Expect(resp.Result.Reason).To(Equal(metav1.StatusReasonInvalid))
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.routes[0].domain"))
+		})
+
+		It("denies the creation of sites claiming routes of other sites", func() {
+			wp.Spec.Routes = append(wp.Spec.Routes, wordpressv1alpha1.RouteSpec{Domain: "claimed.com"})
+
+			resp := v.Handle(ctx, request(admissionv1.Create, wp, nil))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes).To(HaveLen(1))
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.routes[1]"))
+			Expect(resp.Result.Details.Causes[0].Message).To(ContainSubstring("default/other"))
+		})
+
+		It("denies updates changing immutable fields", func() {
+			wp.Spec.CodeVolumeSpec = &wordpressv1alpha1.CodeVolumeSpec{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{StorageClassName: pointerTo("standard")},
+			}
+			old := wp.DeepCopy()
+			wp.Spec.CodeVolumeSpec.PersistentVolumeClaim.StorageClassName = pointerTo("fast")
+
+			resp := v.Handle(ctx, request(admissionv1.Update, wp, old))
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Details.Causes[0].Field).To(Equal("spec.code.persistentVolumeClaim"))
+		})
+
+		It("allows updates keeping routes claimed before the validation", func() {
+			wp.Spec.Routes = append(wp.Spec.Routes, wordpressv1alpha1.RouteSpec{Domain: "claimed.com"})
+			old := wp.DeepCopy()
+			wp.Spec.Image = "docker.io/bitpoke/wordpress-runtime:custom"
+
+			resp := v.Handle(ctx, request(admissionv1.Update, wp, old))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+
+		It("allows metadata updates of invalid sites", func() {
+			wp.Spec.Routes = []wordpressv1alpha1.RouteSpec{{Domain: "not a domain"}}
+			old := wp.DeepCopy()
+			wp.Finalizers = []string{"example.com/finalizer"}
+
+			resp := v.Handle(ctx, request(admissionv1.Update, wp, old))
+			Expect(resp.Allowed).To(BeTrue())
+		})
+	})
+})
+
+func pointerTo(s string) *string {
+	return &s
+}